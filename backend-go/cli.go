@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"gorm.io/gorm"
+
+	"fullstack-crud-project-01/backend-go/config"
+	"fullstack-crud-project-01/backend-go/logging"
+	"fullstack-crud-project-01/backend-go/repositories"
+	"fullstack-crud-project-01/backend-go/services"
+	"fullstack-crud-project-01/backend-go/utils"
+)
+
+// errUsage menandai argumen CLI yang salah (exit code 2)
+var errUsage = errors.New("argumen tidak valid")
+
+// cli menyimpan tujuan output agar subcommand bisa dites tanpa os.Stdout
+type cli struct {
+	out        io.Writer
+	errOut     io.Writer
+	configPath string         // -config: file YAML/TOML opsional
+	cfg        *config.Config // dimuat saat pertama dibutuhkan
+}
+
+// cliCommand adalah satu subcommand tingkat atas
+type cliCommand struct {
+	name    string
+	usage   string
+	summary string
+	run     func(c *cli, ctx context.Context, args []string) error
+}
+
+// cliCommands adalah daftar subcommand yang dikenali, sesuai urutan di bantuan
+var cliCommands = []cliCommand{
+	{"serve", "serve [-addr :8080]", "menjalankan HTTP API (default tanpa argumen)", (*cli).serve},
+	{"migrate", "migrate up|down [-steps N]|status|goto VERSION|baseline VERSION", "mengelola migrasi skema database", (*cli).migrate},
+	{"seed", "seed -file products.yaml [-skip-existing]", "mengisi produk dari file fixture YAML/JSON", (*cli).seed},
+	{"user", "user create|activate|set-role|reset-password -email EMAIL ...", "administrasi akun pengguna", (*cli).user},
+	{"token", "token mint -email EMAIL [-ttl 1h] [-role ROLE]", "menerbitkan JWT untuk debugging", (*cli).token},
+}
+
+// runCLI menjalankan subcommand dari args dan mengembalikan exit code proses.
+// Opsi global -config FILE harus ditulis sebelum subcommand.
+func runCLI(args []string, stdout, stderr io.Writer) int {
+	c := &cli{out: stdout, errOut: stderr}
+
+	global := c.flagSet("backend-go")
+	global.StringVar(&c.configPath, "config", "", "file konfigurasi YAML/TOML (default: CONFIG_FILE)")
+	global.Usage = func() { c.printUsage(stderr) }
+	if err := global.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	args = global.Args()
+
+	if len(args) == 0 {
+		args = []string{"serve"}
+	}
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		c.printUsage(stdout)
+		return 0
+	}
+
+	for _, cmd := range cliCommands {
+		if cmd.name != args[0] {
+			continue
+		}
+		err := cmd.run(c, context.Background(), args[1:])
+		switch {
+		case err == nil, errors.Is(err, flag.ErrHelp):
+			return 0
+		case errors.Is(err, errUsage):
+			fmt.Fprintf(stderr, "%v\npenggunaan: %s\n", err, cmd.usage)
+			return 2
+		default:
+			fmt.Fprintf(stderr, "error: %v\n", err)
+			return 1
+		}
+	}
+
+	fmt.Fprintf(stderr, "subcommand tidak dikenal: %s\n\n", args[0])
+	c.printUsage(stderr)
+	return 2
+}
+
+// printUsage menampilkan daftar subcommand
+func (c *cli) printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Penggunaan: backend-go [-config FILE] <subcommand> [opsi]")
+	fmt.Fprintln(w)
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, cmd := range cliCommands {
+		fmt.Fprintf(tw, "  %s\t%s\n", cmd.usage, cmd.summary)
+	}
+	tw.Flush()
+}
+
+// flagSet membuat FlagSet yang menulis pesan error ke stderr CLI
+func (c *cli) flagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.errOut)
+	return fs
+}
+
+// serve menjalankan HTTP API
+func (c *cli) serve(ctx context.Context, args []string) error {
+	cfg, err := c.config()
+	if err != nil {
+		return err
+	}
+	return runServe(cfg, args)
+}
+
+// config memuat dan memvalidasi konfigurasi sekali, lalu mengatur JWT dari konfigurasi tersebut
+func (c *cli) config() (*config.Config, error) {
+	if c.cfg != nil {
+		return c.cfg, nil
+	}
+	cfg, err := config.Load(c.configPath)
+	if err != nil {
+		return nil, err
+	}
+	logging.Setup(cfg.Log.Level, cfg.Log.Format)
+	utils.ConfigureJWT(cfg.JWT.Secret, cfg.JWT.TTL)
+	if err := utils.ConfigureJWTKeys(cfg.JWT.SigningKeyFile, cfg.JWT.VerificationKeyFiles); err != nil {
+		return nil, err
+	}
+	c.cfg = cfg
+	return cfg, nil
+}
+
+// openDatabase membuka koneksi database dari konfigurasi yang sama dengan server
+func (c *cli) openDatabase() (*gorm.DB, error) {
+	cfg, err := c.config()
+	if err != nil {
+		return nil, err
+	}
+	return config.Open(cfg.Database)
+}
+
+// auditContext menandai perubahan dari CLI di audit log
+func (c *cli) auditContext(ctx context.Context) context.Context {
+	return services.WithAuditActor(ctx, services.AuditActor{Email: "cli", UserAgent: "backend-go-cli"})
+}
+
+// newAuthService menyusun AuthService dengan audit, sama seperti di server
+func (c *cli) newAuthService(db *gorm.DB) *services.AuthService {
+	authService := services.NewAuthService(repositories.NewUserRepository(db))
+	authService.Audit = services.NewAuditService(repositories.NewAuditRepository(db))
+	return authService
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"text/tabwriter"
+
+	"fullstack-crud-project-01/backend-go/database"
+)
+
+// migrate menjalankan subcommand migrate up|down|status|goto|baseline
+func (c *cli) migrate(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: aksi migrate wajib diisi", errUsage)
+	}
+	action, args := args[0], args[1:]
+
+	fs := c.flagSet("migrate " + action)
+	steps := fs.Int("steps", 1, "jumlah migrasi yang dibatalkan (untuk down)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	// Validasi argumen sebelum membuka koneksi database
+	var version uint
+	switch action {
+	case "up", "status":
+	case "down":
+		if *steps < 1 {
+			return fmt.Errorf("%w: -steps minimal 1", errUsage)
+		}
+	case "goto", "baseline":
+		var err error
+		if version, err = parseVersionArg(fs.Args()); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%w: aksi migrate tidak dikenal: %s", errUsage, action)
+	}
+
+	db, err := c.openDatabase()
+	if err != nil {
+		return err
+	}
+	migrations, err := database.EmbeddedMigrations(db.Dialector.Name())
+	if err != nil {
+		return err
+	}
+	migrator := database.NewMigrator(db, migrations)
+
+	var ran []database.Migration
+	switch action {
+	case "up":
+		// Sama seperti saat server start: database lama (AutoMigrate) di-baseline dulu
+		var baselined bool
+		if baselined, err = migrator.BaselineLegacy(ctx); err != nil {
+			return err
+		}
+		if baselined {
+			fmt.Fprintf(c.out, "Database lama terdeteksi, migrasi sampai versi %d ditandai sudah diterapkan\n", database.LegacyBaselineVersion)
+		}
+		ran, err = migrator.Up(ctx)
+	case "down":
+		ran, err = migrator.Down(ctx, *steps)
+	case "goto":
+		ran, err = migrator.Goto(ctx, version)
+	case "baseline":
+		if err := migrator.Baseline(ctx, version); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.out, "Migrasi sampai versi %d ditandai sudah diterapkan\n", version)
+		return nil
+	case "status":
+		return c.printMigrationStatus(ctx, migrator)
+	}
+
+	// Tampilkan migrasi yang sempat dijalankan walaupun terjadi error di tengah
+	for _, m := range ran {
+		fmt.Fprintf(c.out, "%s %06d_%s\n", action, m.Version, m.Name)
+	}
+	if err != nil {
+		return err
+	}
+	if len(ran) == 0 {
+		fmt.Fprintln(c.out, "Tidak ada migrasi yang perlu dijalankan")
+	}
+	return nil
+}
+
+// parseVersionArg membaca satu argumen posisi VERSION
+func parseVersionArg(args []string) (uint, error) {
+	if len(args) != 1 {
+		return 0, fmt.Errorf("%w: VERSION wajib diisi", errUsage)
+	}
+	version, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: VERSION harus berupa angka", errUsage)
+	}
+	return uint(version), nil
+}
+
+// printMigrationStatus menampilkan tabel status migrasi
+func (c *cli) printMigrationStatus(ctx context.Context, migrator *database.Migrator) error {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(c.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, s := range statuses {
+		status, appliedAt := "pending", "-"
+		switch {
+		case s.Missing:
+			status = "missing"
+		case s.Drift:
+			status = "drift"
+		case s.Applied:
+			status = "applied"
+		}
+		if s.AppliedAt != nil {
+			appliedAt = s.AppliedAt.Local().Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(tw, "%06d\t%s\t%s\t%s\n", s.Version, s.Name, status, appliedAt)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/repositories"
+	"fullstack-crud-project-01/backend-go/services"
+)
+
+// seedFile adalah format file fixture untuk subcommand seed
+type seedFile struct {
+	Products []productFixture `json:"products" yaml:"products"`
+}
+
+// productFixture adalah satu produk di file fixture
+type productFixture struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description" yaml:"description"`
+	Price       int    `json:"price" yaml:"price"`
+}
+
+// seed mengisi produk dari file fixture melalui ProductService, sehingga validasi,
+// audit log dan event outbox sama seperti produk yang dibuat lewat API
+func (c *cli) seed(ctx context.Context, args []string) error {
+	fs := c.flagSet("seed")
+	file := fs.String("file", "", "file fixture .yaml, .yml atau .json")
+	skipExisting := fs.Bool("skip-existing", false, "lewati produk yang namanya sudah ada")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return fmt.Errorf("%w: -file wajib diisi", errUsage)
+	}
+
+	fixtures, err := loadSeedFile(*file)
+	if err != nil {
+		return err
+	}
+
+	db, err := c.openDatabase()
+	if err != nil {
+		return err
+	}
+	productService := services.NewProductService(repositories.NewProductRepository(db))
+	productService.Tx = repositories.NewTxManager(db)
+
+	existing := make(map[string]bool)
+	if *skipExisting {
+		products, err := productService.ReadAllProducts(ctx)
+		if err != nil {
+			return err
+		}
+		for _, p := range products {
+			existing[p.Name] = true
+		}
+	}
+
+	ctx = c.auditContext(ctx)
+	created, skipped := 0, 0
+	for i, f := range fixtures.Products {
+		if existing[f.Name] {
+			skipped++
+			continue
+		}
+		product := &models.Product{Name: f.Name, Description: f.Description, Price: f.Price}
+		if err := productService.CreateProduct(ctx, product); err != nil {
+			return fmt.Errorf("produk ke-%d (%q): %w", i+1, f.Name, err)
+		}
+		existing[f.Name] = true
+		created++
+	}
+
+	fmt.Fprintf(c.out, "%d produk ditambahkan, %d dilewati\n", created, skipped)
+	return nil
+}
+
+// loadSeedFile membaca file fixture; format ditentukan dari ekstensi file
+func loadSeedFile(path string) (*seedFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var fixtures seedFile
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &fixtures)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &fixtures)
+	default:
+		return nil, fmt.Errorf("%w: format fixture harus .yaml, .yml atau .json", errUsage)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca fixture %s: %w", path, err)
+	}
+	return &fixtures, nil
+}
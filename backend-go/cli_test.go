@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/repositories"
+	"fullstack-crud-project-01/backend-go/services"
+	"fullstack-crud-project-01/backend-go/utils"
+)
+
+// setupCLI mengarahkan CLI ke database SQLite sementara yang sudah dimigrasi
+func setupCLI(t *testing.T) {
+	t.Setenv("DB_DRIVER", "sqlite")
+	t.Setenv("DB_NAME", filepath.Join(t.TempDir(), "cli.db"))
+	t.Setenv("JWT_SECRET_KEY", "cli-test-secret")
+	runOK(t, "migrate", "up")
+}
+
+// runOK menjalankan CLI dan memastikan exit code 0
+func runOK(t *testing.T, args ...string) string {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := runCLI(args, &stdout, &stderr)
+	require.Equal(t, 0, code, "args=%v stderr=%s", args, stderr.String())
+	return stdout.String()
+}
+
+func TestCLI_Migrate(t *testing.T) {
+	setupCLI(t)
+
+	out := runOK(t, "migrate", "status")
+	assert.Contains(t, out, "000012")
+	assert.NotContains(t, out, "pending")
+
+	out = runOK(t, "migrate", "down", "-steps", "1")
+	assert.Contains(t, out, "down 000012_add_retry_to_outbox_messages")
+	assert.Contains(t, runOK(t, "migrate", "status"), "pending")
+
+	assert.Contains(t, runOK(t, "migrate", "up"), "up 000012_add_retry_to_outbox_messages")
+	assert.Contains(t, runOK(t, "migrate", "up"), "Tidak ada migrasi")
+}
+
+func TestCLI_UserAndToken(t *testing.T) {
+	setupCLI(t)
+
+	out := runOK(t, "user", "create", "-email", "admin@cli.test", "-password", "rahasia123", "-name", "Admin", "-role", "admin", "-active")
+	assert.Contains(t, out, "role=admin, aktif=true")
+
+	token := strings.TrimSpace(runOK(t, "token", "mint", "-email", "admin@cli.test", "-ttl", "5m"))
+	claims, err := utils.ValidateToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, "admin@cli.test", claims.Email)
+	assert.Equal(t, models.RoleAdmin, claims.Role)
+
+	// Token dari CLI terikat sesi sehingga bisa dicabut
+	require.NotEmpty(t, claims.ID)
+	db, err := (&cli{}).openDatabase()
+	require.NoError(t, err)
+	sessions := services.NewSessionService(repositories.NewSessionRepository(db))
+	require.NoError(t, sessions.Validate(context.Background(), claims))
+	require.NoError(t, sessions.RevokeAll(context.Background(), claims.UserID, ""))
+	assert.ErrorIs(t, sessions.Validate(context.Background(), claims), models.ErrSessionRevoked)
+
+	for _, args := range [][]string{
+		{"-role", "superadmin"},
+		{"-ttl", "0s"},
+		{"-ttl", "720h"},
+	} {
+		var stdout, stderr bytes.Buffer
+		code := runCLI(append([]string{"token", "mint", "-email", "admin@cli.test"}, args...), &stdout, &stderr)
+		assert.NotEqual(t, 0, code, "args=%v", args)
+		assert.Empty(t, stdout.String(), "args=%v", args)
+	}
+
+	assert.Contains(t, runOK(t, "user", "set-role", "-email", "admin@cli.test", "-role", "user"), "sekarang user")
+	out = runOK(t, "user", "reset-password", "-email", "admin@cli.test")
+	assert.Contains(t, out, "Password baru: ")
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"user", "activate", "-email", "tidakada@cli.test"}, &stdout, &stderr)
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr.String(), "tidak ditemukan")
+}
+
+func TestCLI_Seed(t *testing.T) {
+	setupCLI(t)
+
+	fixture := filepath.Join(t.TempDir(), "products.json")
+	require.NoError(t, os.WriteFile(fixture, []byte(`{"products":[{"name":"A","price":10},{"name":"B","price":20}]}`), 0o644))
+
+	assert.Contains(t, runOK(t, "seed", "-file", fixture), "2 produk ditambahkan, 0 dilewati")
+	assert.Contains(t, runOK(t, "seed", "-file", fixture, "-skip-existing"), "0 produk ditambahkan, 2 dilewati")
+	assert.Contains(t, runOK(t, "seed", "-file", "database/seeds/products.yaml"), "3 produk ditambahkan")
+}
+
+func TestCLI_Usage(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		code int
+	}{
+		{"Help", []string{"help"}, 0},
+		{"UnknownCommand", []string{"bogus"}, 2},
+		{"MigrateWithoutAction", []string{"migrate"}, 2},
+		{"UserWithoutEmail", []string{"user", "activate"}, 2},
+		{"GotoWithoutVersion", []string{"migrate", "goto"}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DB_DRIVER", "sqlite")
+			t.Setenv("DB_NAME", filepath.Join(t.TempDir(), "cli.db"))
+			var stdout, stderr bytes.Buffer
+			assert.Equal(t, tt.code, runCLI(tt.args, &stdout, &stderr))
+		})
+	}
+}
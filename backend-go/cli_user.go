@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/repositories"
+	"fullstack-crud-project-01/backend-go/services"
+	"fullstack-crud-project-01/backend-go/utils"
+)
+
+// user menjalankan subcommand user create|activate|set-role|reset-password
+func (c *cli) user(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: aksi user wajib diisi", errUsage)
+	}
+	action, args := args[0], args[1:]
+
+	fs := c.flagSet("user " + action)
+	email := fs.String("email", "", "email pengguna")
+	password := fs.String("password", "", "password (reset-password: kosong = dibuat acak)")
+	name := fs.String("name", "", "nama pengguna (create)")
+	role := fs.String("role", models.RoleUser, "peran: user atau admin")
+	active := fs.Bool("active", false, "langsung aktifkan akun (create)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *email == "" {
+		return fmt.Errorf("%w: -email wajib diisi", errUsage)
+	}
+
+	db, err := c.openDatabase()
+	if err != nil {
+		return err
+	}
+	authService := c.newAuthService(db)
+	ctx = c.auditContext(ctx)
+
+	var user *models.User
+	switch action {
+	case "create":
+		if !models.IsValidRole(*role) {
+			return models.ErrInvalidRole
+		}
+		if user, err = authService.Register(ctx, *email, *password, *name); err != nil {
+			return err
+		}
+		if *role != models.RoleUser {
+			if user, err = authService.SetRole(ctx, *email, *role); err != nil {
+				return err
+			}
+		}
+		if *active {
+			if user, err = authService.ActivateByEmail(ctx, *email); err != nil {
+				return err
+			}
+		}
+		fmt.Fprintf(c.out, "Pengguna #%d (%s) dibuat, role=%s, aktif=%t\n", user.ID, user.Email, user.Role, user.IsActive)
+
+	case "activate":
+		if user, err = authService.ActivateByEmail(ctx, *email); err != nil {
+			return userError(err, *email)
+		}
+		fmt.Fprintf(c.out, "Pengguna %s diaktifkan\n", user.Email)
+
+	case "set-role":
+		if user, err = authService.SetRole(ctx, *email, *role); err != nil {
+			return userError(err, *email)
+		}
+		fmt.Fprintf(c.out, "Role %s sekarang %s\n", user.Email, user.Role)
+
+	case "reset-password":
+		newPassword := *password
+		if newPassword == "" {
+			if newPassword, err = randomPassword(); err != nil {
+				return err
+			}
+		}
+		if user, err = authService.ResetPassword(ctx, *email, newPassword); err != nil {
+			return userError(err, *email)
+		}
+		fmt.Fprintf(c.out, "Password %s direset\n", user.Email)
+		if *password == "" {
+			fmt.Fprintf(c.out, "Password baru: %s\n", newPassword)
+		}
+
+	default:
+		return fmt.Errorf("%w: aksi user tidak dikenal: %s", errUsage, action)
+	}
+	return nil
+}
+
+// maxMintTTL membatasi masa berlaku token dari `token mint`
+const maxMintTTL = 24 * time.Hour
+
+// token menjalankan subcommand token mint. Token terikat sesi baru sehingga bisa dicabut
+// seperti sesi login biasa (DELETE /auth/sessions/:id).
+func (c *cli) token(ctx context.Context, args []string) error {
+	if len(args) == 0 || args[0] != "mint" {
+		return fmt.Errorf("%w: aksi token wajib mint", errUsage)
+	}
+
+	fs := c.flagSet("token mint")
+	email := fs.String("email", "", "email pengguna pemilik token")
+	ttl := fs.Duration("ttl", time.Hour, "masa berlaku token (maksimal 24h)")
+	role := fs.String("role", "", "override role di token (default: role pengguna)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if *email == "" {
+		return fmt.Errorf("%w: -email wajib diisi", errUsage)
+	}
+	if *ttl <= 0 || *ttl > maxMintTTL {
+		return fmt.Errorf("%w: -ttl harus di antara 0 dan %s", errUsage, maxMintTTL)
+	}
+	if *role != "" && !models.IsValidRole(*role) {
+		return models.ErrInvalidRole
+	}
+
+	db, err := c.openDatabase()
+	if err != nil {
+		return err
+	}
+	user, err := repositories.NewUserRepository(db).FindByEmail(ctx, *email)
+	if err != nil {
+		return userError(err, *email)
+	}
+	if !user.IsActive {
+		fmt.Fprintf(c.errOut, "Peringatan: akun %s belum aktif\n", user.Email)
+	}
+
+	session, err := services.NewSessionService(repositories.NewSessionRepository(db)).StartWithTTL(c.auditContext(ctx), user, *ttl)
+	if err != nil {
+		return err
+	}
+	tokenRole := user.Role
+	if *role != "" {
+		tokenRole = *role
+	}
+	token, err := utils.GenerateSessionTokenWithTTL(user.ID, user.Email, tokenRole, session.TokenID, *ttl)
+	if err != nil {
+		return err
+	}
+	// Hanya token yang ditulis ke stdout agar mudah dipakai di script
+	fmt.Fprintln(c.out, token)
+	fmt.Fprintf(c.errOut, "Sesi %d dibuat untuk %s, berlaku sampai %s\n", session.ID, user.Email, session.ExpiresAt.Format(time.RFC3339))
+	return nil
+}
+
+// userError memperjelas error pengguna yang tidak ditemukan
+func userError(err error, email string) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("pengguna %s tidak ditemukan", email)
+	}
+	return err
+}
+
+// randomPassword membuat password acak 16 karakter
+func randomPassword() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
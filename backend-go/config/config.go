@@ -1,12 +1,311 @@
 package config
 
 import (
-	"log"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+
+	"fullstack-crud-project-01/backend-go/logging"
+	"fullstack-crud-project-01/backend-go/ratelimit"
+)
+
+// Config adalah seluruh konfigurasi aplikasi yang dibaca sekali saat startup.
+type Config struct {
+	Server    ServerConfig
+	Database  DatabaseConfig
+	JWT       JWTConfig
+	Log       LogConfig
+	Tracing   TracingConfig
+	RateLimit RateLimitConfig
+	MFA       MFAConfig
+	OIDC      OIDCConfig
+	Account   AccountConfig
+	DemoMode  bool // DEMO_MODE: produk disimpan di memori
+}
+
+// ServerConfig mengatur HTTP server.
+type ServerConfig struct {
+	Addr           string   // SERVER_ADDR (atau PORT), default ":8080"
+	AllowedOrigins []string // CORS_ALLOWED_ORIGINS, dipisah koma; dipakai CORS dan WebSocket
+	TrustedProxies []string // SERVER_TRUSTED_PROXIES: IP/CIDR proxy yang boleh mengisi X-Forwarded-For; kosong = tidak ada
+
+	ReadTimeout       time.Duration // SERVER_READ_TIMEOUT: batas membaca seluruh request, default 15s
+	ReadHeaderTimeout time.Duration // SERVER_READ_HEADER_TIMEOUT: batas membaca header (anti slowloris), default 5s
+	WriteTimeout      time.Duration // SERVER_WRITE_TIMEOUT: batas menulis response, default 30s (SSE dikecualikan)
+	IdleTimeout       time.Duration // SERVER_IDLE_TIMEOUT: koneksi keep-alive idle, default 120s
+	ShutdownTimeout   time.Duration // SERVER_SHUTDOWN_TIMEOUT: batas menunggu request berjalan saat shutdown, default 20s
+	ShutdownDelay     time.Duration // SERVER_SHUTDOWN_DELAY: jeda /readyz 503 sebelum listener ditutup, default 5s; 0 = tanpa jeda
+}
+
+// DatabaseConfig mengatur koneksi database.
+type DatabaseConfig struct {
+	Driver   string // DB_DRIVER: mysql (default), postgres atau sqlite
+	Host     string // DB_HOST
+	Port     string // DB_PORT
+	User     string // DB_USER
+	Password string // DB_PASSWORD atau DB_PASSWORD_FILE
+	Name     string // DB_NAME; untuk sqlite berupa path file
+	SSLMode  string // DB_SSLMODE (postgres), default "disable"
+}
+
+// JWTConfig mengatur penerbitan dan validasi token.
+type JWTConfig struct {
+	Secret string        // JWT_SECRET_KEY atau JWT_SECRET_KEY_FILE
+	TTL    time.Duration // JWT_TTL, default 1h
+
+	// JWT_SIGNING_KEY_FILE: kunci privat PEM (RSA → RS256, Ed25519 → EdDSA). Jika diisi,
+	// token ditandatangani dengan kunci ini dan Secret hanya dipakai untuk token HS256 lama.
+	SigningKeyFile string
+	// JWT_VERIFICATION_KEY_FILES: kunci publik PEM lain (dipisah koma) yang tetap diterima
+	// dan dipublikasikan di JWKS selama rotasi
+	VerificationKeyFiles []string
+}
+
+// LogConfig mengatur logger terstruktur.
+type LogConfig struct {
+	Level  slog.Level // LOG_LEVEL: debug, info (default), warn atau error
+	Format string     // LOG_FORMAT: json (default) atau text
+}
+
+// Exporter tracing yang didukung (nilai TRACING_EXPORTER)
+const (
+	TracingNone   = "none"
+	TracingStdout = "stdout"
+	TracingFile   = "file"
+	TracingOTLP   = "otlp"
 )
 
+// TracingConfig mengatur OpenTelemetry tracing.
+type TracingConfig struct {
+	Exporter     string  // TRACING_EXPORTER: none (default), stdout, file atau otlp
+	File         string  // TRACING_FILE: tujuan exporter file, default traces.jsonl
+	OTLPEndpoint string  // TRACING_OTLP_ENDPOINT: URL collector OTLP/HTTP; kosong = OTEL_EXPORTER_OTLP_* standar
+	SampleRatio  float64 // TRACING_SAMPLE_RATIO: 0 sampai 1, default 1 (semua trace)
+	ServiceName  string  // TRACING_SERVICE_NAME, default backend-go
+}
+
+// RateLimitConfig mengatur pembatasan laju dan perlindungan brute-force endpoint autentikasi.
+type RateLimitConfig struct {
+	Enabled          bool            // RATE_LIMIT_ENABLED, default true
+	LoginPerIP       ratelimit.Limit // RATE_LIMIT_LOGIN_IP: login per IP, default 20/1m
+	LoginPerAccount  ratelimit.Limit // RATE_LIMIT_LOGIN_ACCOUNT: login per email, default 10/15m
+	RegisterPerIP    ratelimit.Limit // RATE_LIMIT_REGISTER_IP: registrasi per IP, default 5/1h
+	LockoutThreshold int             // RATE_LIMIT_LOCKOUT_THRESHOLD: login gagal sebelum akun dikunci, default 5
+	LockoutDuration  time.Duration   // RATE_LIMIT_LOCKOUT_DURATION: lama penguncian akun, default 15m
+}
+
+// MFAConfig mengatur autentikasi dua faktor.
+type MFAConfig struct {
+	Issuer string // MFA_ISSUER: nama layanan di aplikasi authenticator, default "Fullstack CRUD"
+}
+
+// OIDCConfig mengatur login lewat penyedia identitas OpenID Connect. Nonaktif jika IssuerURL kosong.
+type OIDCConfig struct {
+	IssuerURL      string   // OIDC_ISSUER_URL: issuer penyedia identitas (discovery di /.well-known/openid-configuration)
+	ClientID       string   // OIDC_CLIENT_ID
+	ClientSecret   string   // OIDC_CLIENT_SECRET atau OIDC_CLIENT_SECRET_FILE; kosong untuk public client (hanya PKCE)
+	RedirectURL    string   // OIDC_REDIRECT_URL: URL callback yang terdaftar di penyedia identitas
+	AllowedDomains []string // OIDC_ALLOWED_DOMAINS: domain email yang boleh login, dipisah koma; kosong = semua
+	AutoProvision  bool     // OIDC_AUTO_PROVISION: buat akun untuk email yang belum terdaftar, default true
+}
+
+// AccountConfig mengatur layanan mandiri akun (/me).
+type AccountConfig struct {
+	DeletionGracePeriod time.Duration // ACCOUNT_DELETE_GRACE_PERIOD: jeda sebelum akun dihapus permanen, default 720h (30 hari)
+}
+
+// Enabled menunjukkan apakah login OIDC diaktifkan.
+func (c OIDCConfig) Enabled() bool {
+	return c.IssuerURL != ""
+}
+
+// ValidationError berisi semua masalah konfigurasi sekaligus, bukan hanya yang pertama.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "konfigurasi tidak valid:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// defaults adalah nilai bawaan untuk setiap kunci konfigurasi
+var defaults = map[string]string{
+	"SERVER_ADDR":                  ":8080",
+	"CORS_ALLOWED_ORIGINS":         "http://localhost:5173",
+	"SERVER_READ_TIMEOUT":          "15s",
+	"SERVER_READ_HEADER_TIMEOUT":   "5s",
+	"SERVER_WRITE_TIMEOUT":         "30s",
+	"SERVER_IDLE_TIMEOUT":          "120s",
+	"SERVER_SHUTDOWN_TIMEOUT":      "20s",
+	"SERVER_SHUTDOWN_DELAY":        "5s",
+	"DB_DRIVER":                    DriverMySQL,
+	"DB_HOST":                      "localhost",
+	"DB_SSLMODE":                   "disable",
+	"JWT_TTL":                      "1h",
+	"DEMO_MODE":                    "false",
+	"LOG_LEVEL":                    "info",
+	"LOG_FORMAT":                   "json",
+	"TRACING_EXPORTER":             "none",
+	"TRACING_FILE":                 "traces.jsonl",
+	"TRACING_SAMPLE_RATIO":         "1",
+	"TRACING_SERVICE_NAME":         "backend-go",
+	"RATE_LIMIT_ENABLED":           "true",
+	"RATE_LIMIT_LOGIN_IP":          "20/1m",
+	"RATE_LIMIT_LOGIN_ACCOUNT":     "10/15m",
+	"RATE_LIMIT_REGISTER_IP":       "5/1h",
+	"RATE_LIMIT_LOCKOUT_THRESHOLD": "5",
+	"RATE_LIMIT_LOCKOUT_DURATION":  "15m",
+	"MFA_ISSUER":                   "Fullstack CRUD",
+	"OIDC_AUTO_PROVISION":          "true",
+	"ACCOUNT_DELETE_GRACE_PERIOD":  "720h",
+}
+
+// fileConfig adalah bentuk file konfigurasi YAML/TOML. Setiap field dipetakan ke
+// kunci environment yang sama sehingga aturan precedence cukup satu.
+type fileConfig struct {
+	Server struct {
+		Addr              string   `yaml:"addr" toml:"addr"`
+		AllowedOrigins    []string `yaml:"allowed_origins" toml:"allowed_origins"`
+		TrustedProxies    []string `yaml:"trusted_proxies" toml:"trusted_proxies"`
+		ReadTimeout       string   `yaml:"read_timeout" toml:"read_timeout"`
+		ReadHeaderTimeout string   `yaml:"read_header_timeout" toml:"read_header_timeout"`
+		WriteTimeout      string   `yaml:"write_timeout" toml:"write_timeout"`
+		IdleTimeout       string   `yaml:"idle_timeout" toml:"idle_timeout"`
+		ShutdownTimeout   string   `yaml:"shutdown_timeout" toml:"shutdown_timeout"`
+		ShutdownDelay     string   `yaml:"shutdown_delay" toml:"shutdown_delay"`
+	} `yaml:"server" toml:"server"`
+	Database struct {
+		Driver       string `yaml:"driver" toml:"driver"`
+		Host         string `yaml:"host" toml:"host"`
+		Port         string `yaml:"port" toml:"port"`
+		User         string `yaml:"user" toml:"user"`
+		Password     string `yaml:"password" toml:"password"`
+		PasswordFile string `yaml:"password_file" toml:"password_file"`
+		Name         string `yaml:"name" toml:"name"`
+		SSLMode      string `yaml:"sslmode" toml:"sslmode"`
+	} `yaml:"database" toml:"database"`
+	JWT struct {
+		Secret               string   `yaml:"secret" toml:"secret"`
+		SecretFile           string   `yaml:"secret_file" toml:"secret_file"`
+		TTL                  string   `yaml:"ttl" toml:"ttl"`
+		SigningKeyFile       string   `yaml:"signing_key_file" toml:"signing_key_file"`
+		VerificationKeyFiles []string `yaml:"verification_key_files" toml:"verification_key_files"`
+	} `yaml:"jwt" toml:"jwt"`
+	Log struct {
+		Level  string `yaml:"level" toml:"level"`
+		Format string `yaml:"format" toml:"format"`
+	} `yaml:"log" toml:"log"`
+	Tracing struct {
+		Exporter     string `yaml:"exporter" toml:"exporter"`
+		File         string `yaml:"file" toml:"file"`
+		OTLPEndpoint string `yaml:"otlp_endpoint" toml:"otlp_endpoint"`
+		SampleRatio  string `yaml:"sample_ratio" toml:"sample_ratio"`
+		ServiceName  string `yaml:"service_name" toml:"service_name"`
+	} `yaml:"tracing" toml:"tracing"`
+	RateLimit struct {
+		Enabled          *bool  `yaml:"enabled" toml:"enabled"`
+		LoginIP          string `yaml:"login_ip" toml:"login_ip"`
+		LoginAccount     string `yaml:"login_account" toml:"login_account"`
+		RegisterIP       string `yaml:"register_ip" toml:"register_ip"`
+		LockoutThreshold int    `yaml:"lockout_threshold" toml:"lockout_threshold"`
+		LockoutDuration  string `yaml:"lockout_duration" toml:"lockout_duration"`
+	} `yaml:"rate_limit" toml:"rate_limit"`
+	MFA struct {
+		Issuer string `yaml:"issuer" toml:"issuer"`
+	} `yaml:"mfa" toml:"mfa"`
+	OIDC struct {
+		IssuerURL        string   `yaml:"issuer_url" toml:"issuer_url"`
+		ClientID         string   `yaml:"client_id" toml:"client_id"`
+		ClientSecret     string   `yaml:"client_secret" toml:"client_secret"`
+		ClientSecretFile string   `yaml:"client_secret_file" toml:"client_secret_file"`
+		RedirectURL      string   `yaml:"redirect_url" toml:"redirect_url"`
+		AllowedDomains   []string `yaml:"allowed_domains" toml:"allowed_domains"`
+		AutoProvision    *bool    `yaml:"auto_provision" toml:"auto_provision"`
+	} `yaml:"oidc" toml:"oidc"`
+	Account struct {
+		DeletionGracePeriod string `yaml:"deletion_grace_period" toml:"deletion_grace_period"`
+	} `yaml:"account" toml:"account"`
+	DemoMode *bool `yaml:"demo_mode" toml:"demo_mode"`
+}
+
+// values mengubah isi file menjadi pasangan kunci environment; field kosong diabaikan
+func (f *fileConfig) values() map[string]string {
+	values := map[string]string{
+		"SERVER_ADDR":                 f.Server.Addr,
+		"CORS_ALLOWED_ORIGINS":        strings.Join(f.Server.AllowedOrigins, ","),
+		"SERVER_TRUSTED_PROXIES":      strings.Join(f.Server.TrustedProxies, ","),
+		"SERVER_READ_TIMEOUT":         f.Server.ReadTimeout,
+		"SERVER_READ_HEADER_TIMEOUT":  f.Server.ReadHeaderTimeout,
+		"SERVER_WRITE_TIMEOUT":        f.Server.WriteTimeout,
+		"SERVER_IDLE_TIMEOUT":         f.Server.IdleTimeout,
+		"SERVER_SHUTDOWN_TIMEOUT":     f.Server.ShutdownTimeout,
+		"SERVER_SHUTDOWN_DELAY":       f.Server.ShutdownDelay,
+		"DB_DRIVER":                   f.Database.Driver,
+		"DB_HOST":                     f.Database.Host,
+		"DB_PORT":                     f.Database.Port,
+		"DB_USER":                     f.Database.User,
+		"DB_PASSWORD":                 f.Database.Password,
+		"DB_PASSWORD_FILE":            f.Database.PasswordFile,
+		"DB_NAME":                     f.Database.Name,
+		"DB_SSLMODE":                  f.Database.SSLMode,
+		"JWT_SECRET_KEY":              f.JWT.Secret,
+		"JWT_SECRET_KEY_FILE":         f.JWT.SecretFile,
+		"JWT_TTL":                     f.JWT.TTL,
+		"JWT_SIGNING_KEY_FILE":        f.JWT.SigningKeyFile,
+		"JWT_VERIFICATION_KEY_FILES":  strings.Join(f.JWT.VerificationKeyFiles, ","),
+		"LOG_LEVEL":                   f.Log.Level,
+		"LOG_FORMAT":                  f.Log.Format,
+		"TRACING_EXPORTER":            f.Tracing.Exporter,
+		"TRACING_FILE":                f.Tracing.File,
+		"TRACING_OTLP_ENDPOINT":       f.Tracing.OTLPEndpoint,
+		"TRACING_SAMPLE_RATIO":        f.Tracing.SampleRatio,
+		"TRACING_SERVICE_NAME":        f.Tracing.ServiceName,
+		"RATE_LIMIT_LOGIN_IP":         f.RateLimit.LoginIP,
+		"RATE_LIMIT_LOGIN_ACCOUNT":    f.RateLimit.LoginAccount,
+		"RATE_LIMIT_REGISTER_IP":      f.RateLimit.RegisterIP,
+		"RATE_LIMIT_LOCKOUT_DURATION": f.RateLimit.LockoutDuration,
+		"MFA_ISSUER":                  f.MFA.Issuer,
+		"OIDC_ISSUER_URL":             f.OIDC.IssuerURL,
+		"OIDC_CLIENT_ID":              f.OIDC.ClientID,
+		"OIDC_CLIENT_SECRET":          f.OIDC.ClientSecret,
+		"OIDC_CLIENT_SECRET_FILE":     f.OIDC.ClientSecretFile,
+		"OIDC_REDIRECT_URL":           f.OIDC.RedirectURL,
+		"OIDC_ALLOWED_DOMAINS":        strings.Join(f.OIDC.AllowedDomains, ","),
+		"ACCOUNT_DELETE_GRACE_PERIOD": f.Account.DeletionGracePeriod,
+	}
+	if f.DemoMode != nil {
+		values["DEMO_MODE"] = strconv.FormatBool(*f.DemoMode)
+	}
+	if f.RateLimit.Enabled != nil {
+		values["RATE_LIMIT_ENABLED"] = strconv.FormatBool(*f.RateLimit.Enabled)
+	}
+	if f.OIDC.AutoProvision != nil {
+		values["OIDC_AUTO_PROVISION"] = strconv.FormatBool(*f.OIDC.AutoProvision)
+	}
+	if f.RateLimit.LockoutThreshold != 0 {
+		values["RATE_LIMIT_LOCKOUT_THRESHOLD"] = strconv.Itoa(f.RateLimit.LockoutThreshold)
+	}
+	for key, value := range values {
+		if value == "" {
+			delete(values, key)
+		}
+	}
+	return values
+}
+
 // LoadEnv memuat variabel dari file .env di root direktori.
+// File .env bersifat opsional: tanpa file tersebut, variabel diambil dari environment
+// proses (mis. di container) dan tes memakai default SQLite in-memory.
 func LoadEnv() {
 	// Coba muat dari direktori saat ini (untuk `go run main.go`)
 	err := godotenv.Load()
@@ -14,7 +313,340 @@ func LoadEnv() {
 		// Jika gagal, coba muat dari direktori induk (untuk `go test ./...`)
 		err = godotenv.Load("../.env")
 		if err != nil {
-			log.Fatalf("Error memuat file .env: %v", err)
+			slog.Info("File .env tidak dimuat, memakai environment proses", "error", err)
+		}
+	}
+}
+
+// Load membaca konfigurasi dengan urutan prioritas (tertinggi lebih dulu):
+//  1. variabel environment proses
+//  2. file .env (tidak menimpa environment yang sudah ada)
+//  3. file konfigurasi YAML/TOML dari path (atau CONFIG_FILE jika path kosong), opsional
+//  4. nilai default
+//
+// Secret dapat dibaca dari file lewat DB_PASSWORD_FILE dan JWT_SECRET_KEY_FILE
+// (mis. Docker/Kubernetes secrets). Semua masalah validasi dikembalikan sekaligus
+// sebagai *ValidationError.
+func Load(path string) (*Config, error) {
+	LoadEnv()
+
+	if path == "" {
+		path = os.Getenv("CONFIG_FILE")
+	}
+	fileValues := map[string]string{}
+	if path != "" {
+		file, err := readConfigFile(path)
+		if err != nil {
+			return nil, err
+		}
+		fileValues = file.values()
+	}
+
+	r := &resolver{file: fileValues}
+	cfg := &Config{
+		Server: ServerConfig{
+			Addr:           r.addr(),
+			AllowedOrigins: splitList(r.get("CORS_ALLOWED_ORIGINS")),
+			TrustedProxies: splitList(r.get("SERVER_TRUSTED_PROXIES")),
+
+			ReadTimeout:       r.duration("SERVER_READ_TIMEOUT"),
+			ReadHeaderTimeout: r.duration("SERVER_READ_HEADER_TIMEOUT"),
+			WriteTimeout:      r.duration("SERVER_WRITE_TIMEOUT"),
+			IdleTimeout:       r.duration("SERVER_IDLE_TIMEOUT"),
+			ShutdownTimeout:   r.duration("SERVER_SHUTDOWN_TIMEOUT"),
+			ShutdownDelay:     r.nonNegativeDuration("SERVER_SHUTDOWN_DELAY"),
+		},
+		Database: DatabaseConfig{
+			Driver:   strings.ToLower(r.get("DB_DRIVER")),
+			Host:     r.get("DB_HOST"),
+			Port:     r.get("DB_PORT"),
+			User:     r.get("DB_USER"),
+			Password: r.secret("DB_PASSWORD"),
+			Name:     r.get("DB_NAME"),
+			SSLMode:  r.get("DB_SSLMODE"),
+		},
+		JWT: JWTConfig{
+			Secret:               r.secret("JWT_SECRET_KEY"),
+			TTL:                  r.duration("JWT_TTL"),
+			SigningKeyFile:       r.get("JWT_SIGNING_KEY_FILE"),
+			VerificationKeyFiles: splitList(r.get("JWT_VERIFICATION_KEY_FILES")),
+		},
+		Log: LogConfig{
+			Level:  r.level("LOG_LEVEL"),
+			Format: strings.ToLower(r.get("LOG_FORMAT")),
+		},
+		Tracing: TracingConfig{
+			Exporter:     strings.ToLower(r.get("TRACING_EXPORTER")),
+			File:         r.get("TRACING_FILE"),
+			OTLPEndpoint: r.get("TRACING_OTLP_ENDPOINT"),
+			SampleRatio:  r.ratio("TRACING_SAMPLE_RATIO"),
+			ServiceName:  r.get("TRACING_SERVICE_NAME"),
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:          r.bool("RATE_LIMIT_ENABLED"),
+			LoginPerIP:       r.limit("RATE_LIMIT_LOGIN_IP"),
+			LoginPerAccount:  r.limit("RATE_LIMIT_LOGIN_ACCOUNT"),
+			RegisterPerIP:    r.limit("RATE_LIMIT_REGISTER_IP"),
+			LockoutThreshold: r.positiveInt("RATE_LIMIT_LOCKOUT_THRESHOLD"),
+			LockoutDuration:  r.duration("RATE_LIMIT_LOCKOUT_DURATION"),
+		},
+		MFA: MFAConfig{
+			Issuer: r.get("MFA_ISSUER"),
+		},
+		OIDC: OIDCConfig{
+			IssuerURL:      r.get("OIDC_ISSUER_URL"),
+			ClientID:       r.get("OIDC_CLIENT_ID"),
+			ClientSecret:   r.secret("OIDC_CLIENT_SECRET"),
+			RedirectURL:    r.get("OIDC_REDIRECT_URL"),
+			AllowedDomains: splitList(strings.ToLower(r.get("OIDC_ALLOWED_DOMAINS"))),
+			AutoProvision:  r.bool("OIDC_AUTO_PROVISION"),
+		},
+		Account: AccountConfig{
+			DeletionGracePeriod: r.duration("ACCOUNT_DELETE_GRACE_PERIOD"),
+		},
+		DemoMode: r.bool("DEMO_MODE"),
+	}
+
+	problems := append(r.problems, cfg.validate()...)
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+	return cfg, nil
+}
+
+// validate memeriksa nilai konfigurasi dan mengembalikan semua masalah yang ditemukan
+func (c *Config) validate() []string {
+	var problems []string
+	if c.Server.Addr == "" {
+		problems = append(problems, "SERVER_ADDR tidak boleh kosong")
+	}
+	for _, origin := range c.Server.AllowedOrigins {
+		if u, err := url.Parse(origin); err != nil || u.Scheme == "" || u.Host == "" {
+			problems = append(problems, fmt.Sprintf("CORS_ALLOWED_ORIGINS: origin %q harus berupa URL lengkap (mis. https://app.example.com)", origin))
+		}
+	}
+	for _, proxy := range c.Server.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			problems = append(problems, fmt.Sprintf("SERVER_TRUSTED_PROXIES: %q bukan alamat IP atau CIDR", proxy))
+		}
+	}
+
+	switch c.Database.Driver {
+	case DriverSQLite:
+	case DriverMySQL, DriverPostgres:
+		required := []struct{ key, value string }{
+			{"DB_USER", c.Database.User},
+			{"DB_PASSWORD", c.Database.Password},
+			{"DB_NAME", c.Database.Name},
+		}
+		for _, field := range required {
+			if field.value == "" {
+				problems = append(problems, fmt.Sprintf("%s wajib diisi untuk DB_DRIVER=%s", field.key, c.Database.Driver))
+			}
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("DB_DRIVER %q tidak didukung (gunakan mysql, postgres atau sqlite)", c.Database.Driver))
+	}
+
+	// Dengan kunci asimetris, secret opsional (hanya untuk menerima token HS256 lama)
+	if c.JWT.Secret == "" && c.JWT.SigningKeyFile == "" {
+		problems = append(problems, "JWT_SECRET_KEY (atau JWT_SECRET_KEY_FILE) wajib diisi")
+	}
+	keyFiles := []struct{ key, path string }{{"JWT_SIGNING_KEY_FILE", c.JWT.SigningKeyFile}}
+	for _, path := range c.JWT.VerificationKeyFiles {
+		keyFiles = append(keyFiles, struct{ key, path string }{"JWT_VERIFICATION_KEY_FILES", path})
+	}
+	for _, file := range keyFiles {
+		if file.path == "" {
+			continue
+		}
+		if _, err := os.Stat(file.path); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: file %q tidak dapat dibaca", file.key, file.path))
+		}
+	}
+
+	if strings.TrimSpace(c.MFA.Issuer) == "" || strings.Contains(c.MFA.Issuer, ":") {
+		problems = append(problems, "MFA_ISSUER tidak boleh kosong atau mengandung ':'")
+	}
+
+	if c.OIDC.Enabled() {
+		if u, err := url.Parse(c.OIDC.IssuerURL); err != nil || u.Scheme == "" || u.Host == "" {
+			problems = append(problems, fmt.Sprintf("OIDC_ISSUER_URL %q harus berupa URL lengkap (mis. https://login.example.com)", c.OIDC.IssuerURL))
+		}
+		if c.OIDC.ClientID == "" {
+			problems = append(problems, "OIDC_CLIENT_ID wajib diisi jika OIDC_ISSUER_URL diatur")
+		}
+		if u, err := url.Parse(c.OIDC.RedirectURL); err != nil || u.Scheme == "" || u.Host == "" {
+			problems = append(problems, "OIDC_REDIRECT_URL wajib berupa URL lengkap jika OIDC_ISSUER_URL diatur")
+		}
+	}
+
+	if c.Log.Format != logging.FormatJSON && c.Log.Format != logging.FormatText {
+		problems = append(problems, fmt.Sprintf("LOG_FORMAT %q tidak didukung (gunakan json atau text)", c.Log.Format))
+	}
+
+	switch c.Tracing.Exporter {
+	case TracingNone, TracingStdout, TracingFile:
+	case TracingOTLP:
+		if c.Tracing.OTLPEndpoint != "" {
+			if u, err := url.Parse(c.Tracing.OTLPEndpoint); err != nil || u.Scheme == "" || u.Host == "" {
+				problems = append(problems, fmt.Sprintf("TRACING_OTLP_ENDPOINT %q harus berupa URL lengkap (mis. http://otel-collector:4318)", c.Tracing.OTLPEndpoint))
+			}
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("TRACING_EXPORTER %q tidak didukung (gunakan none, stdout, file atau otlp)", c.Tracing.Exporter))
+	}
+	return problems
+}
+
+// resolver mencari nilai konfigurasi sesuai precedence dan mengumpulkan masalah parsing
+type resolver struct {
+	file     map[string]string
+	problems []string
+}
+
+// get mengembalikan nilai dari environment, file, lalu default
+func (r *resolver) get(key string) string {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value
+	}
+	if value, ok := r.file[key]; ok {
+		return value
+	}
+	return defaults[key]
+}
+
+// addr mendukung PORT (konvensi platform container) jika SERVER_ADDR tidak diatur
+func (r *resolver) addr() string {
+	_, inFile := r.file["SERVER_ADDR"]
+	if os.Getenv("SERVER_ADDR") == "" && !inFile {
+		if port := os.Getenv("PORT"); port != "" {
+			return ":" + port
+		}
+	}
+	return r.get("SERVER_ADDR")
+}
+
+// secret membaca nilai langsung atau dari file yang ditunjuk oleh KEY_FILE
+func (r *resolver) secret(key string) string {
+	value, path := r.get(key), r.get(key+"_FILE")
+	if path == "" {
+		return value
+	}
+	if value != "" {
+		r.problems = append(r.problems, fmt.Sprintf("%s dan %s_FILE tidak boleh diisi bersamaan", key, key))
+		return value
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		r.problems = append(r.problems, fmt.Sprintf("%s_FILE: %v", key, err))
+		return ""
+	}
+	return strings.TrimRight(string(content), "\r\n")
+}
+
+// duration mem-parse nilai positif seperti "90s" atau "1h"
+func (r *resolver) duration(key string) time.Duration {
+	value := r.get(key)
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		r.problems = append(r.problems, fmt.Sprintf("%s: durasi %q tidak valid", key, value))
+	} else if d <= 0 {
+		r.problems = append(r.problems, fmt.Sprintf("%s harus lebih besar dari nol", key))
+	}
+	return d
+}
+
+// nonNegativeDuration mem-parse durasi yang boleh bernilai nol (fitur dinonaktifkan)
+func (r *resolver) nonNegativeDuration(key string) time.Duration {
+	value := r.get(key)
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		r.problems = append(r.problems, fmt.Sprintf("%s: durasi %q tidak valid", key, value))
+	} else if d < 0 {
+		r.problems = append(r.problems, fmt.Sprintf("%s tidak boleh negatif", key))
+	}
+	return d
+}
+
+// bool mem-parse nilai true/false/1/0
+func (r *resolver) bool(key string) bool {
+	value := r.get(key)
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		r.problems = append(r.problems, fmt.Sprintf("%s: nilai boolean %q tidak valid", key, value))
+	}
+	return b
+}
+
+// ratio mem-parse bilangan pecahan antara 0 dan 1
+func (r *resolver) ratio(key string) float64 {
+	value := r.get(key)
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || f < 0 || f > 1 {
+		r.problems = append(r.problems, fmt.Sprintf("%s: nilai %q harus antara 0 dan 1", key, value))
+	}
+	return f
+}
+
+// positiveInt mem-parse bilangan bulat lebih besar dari nol
+func (r *resolver) positiveInt(key string) int {
+	value := r.get(key)
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		r.problems = append(r.problems, fmt.Sprintf("%s: nilai %q harus bilangan bulat positif", key, value))
+	}
+	return n
+}
+
+// limit mem-parse batas laju berformat "<jumlah>/<durasi>", mis. "20/1m"
+func (r *resolver) limit(key string) ratelimit.Limit {
+	limit, err := ratelimit.ParseLimit(r.get(key))
+	if err != nil {
+		r.problems = append(r.problems, fmt.Sprintf("%s: %v", key, err))
+	}
+	return limit
+}
+
+// level mem-parse level log seperti "debug", "info", "warn" atau "error"
+func (r *resolver) level(key string) slog.Level {
+	value := r.get(key)
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(value)); err != nil {
+		r.problems = append(r.problems, fmt.Sprintf("%s: level %q tidak valid (gunakan debug, info, warn atau error)", key, value))
+	}
+	return level
+}
+
+// readConfigFile membaca file YAML atau TOML; format ditentukan dari ekstensi
+func readConfigFile(path string) (*fileConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca file konfigurasi: %w", err)
+	}
+
+	var file fileConfig
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &file)
+	case ".toml":
+		err = toml.Unmarshal(data, &file)
+	default:
+		return nil, fmt.Errorf("format file konfigurasi %s tidak didukung (gunakan .yaml, .yml atau .toml)", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("gagal mem-parse file konfigurasi %s: %w", path, err)
+	}
+	return &file, nil
+}
+
+// splitList memecah daftar dipisah koma dan membuang elemen kosong
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
 		}
 	}
-}
\ No newline at end of file
+	return items
+}
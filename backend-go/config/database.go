@@ -1,70 +1,228 @@
 package config
 
 import (
+    "context"
+    "errors"
     "fmt"
-    "log"
+    "log/slog"
     "os"
-    "fullstack-crud-project-01/backend-go/models"
+    "strings"
+
+    "fullstack-crud-project-01/backend-go/database"
+    "fullstack-crud-project-01/backend-go/logging"
+    "github.com/glebarez/sqlite"
     "gorm.io/driver/mysql"
+    "gorm.io/driver/postgres"
     "gorm.io/gorm"
 )
 
 // DB adalah variabel global untuk koneksi database
 var DB *gorm.DB
 
-// buildDSN membuat Data Source Name (DSN) dari variabel lingkungan
-func buildDSN(dbNameEnvKey string) string {
-    // Ambil nilai dari variabel lingkungan
-	user := os.Getenv("DB_USER")
-	password := os.Getenv("DB_PASSWORD")
-	host := os.Getenv("DB_HOST")
-	port := os.Getenv("DB_PORT")
-	dbName := os.Getenv(dbNameEnvKey) // Menggunakan DB_NAME atau DB_NAME_TEST
+// Driver database yang didukung (nilai DB_DRIVER)
+const (
+    DriverMySQL    = "mysql"
+    DriverPostgres = "postgres"
+    DriverSQLite   = "sqlite" // Pure-Go (tanpa CGO), cocok untuk laptop tanpa server database
+)
+
+// buildDSN membuat Data Source Name (DSN) sesuai driver
+func buildDSN(cfg DatabaseConfig) (string, error) {
+    switch cfg.Driver {
+    case DriverSQLite:
+        // Untuk SQLite, Name adalah path file database (atau ":memory:")
+        dbName := cfg.Name
+        if dbName == "" {
+            dbName = "app.db"
+        }
+        if dbName == ":memory:" {
+            dbName = "file::memory:"
+        }
+        return dbName + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)", nil
 
-    if user == "" || password == "" || dbName == "" {
-        log.Fatal("Kredensial database di .env belum lengkap!")
+    case DriverMySQL, DriverPostgres:
+        if cfg.User == "" || cfg.Password == "" || cfg.Name == "" {
+            return "", fmt.Errorf("kredensial database belum lengkap (DB_USER, DB_PASSWORD, DB_NAME)")
+        }
+        if cfg.Driver == DriverPostgres {
+            port, sslMode := cfg.Port, cfg.SSLMode
+            if port == "" {
+                port = "5432"
+            }
+            if sslMode == "" {
+                sslMode = "disable"
+            }
+            return fmt.Sprintf(
+                "host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=UTC",
+                cfg.Host, cfg.User, cfg.Password, cfg.Name, port, sslMode,
+            ), nil
+        }
+
+        port := cfg.Port
+        if port == "" {
+            port = "3306"
+        }
+        // Format DSN MySQL
+        return fmt.Sprintf(
+            "%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+            cfg.User, cfg.Password, cfg.Host, port, cfg.Name,
+        ), nil
+
+    default:
+        return "", fmt.Errorf("DB_DRIVER %q tidak didukung (gunakan mysql, postgres atau sqlite)", cfg.Driver)
     }
+}
 
-	// Format DSN
-	return fmt.Sprintf(
-		"%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		user, password, host, port, dbName,
-	)
+// dialector memilih dialector GORM untuk driver
+func dialector(driver, dsn string) gorm.Dialector {
+    switch driver {
+    case DriverPostgres:
+        return postgres.Open(dsn)
+    case DriverSQLite:
+        return sqlite.Open(dsn)
+    default:
+        return mysql.Open(dsn)
+    }
 }
 
-// ConnectDatabase menginisialisasi koneksi ke MySQL
-func ConnectDatabase() {
-    dsn := buildDSN("DB_NAME")
-    
-	database, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+// Open membuka koneksi database sesuai konfigurasi
+func Open(cfg DatabaseConfig) (*gorm.DB, error) {
+    dsn, err := buildDSN(cfg)
+    if err != nil {
+        return nil, err
+    }
 
+    // Log SQL lewat slog (dengan request ID, tanpa nilai parameter). TranslateError mengubah
+    // pelanggaran unique constraint menjadi gorm.ErrDuplicatedKey di semua dialek.
+    database, err := gorm.Open(dialector(cfg.Driver, dsn), &gorm.Config{Logger: logging.NewGormLogger(nil), TranslateError: true})
     if err != nil {
-        log.Fatal("Koneksi ke database GAGAL! \n", err)
+        return nil, err
+    }
+
+    if cfg.Driver == DriverSQLite {
+        // SQLite hanya mengizinkan satu penulis; satu koneksi menghindari "database is locked"
+        // dan membuat database ":memory:" dipakai bersama oleh semua query
+        sqlDB, err := database.DB()
+        if err != nil {
+            return nil, err
+        }
+        sqlDB.SetMaxOpenConns(1)
+    }
+    return database, nil
+}
+
+// ConnectDatabase menginisialisasi koneksi ke database sesuai konfigurasi,
+// mengatur variabel global DB, lalu menerapkan migrasi
+func ConnectDatabase(cfg DatabaseConfig) error {
+    database, err := Open(cfg)
+    if err != nil {
+        return fmt.Errorf("koneksi ke database gagal: %w", err)
     }
 
     // Set variabel DB global
     DB = database
-    fmt.Println("Koneksi database Berhasil!")
+    slog.Info("Koneksi database berhasil", "driver", cfg.Driver)
+
+    // Terapkan migrasi SQL berversi dari database/migrations/<driver>
+    if err := RunMigrations(DB); err != nil {
+        return fmt.Errorf("migrasi database gagal: %w", err)
+    }
+    return nil
+}
 
-    // Otomatis membuat/memperbarui tabel 'products' di database
-	err = DB.AutoMigrate(&models.Product{})
+// RunMigrations menerapkan semua migrasi ter-embed yang belum diterapkan sesuai dialek db.
+// Database lama yang dibuat oleh AutoMigrate di-baseline otomatis terlebih dahulu.
+func RunMigrations(db *gorm.DB) error {
+    migrations, err := database.EmbeddedMigrations(db.Dialector.Name())
     if err != nil {
-        log.Fatal("Migrasi tabel GAGAL! \n", err)
+        return err
+    }
+    migrator := database.NewMigrator(db, migrations)
+    baselined, err := migrator.BaselineLegacy(context.Background())
+    if err != nil {
+        return err
+    }
+    if baselined {
+        slog.Warn("Database lama (AutoMigrate) terdeteksi dan di-baseline", "version", database.LegacyBaselineVersion)
     }
-    fmt.Println("Migrasi tabel 'products' Berhasil.")
+    ran, err := migrator.Up(context.Background())
+    if err != nil {
+        return err
+    }
+    slog.Info("Migrasi database selesai", "applied", len(ran))
+    return nil
+}
+
+// errNotConnected dikembalikan pemeriksaan kesehatan jika ConnectDatabase belum dipanggil
+var errNotConnected = errors.New("database belum terhubung")
+
+// Ping memeriksa koneksi database global DB (dipakai readiness probe)
+func Ping(ctx context.Context) error {
+    if DB == nil {
+        return errNotConnected
+    }
+    sqlDB, err := DB.DB()
+    if err != nil {
+        return err
+    }
+    return sqlDB.PingContext(ctx)
+}
+
+// CheckMigrations memastikan semua migrasi ter-embed sudah diterapkan pada database global DB
+// tanpa drift, mis. instance baru yang berjalan sebelum `migrate up` selesai
+func CheckMigrations(ctx context.Context) error {
+    if DB == nil {
+        return errNotConnected
+    }
+    migrations, err := database.EmbeddedMigrations(DB.Dialector.Name())
+    if err != nil {
+        return err
+    }
+    statuses, err := database.NewMigrator(DB.WithContext(ctx), migrations).Status(ctx)
+    if err != nil {
+        return err
+    }
+    pending, drift := 0, 0
+    for _, s := range statuses {
+        switch {
+        case s.Drift:
+            drift++
+        case !s.Applied:
+            pending++
+        }
+    }
+    if pending > 0 || drift > 0 {
+        return fmt.Errorf("%d migrasi belum diterapkan, %d migrasi mengalami drift", pending, drift)
+    }
+    return nil
 }
 
 // ConnectTestDatabase menginisialisasi koneksi ke database TEST dan mengembalikannya.
+// Driver diambil dari DB_DRIVER_TEST (default sqlite in-memory) dan nama database dari
+// DB_NAME_TEST; kredensial lain memakai DB_* yang sama dengan aplikasi.
 func ConnectTestDatabase() (*gorm.DB, error) {
-    dsn := buildDSN("DB_NAME_TEST") // Gunakan DB_NAME_TEST untuk pengujian
-    
-	database, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+    cfg := DatabaseConfig{
+        Driver:   strings.ToLower(os.Getenv("DB_DRIVER_TEST")),
+        Host:     os.Getenv("DB_HOST"),
+        Port:     os.Getenv("DB_PORT"),
+        User:     os.Getenv("DB_USER"),
+        Password: os.Getenv("DB_PASSWORD"),
+        Name:     os.Getenv("DB_NAME_TEST"), // Gunakan DB_NAME_TEST untuk pengujian
+        SSLMode:  os.Getenv("DB_SSLMODE"),
+    }
+    if cfg.Driver == "" {
+        cfg.Driver = DriverSQLite
+    }
+    if cfg.Driver == DriverSQLite && cfg.Name == "" {
+        cfg.Name = ":memory:"
+    }
 
+    database, err := Open(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("koneksi ke database TEST GAGAL: %w", err)
 	}
 
-	fmt.Println("Koneksi database TEST Berhasil!")
+	slog.Info("Koneksi database TEST berhasil", "driver", cfg.Driver)
 	// Mengembalikan instance DB, bukan menyimpannya di variabel global
 	return database, nil
 }
@@ -0,0 +1,217 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// sqliteLockTableSQL membuat tabel pengganti advisory lock untuk SQLite; satu baris per lock
+const sqliteLockTableSQL = `CREATE TABLE IF NOT EXISTS schema_migrations_lock (
+	id INTEGER PRIMARY KEY,
+	locked_at DATETIME NOT NULL
+)`
+
+// advisoryLock adalah satu advisory lock bernama: GET_LOCK pada MySQL, pg_advisory_lock
+// pada PostgreSQL dan satu baris tabel schema_migrations_lock pada SQLite.
+type advisoryLock struct {
+	name string // Nama untuk GET_LOCK
+	key  int64  // Kunci untuk pg_advisory_lock
+	row  int64  // ID baris di schema_migrations_lock
+}
+
+// tryLock mencoba mengambil lock sekali tanpa menunggu
+func (l advisoryLock) tryLock(ctx context.Context, conn *sql.Conn, dialect string, staleAfter time.Duration) (bool, error) {
+	switch dialect {
+	case "mysql":
+		var got sql.NullInt64
+		err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, 0)", l.name).Scan(&got)
+		return got.Valid && got.Int64 == 1, err
+	case "postgres":
+		var got bool
+		err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", l.key).Scan(&got)
+		return got, err
+	default:
+		// SQLite tidak punya advisory lock: pakai baris di tabel lock,
+		// dan ambil alih lock milik proses yang mati setelah staleAfter
+		now := time.Now().UTC()
+		if _, err := conn.ExecContext(ctx, "DELETE FROM schema_migrations_lock WHERE id = ? AND locked_at < ?", l.row, now.Add(-staleAfter)); err != nil {
+			return false, err
+		}
+		result, err := conn.ExecContext(ctx, "INSERT OR IGNORE INTO schema_migrations_lock (id, locked_at) VALUES (?, ?)", l.row, now)
+		if err != nil {
+			return false, err
+		}
+		rows, err := result.RowsAffected()
+		return rows == 1, err
+	}
+}
+
+// unlock melepas advisory lock
+func (l advisoryLock) unlock(ctx context.Context, conn *sql.Conn, dialect string) error {
+	var err error
+	switch dialect {
+	case "mysql":
+		_, err = conn.ExecContext(ctx, "SELECT RELEASE_LOCK(?)", l.name)
+	case "postgres":
+		_, err = conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", l.key)
+	default:
+		_, err = conn.ExecContext(ctx, "DELETE FROM schema_migrations_lock WHERE id = ?", l.row)
+	}
+	return err
+}
+
+// LeaderLock memilih satu instance sebagai pemegang pekerjaan latar yang tidak boleh
+// berjalan di beberapa replika sekaligus (mis. relay outbox), memakai advisory lock yang
+// sama dengan Migrator.
+//
+// Pada MySQL/PostgreSQL lock terikat pada satu koneksi yang dipegang selama menjadi leader;
+// jika koneksi putus, lock otomatis lepas dan instance lain bisa mengambil alih. Pada SQLite
+// lock adalah baris yang diperbarui setiap Acquire dan diambil alih setelah StaleAfter.
+type LeaderLock struct {
+	DB         *gorm.DB
+	StaleAfter time.Duration // SQLite: lock yang tidak diperbarui selama ini dianggap milik proses yang mati
+
+	lock     advisoryLock
+	mu       sync.Mutex
+	conn     *sql.Conn // MySQL/PostgreSQL: koneksi pemegang lock
+	lockedAt time.Time // SQLite: nilai locked_at terakhir yang ditulis instance ini
+	held     bool
+}
+
+// NewLeaderLock adalah konstruktor untuk LeaderLock dengan nama lock yang unik per pekerjaan.
+func NewLeaderLock(db *gorm.DB, name string) *LeaderLock {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	key := int64(h.Sum64() >> 1) // Positif agar aman dipakai sebagai id baris SQLite
+	return &LeaderLock{
+		DB:         db,
+		StaleAfter: time.Minute,
+		lock:       advisoryLock{name: name, key: key, row: key},
+	}
+}
+
+// Acquire mengambil lock jika belum dipegang, atau memastikan lock yang sudah dipegang
+// masih berlaku. Mengembalikan true selama instance ini adalah leader; panggil secara
+// berkala (lebih sering dari StaleAfter).
+func (l *LeaderLock) Acquire(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if dialectName(l.DB) == "sqlite" {
+		return l.acquireSQLite(ctx)
+	}
+
+	if l.held {
+		// Lock sesi hilang bersama koneksinya
+		if err := l.conn.PingContext(ctx); err == nil {
+			return true, nil
+		}
+		l.conn.Close()
+		l.conn, l.held = nil, false
+	}
+
+	sqlDB, err := l.DB.DB()
+	if err != nil {
+		return false, err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	acquired, err := l.lock.tryLock(ctx, conn, dialectName(l.DB), l.StaleAfter)
+	if err != nil || !acquired {
+		conn.Close()
+		return false, err
+	}
+	l.conn, l.held = conn, true
+	return true, nil
+}
+
+// acquireSQLite memperbarui atau mengambil baris lock. Koneksi tidak dipegang karena
+// SQLite hanya memakai satu koneksi untuk seluruh aplikasi.
+func (l *LeaderLock) acquireSQLite(ctx context.Context) (bool, error) {
+	sqlDB, err := l.DB.DB()
+	if err != nil {
+		return false, err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	if l.held {
+		// locked_at yang berbeda berarti baris sudah diambil alih instance lain
+		now := time.Now().UTC()
+		result, err := conn.ExecContext(ctx, "UPDATE schema_migrations_lock SET locked_at = ? WHERE id = ? AND locked_at = ?", now, l.lock.row, l.lockedAt)
+		if err != nil {
+			return false, err
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return false, err
+		}
+		if rows == 1 {
+			l.lockedAt = now
+			return true, nil
+		}
+		l.held = false
+	}
+
+	if _, err := conn.ExecContext(ctx, sqliteLockTableSQL); err != nil {
+		return false, err
+	}
+	acquired, err := l.lock.tryLock(ctx, conn, "sqlite", l.StaleAfter)
+	if err != nil || !acquired {
+		return false, err
+	}
+	// Tandai baris dengan locked_at milik sendiri untuk pembaruan berikutnya
+	l.lockedAt = time.Now().UTC()
+	if _, err := conn.ExecContext(ctx, "UPDATE schema_migrations_lock SET locked_at = ? WHERE id = ?", l.lockedAt, l.lock.row); err != nil {
+		return false, err
+	}
+	l.held = true
+	return true, nil
+}
+
+// Release melepas lock jika sedang dipegang.
+func (l *LeaderLock) Release(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.held {
+		return nil
+	}
+	l.held = false
+
+	if dialectName(l.DB) == "sqlite" {
+		sqlDB, err := l.DB.DB()
+		if err != nil {
+			return err
+		}
+		conn, err := sqlDB.Conn(ctx)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		// Jangan hapus baris yang sudah diambil alih instance lain
+		_, err = conn.ExecContext(ctx, "DELETE FROM schema_migrations_lock WHERE id = ? AND locked_at = ?", l.lock.row, l.lockedAt)
+		return err
+	}
+
+	defer func() {
+		l.conn.Close()
+		l.conn = nil
+	}()
+	return l.lock.unlock(ctx, l.conn, dialectName(l.DB))
+}
+
+// dialectName mengembalikan nama dialek GORM: mysql, postgres atau sqlite
+func dialectName(db *gorm.DB) string {
+	return db.Dialector.Name()
+}
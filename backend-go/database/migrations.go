@@ -0,0 +1,119 @@
+// Package database berisi migrasi SQL ter-embed beserta runner-nya.
+// File migrasi disimpan per dialek di migrations/<driver>/NNNNNN_nama.{up,down}.sql.
+package database
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed migrations
+var embeddedMigrations embed.FS
+
+// Migration adalah satu versi skema beserta SQL up/down-nya.
+type Migration struct {
+	Version  uint
+	Name     string
+	Up       string
+	Down     string
+	Checksum string // SHA-256 dari isi file up dan down, untuk mendeteksi drift
+}
+
+// migrationFilePattern mencocokkan nama file seperti 000001_create_products_table.up.sql
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// EmbeddedMigrations mengembalikan migrasi ter-embed untuk dialek (mysql, postgres, sqlite).
+func EmbeddedMigrations(dialect string) ([]Migration, error) {
+	sub, err := fs.Sub(embeddedMigrations, path.Join("migrations", dialect))
+	if err != nil {
+		return nil, err
+	}
+	migrations, err := LoadMigrations(sub)
+	if err != nil {
+		return nil, err
+	}
+	if len(migrations) == 0 {
+		return nil, fmt.Errorf("tidak ada migrasi untuk dialek %q", dialect)
+	}
+	return migrations, nil
+}
+
+// LoadMigrations membaca pasangan file up/down dari root fsys, terurut berdasarkan versi.
+// Setiap versi wajib memiliki file up dan down.
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[uint]*Migration)
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("versi migrasi tidak valid: %s", entry.Name())
+		}
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[uint(version)]
+		if !ok {
+			m = &Migration{Version: uint(version), Name: match[2]}
+			byVersion[uint(version)] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("versi migrasi %d dipakai oleh dua nama: %s dan %s", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if strings.TrimSpace(m.Up) == "" || strings.TrimSpace(m.Down) == "" {
+			return nil, fmt.Errorf("migrasi %d_%s wajib memiliki file up dan down", m.Version, m.Name)
+		}
+		sum := sha256.Sum256([]byte(m.Up + "\x00" + m.Down))
+		m.Checksum = hex.EncodeToString(sum[:])
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// splitStatements memecah isi file SQL menjadi statement terpisah, karena driver
+// MySQL tidak menjalankan banyak statement dalam satu Exec. Komentar baris (--) dibuang.
+// Titik koma di dalam string literal tidak didukung.
+func splitStatements(sql string) []string {
+	var cleaned strings.Builder
+	for _, line := range strings.Split(sql, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "--") {
+			continue
+		}
+		cleaned.WriteString(line)
+		cleaned.WriteString("\n")
+	}
+
+	var statements []string
+	for _, stmt := range strings.Split(cleaned.String(), ";") {
+		if stmt = strings.TrimSpace(stmt); stmt != "" {
+			statements = append(statements, stmt)
+		}
+	}
+	return statements
+}
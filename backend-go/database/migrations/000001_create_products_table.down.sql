@@ -1,3 +0,0 @@
--- database/migrations/000001_create_products_table.down.sql
-
-DROP TABLE products;
\ No newline at end of file
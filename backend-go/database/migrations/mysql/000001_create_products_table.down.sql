@@ -0,0 +1,3 @@
+-- database/migrations/mysql/000001_create_products_table.down.sql
+
+DROP TABLE products;
\ No newline at end of file
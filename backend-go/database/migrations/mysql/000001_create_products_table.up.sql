@@ -1,4 +1,4 @@
--- database/migrations/000001_create_products_table.up.sql
+-- database/migrations/mysql/000001_create_products_table.up.sql
 
 CREATE TABLE products (
     id BIGINT AUTO_INCREMENT PRIMARY KEY,
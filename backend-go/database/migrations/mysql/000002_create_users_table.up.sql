@@ -1,4 +1,4 @@
--- database/migrations/000002_create_users_table.up.sql
+-- database/migrations/mysql/000002_create_users_table.up.sql
 
 CREATE TABLE users (
     id BIGINT AUTO_INCREMENT PRIMARY KEY,
@@ -0,0 +1,3 @@
+-- database/migrations/mysql/000003_add_role_to_users.down.sql
+
+ALTER TABLE users DROP COLUMN role;
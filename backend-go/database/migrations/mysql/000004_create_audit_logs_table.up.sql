@@ -0,0 +1,20 @@
+-- database/migrations/mysql/000004_create_audit_logs_table.up.sql
+
+CREATE TABLE audit_logs (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    actor_id BIGINT NULL,
+    actor_email VARCHAR(255),
+    action VARCHAR(50) NOT NULL,
+    resource VARCHAR(50) NOT NULL,
+    resource_id VARCHAR(64),
+    `before` TEXT NULL,
+    `after` TEXT NULL,
+    ip VARCHAR(45),
+    user_agent VARCHAR(255),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_audit_logs_actor_id ON audit_logs (actor_id);
+CREATE INDEX idx_audit_logs_action ON audit_logs (action);
+CREATE INDEX idx_audit_logs_resource ON audit_logs (resource);
+CREATE INDEX idx_audit_logs_created_at ON audit_logs (created_at);
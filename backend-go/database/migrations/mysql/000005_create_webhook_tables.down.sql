@@ -0,0 +1,5 @@
+-- database/migrations/mysql/000005_create_webhook_tables.down.sql
+
+DROP TABLE webhook_delivery_logs;
+DROP TABLE webhook_deliveries;
+DROP TABLE webhook_subscriptions;
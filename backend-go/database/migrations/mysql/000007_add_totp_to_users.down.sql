@@ -0,0 +1,6 @@
+-- database/migrations/mysql/000007_add_totp_to_users.down.sql
+
+DROP TABLE recovery_codes;
+ALTER TABLE users DROP COLUMN totp_last_counter;
+ALTER TABLE users DROP COLUMN totp_enabled;
+ALTER TABLE users DROP COLUMN totp_secret;
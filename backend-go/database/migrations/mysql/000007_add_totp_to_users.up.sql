@@ -0,0 +1,15 @@
+-- database/migrations/mysql/000007_add_totp_to_users.up.sql
+
+ALTER TABLE users ADD COLUMN totp_secret VARCHAR(64) NULL AFTER role;
+ALTER TABLE users ADD COLUMN totp_enabled BOOLEAN NOT NULL DEFAULT FALSE AFTER totp_secret;
+ALTER TABLE users ADD COLUMN totp_last_counter BIGINT NOT NULL DEFAULT 0 AFTER totp_enabled;
+
+CREATE TABLE recovery_codes (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    code_hash CHAR(64) NOT NULL,
+    used_at DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_recovery_codes_user_code ON recovery_codes (user_id, code_hash);
@@ -0,0 +1,3 @@
+-- database/migrations/mysql/000008_create_api_keys_table.down.sql
+
+DROP TABLE api_keys;
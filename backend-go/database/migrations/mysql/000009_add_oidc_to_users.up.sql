@@ -0,0 +1,6 @@
+-- database/migrations/mysql/000009_add_oidc_to_users.up.sql
+
+ALTER TABLE users ADD COLUMN oidc_issuer VARCHAR(255) NULL AFTER totp_last_counter;
+ALTER TABLE users ADD COLUMN oidc_subject VARCHAR(255) NULL AFTER oidc_issuer;
+
+CREATE UNIQUE INDEX idx_users_oidc_identity ON users (oidc_issuer, oidc_subject);
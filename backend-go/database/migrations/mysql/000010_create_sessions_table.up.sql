@@ -0,0 +1,18 @@
+-- database/migrations/mysql/000010_create_sessions_table.up.sql
+
+CREATE TABLE sessions (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    token_id VARCHAR(32) NOT NULL,
+    device VARCHAR(100),
+    ip VARCHAR(45),
+    user_agent VARCHAR(255),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    last_seen_at DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL,
+    revoked_at DATETIME NULL
+);
+
+CREATE UNIQUE INDEX idx_sessions_token_id ON sessions (token_id);
+CREATE INDEX idx_sessions_user_id ON sessions (user_id);
+CREATE INDEX idx_sessions_expires_at ON sessions (expires_at);
@@ -0,0 +1,9 @@
+-- database/migrations/mysql/000011_add_account_self_service_to_users.up.sql
+
+ALTER TABLE users ADD COLUMN pending_email VARCHAR(255) NULL AFTER oidc_subject;
+ALTER TABLE users ADD COLUMN email_change_token VARCHAR(255) NULL AFTER pending_email;
+ALTER TABLE users ADD COLUMN email_change_expiry DATETIME NULL AFTER email_change_token;
+ALTER TABLE users ADD COLUMN deletion_scheduled_at DATETIME NULL AFTER email_change_expiry;
+
+CREATE UNIQUE INDEX idx_users_email_change_token ON users (email_change_token);
+CREATE INDEX idx_users_deletion_scheduled_at ON users (deletion_scheduled_at);
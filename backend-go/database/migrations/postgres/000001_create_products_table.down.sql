@@ -0,0 +1,3 @@
+-- database/migrations/postgres/000001_create_products_table.down.sql
+
+DROP TABLE products;
@@ -0,0 +1,14 @@
+-- database/migrations/postgres/000001_create_products_table.up.sql
+
+CREATE TABLE products (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    price INT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    deleted_at TIMESTAMPTZ NULL
+);
+
+-- Opsional: Tambahkan index untuk pencarian nama produk yang lebih cepat
+CREATE INDEX idx_products_name ON products (name);
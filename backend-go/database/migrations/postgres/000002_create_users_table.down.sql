@@ -0,0 +1,3 @@
+-- database/migrations/postgres/000002_create_users_table.down.sql
+
+DROP TABLE users;
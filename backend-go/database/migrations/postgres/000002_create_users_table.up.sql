@@ -0,0 +1,14 @@
+-- database/migrations/postgres/000002_create_users_table.up.sql
+
+CREATE TABLE users (
+    id BIGSERIAL PRIMARY KEY,
+    email VARCHAR(255) NOT NULL UNIQUE,
+    password_hash VARCHAR(255) NOT NULL,
+    name VARCHAR(255),
+    is_active BOOLEAN DEFAULT FALSE,
+    activation_token VARCHAR(255) NULL,
+    reset_token VARCHAR(255) NULL,
+    reset_token_expiry TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
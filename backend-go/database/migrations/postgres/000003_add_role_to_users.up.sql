@@ -0,0 +1,3 @@
+-- database/migrations/postgres/000003_add_role_to_users.up.sql
+
+ALTER TABLE users ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'user';
@@ -0,0 +1,16 @@
+-- database/migrations/postgres/000006_create_outbox_messages_table.up.sql
+
+CREATE TABLE outbox_messages (
+    id BIGSERIAL PRIMARY KEY,
+    aggregate_type VARCHAR(50) NOT NULL,
+    aggregate_id BIGINT NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    payload TEXT NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    published_at TIMESTAMPTZ NULL
+);
+
+CREATE INDEX idx_outbox_aggregate ON outbox_messages (aggregate_type, aggregate_id);
+CREATE INDEX idx_outbox_messages_published_at ON outbox_messages (published_at);
@@ -0,0 +1,5 @@
+-- database/migrations/postgres/000009_add_oidc_to_users.down.sql
+
+DROP INDEX idx_users_oidc_identity;
+ALTER TABLE users DROP COLUMN oidc_subject;
+ALTER TABLE users DROP COLUMN oidc_issuer;
@@ -0,0 +1,8 @@
+-- database/migrations/postgres/000011_add_account_self_service_to_users.down.sql
+
+DROP INDEX idx_users_deletion_scheduled_at;
+DROP INDEX idx_users_email_change_token;
+ALTER TABLE users DROP COLUMN deletion_scheduled_at;
+ALTER TABLE users DROP COLUMN email_change_expiry;
+ALTER TABLE users DROP COLUMN email_change_token;
+ALTER TABLE users DROP COLUMN pending_email;
@@ -0,0 +1,9 @@
+-- database/migrations/postgres/000011_add_account_self_service_to_users.up.sql
+
+ALTER TABLE users ADD COLUMN pending_email VARCHAR(255) NULL;
+ALTER TABLE users ADD COLUMN email_change_token VARCHAR(255) NULL;
+ALTER TABLE users ADD COLUMN email_change_expiry TIMESTAMPTZ NULL;
+ALTER TABLE users ADD COLUMN deletion_scheduled_at TIMESTAMPTZ NULL;
+
+CREATE UNIQUE INDEX idx_users_email_change_token ON users (email_change_token);
+CREATE INDEX idx_users_deletion_scheduled_at ON users (deletion_scheduled_at);
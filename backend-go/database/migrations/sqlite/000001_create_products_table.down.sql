@@ -0,0 +1,3 @@
+-- database/migrations/sqlite/000001_create_products_table.down.sql
+
+DROP TABLE products;
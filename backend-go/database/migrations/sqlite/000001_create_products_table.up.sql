@@ -0,0 +1,14 @@
+-- database/migrations/sqlite/000001_create_products_table.up.sql
+
+CREATE TABLE products (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    description TEXT,
+    price INTEGER NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    deleted_at DATETIME NULL
+);
+
+-- Opsional: Tambahkan index untuk pencarian nama produk yang lebih cepat
+CREATE INDEX idx_products_name ON products (name);
@@ -0,0 +1,14 @@
+-- database/migrations/sqlite/000002_create_users_table.up.sql
+
+CREATE TABLE users (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    email TEXT NOT NULL UNIQUE,
+    password_hash TEXT NOT NULL,
+    name TEXT,
+    is_active BOOLEAN DEFAULT FALSE,
+    activation_token TEXT NULL,
+    reset_token TEXT NULL,
+    reset_token_expiry DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
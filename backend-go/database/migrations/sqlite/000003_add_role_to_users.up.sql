@@ -0,0 +1,3 @@
+-- database/migrations/sqlite/000003_add_role_to_users.up.sql
+
+ALTER TABLE users ADD COLUMN role TEXT NOT NULL DEFAULT 'user';
@@ -0,0 +1,3 @@
+-- database/migrations/sqlite/000004_create_audit_logs_table.down.sql
+
+DROP TABLE audit_logs;
@@ -0,0 +1,3 @@
+-- database/migrations/sqlite/000006_create_outbox_messages_table.down.sql
+
+DROP TABLE outbox_messages;
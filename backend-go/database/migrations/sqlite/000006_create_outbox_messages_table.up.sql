@@ -0,0 +1,16 @@
+-- database/migrations/sqlite/000006_create_outbox_messages_table.up.sql
+
+CREATE TABLE outbox_messages (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    aggregate_type TEXT NOT NULL,
+    aggregate_id INTEGER NOT NULL,
+    event_type TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    published_at DATETIME NULL
+);
+
+CREATE INDEX idx_outbox_aggregate ON outbox_messages (aggregate_type, aggregate_id);
+CREATE INDEX idx_outbox_messages_published_at ON outbox_messages (published_at);
@@ -0,0 +1,6 @@
+-- database/migrations/sqlite/000009_add_oidc_to_users.up.sql
+
+ALTER TABLE users ADD COLUMN oidc_issuer TEXT NULL;
+ALTER TABLE users ADD COLUMN oidc_subject TEXT NULL;
+
+CREATE UNIQUE INDEX idx_users_oidc_identity ON users (oidc_issuer, oidc_subject);
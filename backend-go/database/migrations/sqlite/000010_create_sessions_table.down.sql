@@ -0,0 +1,3 @@
+-- database/migrations/sqlite/000010_create_sessions_table.down.sql
+
+DROP TABLE sessions;
@@ -0,0 +1,9 @@
+-- database/migrations/sqlite/000011_add_account_self_service_to_users.up.sql
+
+ALTER TABLE users ADD COLUMN pending_email TEXT NULL;
+ALTER TABLE users ADD COLUMN email_change_token TEXT NULL;
+ALTER TABLE users ADD COLUMN email_change_expiry DATETIME NULL;
+ALTER TABLE users ADD COLUMN deletion_scheduled_at DATETIME NULL;
+
+CREATE UNIQUE INDEX idx_users_email_change_token ON users (email_change_token);
+CREATE INDEX idx_users_deletion_scheduled_at ON users (deletion_scheduled_at);
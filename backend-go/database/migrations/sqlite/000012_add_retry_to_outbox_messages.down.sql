@@ -0,0 +1,5 @@
+-- database/migrations/sqlite/000012_add_retry_to_outbox_messages.down.sql
+
+DROP INDEX idx_outbox_messages_dead_letter_at;
+ALTER TABLE outbox_messages DROP COLUMN dead_letter_at;
+ALTER TABLE outbox_messages DROP COLUMN next_attempt_at;
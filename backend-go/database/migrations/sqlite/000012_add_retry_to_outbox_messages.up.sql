@@ -0,0 +1,6 @@
+-- database/migrations/sqlite/000012_add_retry_to_outbox_messages.up.sql
+
+ALTER TABLE outbox_messages ADD COLUMN next_attempt_at DATETIME NULL;
+ALTER TABLE outbox_messages ADD COLUMN dead_letter_at DATETIME NULL;
+
+CREATE INDEX idx_outbox_messages_dead_letter_at ON outbox_messages (dead_letter_at);
@@ -0,0 +1,492 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Error yang dikembalikan oleh Migrator
+var (
+	ErrMigrationLocked  = errors.New("migrasi sedang dijalankan oleh proses lain")
+	ErrChecksumMismatch = errors.New("isi file migrasi berbeda dengan yang sudah diterapkan")
+	ErrMigrationMissing = errors.New("migrasi yang sudah diterapkan tidak ditemukan di file")
+	ErrUnknownVersion   = errors.New("versi migrasi tidak dikenal")
+	ErrLegacySchema     = errors.New("database lama tanpa schema_migrations berisi tabel selain products; periksa skema lalu jalankan `migrate baseline VERSION`")
+)
+
+// LegacyBaselineVersion adalah versi terakhir yang tabelnya dibuat oleh AutoMigrate versi lama.
+// AutoMigrate hanya pernah membuat tabel products (migrasi 000001).
+const LegacyBaselineVersion uint = 1
+
+// migrationLock adalah advisory lock yang dipakai bersama semua instance saat migrasi
+var migrationLock = advisoryLock{name: "schema_migrations", key: 7260531907, row: 1}
+
+// insertAppliedSQL mencatat satu migrasi di schema_migrations
+const insertAppliedSQL = "INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES (?, ?, ?, ?)"
+
+// MigrationStatus menggambarkan status satu versi migrasi di database.
+type MigrationStatus struct {
+	Version   uint       `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+	Drift     bool       `json:"drift"`   // File berubah setelah diterapkan
+	Missing   bool       `json:"missing"` // Tercatat diterapkan, tetapi file-nya tidak ada
+}
+
+// appliedMigration adalah satu baris tabel schema_migrations
+type appliedMigration struct {
+	Version   uint
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+// Migrator menjalankan migrasi SQL berversi dan mencatatnya di tabel schema_migrations.
+// Setiap operasi memegang advisory lock sehingga beberapa instance aplikasi yang start
+// bersamaan tidak menjalankan migrasi yang sama dua kali.
+type Migrator struct {
+	DB         *gorm.DB
+	Migrations []Migration
+
+	LockTimeout    time.Duration // Lama menunggu lock sebelum ErrMigrationLocked
+	LockStaleAfter time.Duration // SQLite: lock yang lebih tua dari ini dianggap milik proses yang mati
+}
+
+// NewMigrator adalah konstruktor untuk Migrator
+func NewMigrator(db *gorm.DB, migrations []Migration) *Migrator {
+	return &Migrator{
+		DB:             db,
+		Migrations:     migrations,
+		LockTimeout:    30 * time.Second,
+		LockStaleAfter: 10 * time.Minute,
+	}
+}
+
+// Up menerapkan semua migrasi yang belum diterapkan, terurut naik.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var ran []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn, applied map[uint]appliedMigration) error {
+		for _, mig := range m.Migrations {
+			if _, ok := applied[mig.Version]; ok {
+				continue
+			}
+			if err := m.apply(ctx, conn, mig, true); err != nil {
+				return err
+			}
+			ran = append(ran, mig)
+		}
+		return nil
+	})
+	return ran, err
+}
+
+// Down membatalkan steps migrasi terakhir yang sudah diterapkan.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var ran []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn, applied map[uint]appliedMigration) error {
+		for _, mig := range m.appliedDescending(applied) {
+			if len(ran) >= steps {
+				break
+			}
+			if err := m.apply(ctx, conn, mig, false); err != nil {
+				return err
+			}
+			ran = append(ran, mig)
+		}
+		return nil
+	})
+	return ran, err
+}
+
+// Goto memindahkan skema ke versi tertentu: migrasi <= version diterapkan dan
+// migrasi > version dibatalkan. Version 0 membatalkan semua migrasi.
+func (m *Migrator) Goto(ctx context.Context, version uint) ([]Migration, error) {
+	if version != 0 && m.find(version) == nil {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+
+	var ran []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn, applied map[uint]appliedMigration) error {
+		// Batalkan dulu yang di atas target, dari versi tertinggi
+		for _, mig := range m.appliedDescending(applied) {
+			if mig.Version <= version {
+				break
+			}
+			if err := m.apply(ctx, conn, mig, false); err != nil {
+				return err
+			}
+			ran = append(ran, mig)
+		}
+		for _, mig := range m.Migrations {
+			if mig.Version > version {
+				break
+			}
+			if _, ok := applied[mig.Version]; ok {
+				continue
+			}
+			if err := m.apply(ctx, conn, mig, true); err != nil {
+				return err
+			}
+			ran = append(ran, mig)
+		}
+		return nil
+	})
+	return ran, err
+}
+
+// Baseline menandai semua migrasi sampai version sebagai sudah diterapkan tanpa
+// menjalankan SQL-nya. Dipakai untuk database lama yang tabelnya dibuat oleh AutoMigrate.
+func (m *Migrator) Baseline(ctx context.Context, version uint) error {
+	if m.find(version) == nil {
+		return fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+	return m.withLock(ctx, func(conn *sql.Conn, applied map[uint]appliedMigration) error {
+		for _, mig := range m.Migrations {
+			if mig.Version > version {
+				break
+			}
+			if _, ok := applied[mig.Version]; ok {
+				continue
+			}
+			if err := m.record(ctx, conn, mig); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// BaselineLegacy mendeteksi database yang dibuat oleh AutoMigrate versi lama: belum ada
+// migrasi yang tercatat, tetapi tabel products sudah ada. Database seperti itu ditandai
+// sudah diterapkan sampai LegacyBaselineVersion agar Up membuat tabel-tabel berikutnya.
+// Mengembalikan true jika baseline dilakukan. Jika tabel users juga sudah ada, skemanya
+// tidak bisa dipastikan dan ErrLegacySchema dikembalikan.
+func (m *Migrator) BaselineLegacy(ctx context.Context) (bool, error) {
+	baselined := false
+	err := m.withLock(ctx, func(conn *sql.Conn, applied map[uint]appliedMigration) error {
+		if len(applied) > 0 {
+			return nil
+		}
+		legacy, err := m.hasTable(ctx, conn, "products")
+		if err != nil || !legacy {
+			return err
+		}
+		hasUsers, err := m.hasTable(ctx, conn, "users")
+		if err != nil {
+			return err
+		}
+		if hasUsers {
+			return ErrLegacySchema
+		}
+		for _, mig := range m.Migrations {
+			if mig.Version > LegacyBaselineVersion {
+				break
+			}
+			if err := m.record(ctx, conn, mig); err != nil {
+				return err
+			}
+		}
+		baselined = true
+		return nil
+	})
+	return baselined, err
+}
+
+// Status mengembalikan status setiap migrasi, termasuk drift checksum.
+// Berbeda dengan operasi lain, drift tidak menghasilkan error di sini.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	conn, err := m.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := m.ensureTables(ctx, conn); err != nil {
+		return nil, err
+	}
+	applied, err := m.readApplied(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.Migrations))
+	for _, mig := range m.Migrations {
+		status := MigrationStatus{Version: mig.Version, Name: mig.Name}
+		if row, ok := applied[mig.Version]; ok {
+			appliedAt := row.AppliedAt
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+			status.Drift = row.Checksum != mig.Checksum
+		}
+		statuses = append(statuses, status)
+	}
+	for _, row := range applied {
+		if m.find(row.Version) == nil {
+			appliedAt := row.AppliedAt
+			statuses = append(statuses, MigrationStatus{
+				Version: row.Version, Name: row.Name, Applied: true, AppliedAt: &appliedAt, Missing: true,
+			})
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// withLock menjalankan fn pada satu koneksi khusus yang memegang advisory lock,
+// setelah memastikan migrasi yang sudah diterapkan tidak mengalami drift.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn, applied map[uint]appliedMigration) error) error {
+	conn, err := m.conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := m.ensureTables(ctx, conn); err != nil {
+		return err
+	}
+	if err := m.lock(ctx, conn); err != nil {
+		return err
+	}
+	defer func() {
+		// Lock tetap dilepas walaupun ctx sudah dibatalkan
+		if err := migrationLock.unlock(context.Background(), conn, dialectName(m.DB)); err != nil {
+			slog.ErrorContext(ctx, "Gagal melepas lock migrasi", "error", err)
+		}
+	}()
+
+	applied, err := m.readApplied(ctx, conn)
+	if err != nil {
+		return err
+	}
+	if err := m.verify(applied); err != nil {
+		return err
+	}
+	return fn(conn, applied)
+}
+
+// conn mengambil satu koneksi dari pool; lock MySQL/PostgreSQL terikat pada sesi koneksi
+func (m *Migrator) conn(ctx context.Context) (*sql.Conn, error) {
+	sqlDB, err := m.DB.DB()
+	if err != nil {
+		return nil, err
+	}
+	return sqlDB.Conn(ctx)
+}
+
+// transactional menunjukkan apakah DDL dialek bisa di-rollback dalam transaksi.
+// MySQL melakukan implicit commit pada setiap DDL.
+func (m *Migrator) transactional() bool {
+	return dialectName(m.DB) != "mysql"
+}
+
+// bind mengganti placeholder ? menjadi $n untuk PostgreSQL
+func (m *Migrator) bind(query string) string {
+	if dialectName(m.DB) != "postgres" {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// ensureTables membuat tabel schema_migrations (dan tabel lock untuk SQLite) jika belum ada
+func (m *Migrator) ensureTables(ctx context.Context, conn *sql.Conn) error {
+	var statements []string
+	switch dialectName(m.DB) {
+	case "mysql":
+		statements = []string{`CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			checksum VARCHAR(64) NOT NULL,
+			applied_at DATETIME NOT NULL
+		)`}
+	case "postgres":
+		statements = []string{`CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			checksum VARCHAR(64) NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL
+		)`}
+	default:
+		statements = []string{
+			`CREATE TABLE IF NOT EXISTS schema_migrations (
+				version INTEGER PRIMARY KEY,
+				name TEXT NOT NULL,
+				checksum TEXT NOT NULL,
+				applied_at DATETIME NOT NULL
+			)`,
+			sqliteLockTableSQL,
+		}
+	}
+	for _, stmt := range statements {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("gagal membuat tabel schema_migrations: %w", err)
+		}
+	}
+	return nil
+}
+
+// lock menunggu advisory lock sampai LockTimeout
+func (m *Migrator) lock(ctx context.Context, conn *sql.Conn) error {
+	deadline := time.Now().Add(m.LockTimeout)
+	for {
+		acquired, err := migrationLock.tryLock(ctx, conn, dialectName(m.DB), m.LockStaleAfter)
+		if err != nil {
+			return fmt.Errorf("gagal mengambil lock migrasi: %w", err)
+		}
+		if acquired {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return ErrMigrationLocked
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+}
+
+// hasTable memeriksa keberadaan tabel lewat conn; db.Migrator() tidak bisa dipakai
+// karena SQLite hanya punya satu koneksi yang sedang dipegang lock
+func (m *Migrator) hasTable(ctx context.Context, conn *sql.Conn, table string) (bool, error) {
+	var query string
+	switch dialectName(m.DB) {
+	case "mysql":
+		query = "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?"
+	case "postgres":
+		query = "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = CURRENT_SCHEMA() AND table_name = $1"
+	default:
+		query = "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?"
+	}
+	var count int
+	if err := conn.QueryRowContext(ctx, query, table).Scan(&count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// readApplied membaca semua baris schema_migrations
+func (m *Migrator) readApplied(ctx context.Context, conn *sql.Conn) (map[uint]appliedMigration, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version, name, checksum, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[uint]appliedMigration)
+	for rows.Next() {
+		var row appliedMigration
+		if err := rows.Scan(&row.Version, &row.Name, &row.Checksum, &row.AppliedAt); err != nil {
+			return nil, err
+		}
+		applied[row.Version] = row
+	}
+	return applied, rows.Err()
+}
+
+// verify memastikan setiap migrasi yang sudah diterapkan masih ada dan isinya tidak berubah
+func (m *Migrator) verify(applied map[uint]appliedMigration) error {
+	for _, row := range applied {
+		mig := m.find(row.Version)
+		if mig == nil {
+			return fmt.Errorf("%w: %d_%s", ErrMigrationMissing, row.Version, row.Name)
+		}
+		if mig.Checksum != row.Checksum {
+			return fmt.Errorf("%w: %d_%s", ErrChecksumMismatch, mig.Version, mig.Name)
+		}
+	}
+	return nil
+}
+
+// apply menjalankan SQL up atau down satu migrasi dan memperbarui schema_migrations.
+// Pada dialek transaksional keduanya atomik; pada MySQL kegagalan di tengah file
+// dapat meninggalkan skema setengah jadi yang harus diperbaiki manual.
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, mig Migration, up bool) error {
+	body, direction := mig.Down, "down"
+	if up {
+		body, direction = mig.Up, "up"
+	}
+
+	run := func(exec func(ctx context.Context, query string, args ...interface{}) (sql.Result, error)) error {
+		for _, stmt := range splitStatements(body) {
+			if _, err := exec(ctx, stmt); err != nil {
+				return err
+			}
+		}
+		if up {
+			_, err := exec(ctx, m.bind(insertAppliedSQL), mig.Version, mig.Name, mig.Checksum, time.Now().UTC())
+			return err
+		}
+		_, err := exec(ctx, m.bind("DELETE FROM schema_migrations WHERE version = ?"), mig.Version)
+		return err
+	}
+
+	var err error
+	if m.transactional() {
+		var tx *sql.Tx
+		if tx, err = conn.BeginTx(ctx, nil); err == nil {
+			if err = run(tx.ExecContext); err != nil {
+				tx.Rollback()
+			} else {
+				err = tx.Commit()
+			}
+		}
+	} else {
+		err = run(conn.ExecContext)
+	}
+	if err != nil {
+		if !m.transactional() {
+			return fmt.Errorf("migrasi %s %d_%s gagal (periksa skema secara manual): %w", direction, mig.Version, mig.Name, err)
+		}
+		return fmt.Errorf("migrasi %s %d_%s gagal: %w", direction, mig.Version, mig.Name, err)
+	}
+	slog.InfoContext(ctx, "Migrasi berhasil", "direction", direction, "version", mig.Version, "name", mig.Name)
+	return nil
+}
+
+// record mencatat migrasi sebagai sudah diterapkan tanpa menjalankan SQL-nya
+func (m *Migrator) record(ctx context.Context, conn *sql.Conn, mig Migration) error {
+	_, err := conn.ExecContext(ctx, m.bind(insertAppliedSQL), mig.Version, mig.Name, mig.Checksum, time.Now().UTC())
+	return err
+}
+
+// find mencari migrasi berdasarkan versi
+func (m *Migrator) find(version uint) *Migration {
+	for i := range m.Migrations {
+		if m.Migrations[i].Version == version {
+			return &m.Migrations[i]
+		}
+	}
+	return nil
+}
+
+// appliedDescending mengembalikan migrasi yang sudah diterapkan, dari versi tertinggi
+func (m *Migrator) appliedDescending(applied map[uint]appliedMigration) []Migration {
+	var result []Migration
+	for i := len(m.Migrations) - 1; i >= 0; i-- {
+		if _, ok := applied[m.Migrations[i].Version]; ok {
+			result = append(result, m.Migrations[i])
+		}
+	}
+	return result
+}
@@ -0,0 +1,286 @@
+package database_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"fullstack-crud-project-01/backend-go/database"
+)
+
+// openSQLite membuka database SQLite berbasis file di direktori sementara.
+// Dua pemanggilan dengan path yang sama mensimulasikan dua proses aplikasi.
+func openSQLite(t *testing.T, path string) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(path+"?_pragma=busy_timeout(5000)"), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	return db
+}
+
+func tableExists(t *testing.T, db *gorm.DB, table string) bool {
+	t.Helper()
+	return db.Migrator().HasTable(table)
+}
+
+func versions(migrations []database.Migration) []uint {
+	result := make([]uint, 0, len(migrations))
+	for _, m := range migrations {
+		result = append(result, m.Version)
+	}
+	return result
+}
+
+func TestEmbeddedMigrations_AllDialectsInSync(t *testing.T) {
+	mysql, err := database.EmbeddedMigrations("mysql")
+	require.NoError(t, err)
+
+	for _, dialect := range []string{"postgres", "sqlite"} {
+		migrations, err := database.EmbeddedMigrations(dialect)
+		require.NoError(t, err, dialect)
+		require.Len(t, migrations, len(mysql), dialect)
+		for i := range mysql {
+			assert.Equal(t, mysql[i].Version, migrations[i].Version, dialect)
+			assert.Equal(t, mysql[i].Name, migrations[i].Name, dialect)
+		}
+	}
+
+	_, err = database.EmbeddedMigrations("oracle")
+	assert.Error(t, err)
+}
+
+func TestLoadMigrations_RequiresUpAndDown(t *testing.T) {
+	_, err := database.LoadMigrations(fstest.MapFS{
+		"000001_a.up.sql": {Data: []byte("CREATE TABLE a (id INTEGER);")},
+	})
+	assert.Error(t, err)
+}
+
+func TestMigrator_UpDownGotoStatus(t *testing.T) {
+	db := openSQLite(t, filepath.Join(t.TempDir(), "app.db"))
+	migrations, err := database.EmbeddedMigrations("sqlite")
+	require.NoError(t, err)
+	migrator := database.NewMigrator(db, migrations)
+	ctx := context.Background()
+	latest := migrations[len(migrations)-1].Version
+
+	// Up menerapkan semua migrasi, pemanggilan kedua tidak melakukan apa-apa
+	ran, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Len(t, ran, len(migrations))
+	assert.True(t, tableExists(t, db, "users"))
+	assert.True(t, tableExists(t, db, "outbox_messages"))
+
+	ran, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, ran)
+
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	require.Len(t, statuses, len(migrations))
+	for _, s := range statuses {
+		assert.True(t, s.Applied, "versi %d", s.Version)
+		assert.NotNil(t, s.AppliedAt)
+		assert.False(t, s.Drift)
+	}
+
+	// Down membatalkan dari versi tertinggi
+	ran, err = migrator.Down(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{latest, latest - 1}, versions(ran))
+	assert.False(t, db.Migrator().HasColumn("users", "pending_email"))
+
+	// Goto naik ke satu versi tertentu
+	ran, err = migrator.Goto(ctx, latest-1)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{latest - 1}, versions(ran))
+	statuses, err = migrator.Status(ctx)
+	require.NoError(t, err)
+	assert.False(t, statuses[len(statuses)-1].Applied)
+
+	// Goto 0 membatalkan semuanya
+	ran, err = migrator.Goto(ctx, 0)
+	require.NoError(t, err)
+	assert.Len(t, ran, len(migrations)-1)
+	assert.False(t, tableExists(t, db, "products"))
+
+	_, err = migrator.Goto(ctx, 999)
+	assert.ErrorIs(t, err, database.ErrUnknownVersion)
+}
+
+func TestMigrator_DetectsDrift(t *testing.T) {
+	db := openSQLite(t, filepath.Join(t.TempDir(), "app.db"))
+	ctx := context.Background()
+	files := fstest.MapFS{
+		"000001_create_a.up.sql":   {Data: []byte("CREATE TABLE a (id INTEGER);")},
+		"000001_create_a.down.sql": {Data: []byte("DROP TABLE a;")},
+		"000002_create_b.up.sql":   {Data: []byte("CREATE TABLE b (id INTEGER);")},
+		"000002_create_b.down.sql": {Data: []byte("DROP TABLE b;")},
+	}
+	migrations, err := database.LoadMigrations(files)
+	require.NoError(t, err)
+	_, err = database.NewMigrator(db, migrations).Up(ctx)
+	require.NoError(t, err)
+
+	// File yang sudah diterapkan diubah
+	files["000001_create_a.up.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE a (id INTEGER, name TEXT);")}
+	changed, err := database.LoadMigrations(files)
+	require.NoError(t, err)
+	migrator := database.NewMigrator(db, changed)
+
+	_, err = migrator.Up(ctx)
+	assert.ErrorIs(t, err, database.ErrChecksumMismatch)
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	assert.True(t, statuses[0].Drift)
+	assert.False(t, statuses[1].Drift)
+
+	// File yang sudah diterapkan dihapus
+	_, err = database.NewMigrator(db, migrations[:1]).Up(ctx)
+	assert.ErrorIs(t, err, database.ErrMigrationMissing)
+}
+
+func TestMigrator_FailedMigrationIsRolledBack(t *testing.T) {
+	db := openSQLite(t, filepath.Join(t.TempDir(), "app.db"))
+	migrations, err := database.LoadMigrations(fstest.MapFS{
+		"000001_create_a.up.sql":   {Data: []byte("CREATE TABLE a (id INTEGER);")},
+		"000001_create_a.down.sql": {Data: []byte("DROP TABLE a;")},
+		"000002_broken.up.sql":     {Data: []byte("CREATE TABLE b (id INTEGER);\nTHIS IS NOT SQL;")},
+		"000002_broken.down.sql":   {Data: []byte("DROP TABLE b;")},
+	})
+	require.NoError(t, err)
+	migrator := database.NewMigrator(db, migrations)
+
+	ran, err := migrator.Up(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, []uint{1}, versions(ran))
+	assert.True(t, tableExists(t, db, "a"))
+	assert.False(t, tableExists(t, db, "b"), "Statement pertama migrasi gagal harus di-rollback")
+
+	statuses, err := migrator.Status(context.Background())
+	require.NoError(t, err)
+	assert.True(t, statuses[0].Applied)
+	assert.False(t, statuses[1].Applied)
+}
+
+func TestMigrator_Lock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.db")
+	ctx := context.Background()
+	migrations, err := database.EmbeddedMigrations("sqlite")
+	require.NoError(t, err)
+
+	// Proses lain sedang memegang lock
+	other := openSQLite(t, path)
+	_, err = database.NewMigrator(other, migrations).Status(ctx) // membuat tabel lock
+	require.NoError(t, err)
+	require.NoError(t, other.Exec("INSERT INTO schema_migrations_lock (id, locked_at) VALUES (1, ?)", time.Now().UTC()).Error)
+
+	migrator := database.NewMigrator(openSQLite(t, path), migrations)
+	migrator.LockTimeout = 300 * time.Millisecond
+	_, err = migrator.Up(ctx)
+	assert.ErrorIs(t, err, database.ErrMigrationLocked)
+
+	// Lock yang sudah basi (proses pemegangnya mati) diambil alih
+	migrator.LockStaleAfter = 100 * time.Millisecond
+	time.Sleep(150 * time.Millisecond)
+	ran, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Len(t, ran, len(migrations))
+
+	// Lock dilepas setelah selesai
+	var count int64
+	other.Table("schema_migrations_lock").Count(&count)
+	assert.Zero(t, count)
+}
+
+func TestMigrator_BaselineLegacy(t *testing.T) {
+	ctx := context.Background()
+	migrations, err := database.EmbeddedMigrations("sqlite")
+	require.NoError(t, err)
+
+	// Database baru tidak di-baseline
+	fresh := database.NewMigrator(openSQLite(t, filepath.Join(t.TempDir(), "fresh.db")), migrations)
+	baselined, err := fresh.BaselineLegacy(ctx)
+	require.NoError(t, err)
+	assert.False(t, baselined)
+
+	// Database lama: AutoMigrate hanya pernah membuat tabel products
+	db := openSQLite(t, filepath.Join(t.TempDir(), "legacy.db"))
+	require.NoError(t, db.Exec("CREATE TABLE products (id INTEGER PRIMARY KEY, name TEXT, description TEXT, price INTEGER, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)").Error)
+	migrator := database.NewMigrator(db, migrations)
+
+	baselined, err = migrator.BaselineLegacy(ctx)
+	require.NoError(t, err)
+	assert.True(t, baselined)
+
+	ran, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Len(t, ran, len(migrations)-1)
+	assert.Equal(t, uint(2), ran[0].Version)
+	assert.True(t, tableExists(t, db, "users"))
+
+	// Setelah ada migrasi tercatat, tidak ada baseline lagi
+	baselined, err = migrator.BaselineLegacy(ctx)
+	require.NoError(t, err)
+	assert.False(t, baselined)
+
+	// Tabel users yang dibuat manual membuat skema tidak bisa dipastikan
+	manual := openSQLite(t, filepath.Join(t.TempDir(), "manual.db"))
+	require.NoError(t, manual.Exec("CREATE TABLE products (id INTEGER PRIMARY KEY)").Error)
+	require.NoError(t, manual.Exec("CREATE TABLE users (id INTEGER PRIMARY KEY)").Error)
+	_, err = database.NewMigrator(manual, migrations).BaselineLegacy(ctx)
+	assert.ErrorIs(t, err, database.ErrLegacySchema)
+}
+
+func TestLeaderLock_OneLeaderPerDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.db")
+	ctx := context.Background()
+
+	// Dua proses aplikasi yang memakai database yang sama
+	first := database.NewLeaderLock(openSQLite(t, path), "outbox_relay")
+	second := database.NewLeaderLock(openSQLite(t, path), "outbox_relay")
+	other := database.NewLeaderLock(openSQLite(t, path), "another_job")
+
+	leader, err := first.Acquire(ctx)
+	require.NoError(t, err)
+	assert.True(t, leader)
+	leader, err = first.Acquire(ctx) // Memperbarui lock yang sudah dipegang
+	require.NoError(t, err)
+	assert.True(t, leader)
+
+	leader, err = second.Acquire(ctx)
+	require.NoError(t, err)
+	assert.False(t, leader)
+
+	// Nama lock lain tidak saling menghalangi
+	leader, err = other.Acquire(ctx)
+	require.NoError(t, err)
+	assert.True(t, leader)
+
+	// Setelah dilepas, instance lain bisa menjadi leader
+	require.NoError(t, first.Release(ctx))
+	leader, err = second.Acquire(ctx)
+	require.NoError(t, err)
+	assert.True(t, leader)
+
+	// Leader yang berhenti memperbarui lock (proses mati) diambil alih setelah StaleAfter
+	first.StaleAfter = 100 * time.Millisecond
+	time.Sleep(150 * time.Millisecond)
+	leader, err = first.Acquire(ctx)
+	require.NoError(t, err)
+	assert.True(t, leader)
+	leader, err = second.Acquire(ctx)
+	require.NoError(t, err)
+	assert.False(t, leader, "Leader lama harus tahu lock-nya sudah diambil alih")
+}
@@ -0,0 +1,24 @@
+package dto
+
+// UpdateProfileRequest adalah DTO untuk mengubah profil pengguna yang sedang login
+type UpdateProfileRequest struct {
+	Name string `json:"name" binding:"required,max=255"`
+}
+
+// ChangePasswordRequest adalah DTO untuk mengganti password. current_password boleh kosong
+// hanya untuk akun OIDC yang belum punya password.
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password" binding:"required,min=8"`
+}
+
+// ChangeEmailRequest adalah DTO untuk meminta penggantian email (dikonfirmasi lewat email baru)
+type ChangeEmailRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password"`
+}
+
+// DeleteAccountRequest adalah DTO untuk meminta penghapusan akun
+type DeleteAccountRequest struct {
+	Password string `json:"password"`
+}
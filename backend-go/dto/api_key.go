@@ -0,0 +1,10 @@
+package dto
+
+import "time"
+
+// CreateAPIKeyRequest adalah DTO untuk membuat API key pribadi
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" binding:"required,max=100"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1"` // products:read dan/atau products:write
+	ExpiresAt *time.Time `json:"expires_at"`                      // Opsional (RFC 3339); kosong berarti tidak kedaluwarsa
+}
@@ -0,0 +1,12 @@
+package dto
+
+// MFALoginRequest adalah DTO langkah kedua login untuk pengguna dengan TOTP aktif
+type MFALoginRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required"` // Kode TOTP 6 digit atau kode pemulihan
+}
+
+// MFACodeRequest adalah DTO untuk aksi MFA yang membutuhkan kode TOTP atau kode pemulihan
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
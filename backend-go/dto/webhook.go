@@ -0,0 +1,8 @@
+package dto
+
+// WebhookSubscriptionRequest adalah DTO untuk mendaftarkan subscription webhook baru
+type WebhookSubscriptionRequest struct {
+	URL        string   `json:"url" binding:"required,url"`
+	Secret     string   `json:"secret"` // Opsional: dibuat otomatis jika kosong
+	EventTypes []string `json:"event_types" binding:"required,min=1"`
+}
@@ -0,0 +1,144 @@
+package events
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Tipe event perubahan produk
+const (
+	ProductCreated = "product.created"
+	ProductUpdated = "product.updated"
+	ProductDeleted = "product.deleted"
+)
+
+// subscriberBuffer adalah kapasitas channel setiap subscriber.
+// Subscriber yang tertinggal lebih dari ini akan diputus agar tidak memblokir publisher.
+const subscriberBuffer = 64
+
+// Event adalah satu kejadian domain yang dikirim melalui Bus.
+type Event struct {
+	ID          uint64          `json:"id"`
+	Type        string          `json:"type"`
+	AggregateID uint            `json:"aggregate_id"` // ID resource yang berubah (mis. ID produk)
+	Data        json.RawMessage `json:"data"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+}
+
+// Subscription mewakili satu pendengar event.
+// Channel C ditutup jika subscriber terlalu lambat atau Cancel dipanggil.
+type Subscription struct {
+	C <-chan Event
+
+	ch  chan Event
+	bus *Bus
+}
+
+// Cancel berhenti mendengarkan event dan melepaskan resource subscription.
+func (s *Subscription) Cancel() {
+	s.bus.unsubscribe(s)
+}
+
+// Bus adalah event bus in-process dengan log event terbatas (ring buffer)
+// sehingga klien yang tersambung ulang bisa melanjutkan dari event terakhir yang diterima.
+type Bus struct {
+	mu          sync.Mutex
+	capacity    int
+	log         []Event
+	lastID      uint64
+	subscribers map[*Subscription]struct{}
+}
+
+// NewBus membuat Bus yang menyimpan maksimal `capacity` event terakhir.
+func NewBus(capacity int) *Bus {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &Bus{
+		capacity:    capacity,
+		log:         make([]Event, 0, capacity),
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish memberi ID pada event baru, menyimpannya di log dan meneruskannya ke semua subscriber.
+func (b *Bus) Publish(eventType string, aggregateID uint, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	event := Event{
+		ID:          b.lastID,
+		Type:        eventType,
+		AggregateID: aggregateID,
+		Data:        data,
+		OccurredAt:  time.Now(),
+	}
+
+	if len(b.log) == b.capacity {
+		copy(b.log, b.log[1:])
+		b.log = b.log[:len(b.log)-1]
+	}
+	b.log = append(b.log, event)
+
+	for sub := range b.subscribers {
+		select {
+		case sub.ch <- event:
+		default:
+			// Subscriber terlalu lambat: putuskan, klien akan resume via Last-Event-ID
+			delete(b.subscribers, sub)
+			close(sub.ch)
+		}
+	}
+	return nil
+}
+
+// Subscribe mendaftarkan subscriber baru dan mengembalikan event di log dengan ID > afterID
+// untuk diputar ulang. complete bernilai false jika sebagian event setelah afterID sudah
+// keluar dari log (atau afterID tidak dikenal), sehingga klien perlu memuat ulang datanya.
+func (b *Bus) Subscribe(afterID uint64) (sub *Subscription, replay []Event, complete bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan Event, subscriberBuffer)
+	sub = &Subscription{C: ch, ch: ch, bus: b}
+	b.subscribers[sub] = struct{}{}
+
+	if afterID == 0 {
+		return sub, nil, true
+	}
+	if afterID > b.lastID {
+		// ID berasal dari proses sebelumnya (mis. setelah restart) atau tidak valid
+		return sub, nil, false
+	}
+
+	complete = len(b.log) == 0 || b.log[0].ID <= afterID+1
+	for _, event := range b.log {
+		if event.ID > afterID {
+			replay = append(replay, event)
+		}
+	}
+	return sub, replay, complete
+}
+
+// LastID mengembalikan ID event terakhir yang dipublikasikan.
+func (b *Bus) LastID() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.lastID
+}
+
+func (b *Bus) unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.subscribers[sub]; ok {
+		delete(b.subscribers, sub)
+		close(sub.ch)
+	}
+}
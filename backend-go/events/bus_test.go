@@ -0,0 +1,75 @@
+package events_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"fullstack-crud-project-01/backend-go/events"
+)
+
+func TestBus_PublishAndSubscribe(t *testing.T) {
+	bus := events.NewBus(10)
+
+	sub, replay, complete := bus.Subscribe(0)
+	defer sub.Cancel()
+	assert.Empty(t, replay)
+	assert.True(t, complete)
+
+	err := bus.Publish(events.ProductCreated, 7, map[string]string{"name": "Laptop"})
+	assert.NoError(t, err)
+
+	event := <-sub.C
+	assert.Equal(t, uint64(1), event.ID)
+	assert.Equal(t, events.ProductCreated, event.Type)
+	assert.Equal(t, uint(7), event.AggregateID)
+	assert.JSONEq(t, `{"name":"Laptop"}`, string(event.Data))
+}
+
+func TestBus_ResumeFromLastEventID(t *testing.T) {
+	bus := events.NewBus(3)
+	for i := uint(1); i <= 5; i++ {
+		assert.NoError(t, bus.Publish(events.ProductUpdated, i, nil))
+	}
+
+	// Log hanya menyimpan event 3..5
+	t.Run("Complete_WithinLog", func(t *testing.T) {
+		sub, replay, complete := bus.Subscribe(3)
+		defer sub.Cancel()
+		assert.True(t, complete)
+		assert.Len(t, replay, 2)
+		assert.Equal(t, uint64(4), replay[0].ID)
+		assert.Equal(t, uint64(5), replay[1].ID)
+	})
+
+	t.Run("Incomplete_EvictedFromLog", func(t *testing.T) {
+		sub, replay, complete := bus.Subscribe(1)
+		defer sub.Cancel()
+		assert.False(t, complete)
+		assert.Len(t, replay, 3)
+	})
+
+	t.Run("Incomplete_UnknownID", func(t *testing.T) {
+		sub, replay, complete := bus.Subscribe(99)
+		defer sub.Cancel()
+		assert.False(t, complete)
+		assert.Empty(t, replay)
+	})
+}
+
+func TestBus_SlowSubscriberIsDropped(t *testing.T) {
+	bus := events.NewBus(1000)
+	sub, _, _ := bus.Subscribe(0)
+
+	// Jangan baca channel: setelah buffer penuh subscriber harus diputus, bukan memblokir
+	for i := 0; i < 200; i++ {
+		assert.NoError(t, bus.Publish(events.ProductCreated, uint(i), nil))
+	}
+
+	received := 0
+	for range sub.C {
+		received++
+	}
+	assert.Less(t, received, 200)
+	sub.Cancel() // Aman dipanggil setelah diputus
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"fullstack-crud-project-01/backend-go/dto"
+	"fullstack-crud-project-01/backend-go/middleware"
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/services"
+)
+
+// AccountHandler menyediakan endpoint /me untuk pengguna yang sudah login
+type AccountHandler struct {
+	AccountSvc *services.AccountService
+}
+
+// NewAccountHandler adalah konstruktor untuk AccountHandler
+func NewAccountHandler(svc *services.AccountService) *AccountHandler {
+	return &AccountHandler{AccountSvc: svc}
+}
+
+// GetProfileHandler mengembalikan profil pengguna dari claims token
+func (h *AccountHandler) GetProfileHandler(c *gin.Context) {
+	claims, _ := middleware.CurrentClaims(c)
+	user, err := h.AccountSvc.Profile(c.Request.Context(), claims.UserID)
+	if err != nil {
+		respondAccountError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": user})
+}
+
+// UpdateProfileHandler mengubah nama pengguna
+func (h *AccountHandler) UpdateProfileHandler(c *gin.Context) {
+	var req dto.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Permintaan tidak valid", "details": err.Error()})
+		return
+	}
+
+	claims, _ := middleware.CurrentClaims(c)
+	user, err := h.AccountSvc.UpdateProfile(auditContext(c), claims.UserID, req.Name)
+	if err != nil {
+		respondAccountError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": user})
+}
+
+// ChangePasswordHandler mengganti password; sesi lain dicabut, sesi ini tetap berlaku
+func (h *AccountHandler) ChangePasswordHandler(c *gin.Context) {
+	var req dto.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Permintaan tidak valid", "details": err.Error()})
+		return
+	}
+
+	claims, _ := middleware.CurrentClaims(c)
+	if err := h.AccountSvc.ChangePassword(auditContext(c), claims.UserID, req.CurrentPassword, req.NewPassword, claims.ID); err != nil {
+		respondAccountError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Password berhasil diganti. Sesi di perangkat lain telah diakhiri."})
+}
+
+// ChangeEmailHandler meminta penggantian email. Email akun baru berubah setelah link
+// konfirmasi yang dikirim ke email baru dibuka.
+func (h *AccountHandler) ChangeEmailHandler(c *gin.Context) {
+	var req dto.ChangeEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Permintaan tidak valid", "details": err.Error()})
+		return
+	}
+
+	claims, _ := middleware.CurrentClaims(c)
+	if _, err := h.AccountSvc.RequestEmailChange(auditContext(c), claims.UserID, req.Email, req.Password); err != nil {
+		respondAccountError(c, err)
+		return
+	}
+
+	// Kirim Email (SIMULASI - Ganti dengan logika kirim email sesungguhnya)
+	// Link dikirim ke email BARU agar kepemilikannya terbukti:
+	// Contoh link: http://localhost:3000/confirm-email?token=emailChangeToken
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Silakan cek email baru Anda untuk konfirmasi."})
+}
+
+// ConfirmEmailHandler menyelesaikan penggantian email menggunakan token dari link konfirmasi
+func (h *AccountHandler) ConfirmEmailHandler(c *gin.Context) {
+	if _, err := h.AccountSvc.ConfirmEmailChange(auditContext(c), c.Query("token")); err != nil {
+		if errors.Is(err, models.ErrEmailChangeTokenInvalid) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Token konfirmasi tidak valid atau kedaluwarsa."})
+			return
+		}
+		respondAccountError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Email berhasil diganti. Gunakan email baru untuk login."})
+}
+
+// DeleteAccountHandler menjadwalkan penghapusan akun dan mengakhiri semua sesi
+func (h *AccountHandler) DeleteAccountHandler(c *gin.Context) {
+	var req dto.DeleteAccountRequest
+	// Body boleh kosong untuk akun tanpa password (OIDC)
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Permintaan tidak valid", "details": err.Error()})
+		return
+	}
+
+	claims, _ := middleware.CurrentClaims(c)
+	user, err := h.AccountSvc.ScheduleDeletion(auditContext(c), claims.UserID, req.Password)
+	if err != nil {
+		respondAccountError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Akun akan dihapus permanen pada waktu yang dijadwalkan. Login kembali dan pulihkan akun sebelum waktu tersebut untuk membatalkannya.",
+		"data":    user,
+	})
+}
+
+// RestoreAccountHandler membatalkan penghapusan akun yang masih dalam masa tenggang
+func (h *AccountHandler) RestoreAccountHandler(c *gin.Context) {
+	claims, _ := middleware.CurrentClaims(c)
+	user, err := h.AccountSvc.CancelDeletion(auditContext(c), claims.UserID)
+	if err != nil {
+		respondAccountError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Penghapusan akun dibatalkan.", "data": user})
+}
+
+// respondAccountError memetakan error layanan akun ke response HTTP
+func respondAccountError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Akun tidak ditemukan"})
+	case errors.Is(err, models.ErrInvalidCredentials):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Password saat ini salah."})
+	case errors.Is(err, models.ErrPasswordTooShort), errors.Is(err, models.ErrEmailUnchanged):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrEmailAlreadyRegistered):
+		c.JSON(http.StatusConflict, gin.H{"error": "Email sudah terdaftar."})
+	case errors.Is(err, models.ErrDeletionNotScheduled):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses permintaan akun"})
+	}
+}
@@ -0,0 +1,139 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"fullstack-crud-project-01/backend-go/handlers"
+	"fullstack-crud-project-01/backend-go/middleware"
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/repositories"
+	"fullstack-crud-project-01/backend-go/services"
+	"fullstack-crud-project-01/backend-go/utils"
+)
+
+func TestAccount_SelfService(t *testing.T) {
+	testDB.Exec("DELETE FROM sessions")
+	testDB.Exec("DELETE FROM api_keys")
+	testDB.Exec("DELETE FROM users")
+	hash, err := utils.HashPassword("password123")
+	require.NoError(t, err)
+	user := models.User{Email: "saya@test.com", PasswordHash: hash, Name: "Saya", IsActive: true, Role: models.RoleUser}
+	require.NoError(t, testDB.Create(&user).Error)
+
+	userRepo := repositories.NewUserRepository(testDB)
+	sessionService := services.NewSessionService(repositories.NewSessionRepository(testDB))
+	authService := services.NewAuthService(userRepo)
+	authService.Sessions = sessionService
+	accountService := services.NewAccountService(userRepo, time.Hour)
+	accountService.Sessions = sessionService
+	accountHandler := handlers.NewAccountHandler(accountService)
+
+	r := gin.New()
+	r.POST("/api/v1/auth/login", handlers.NewAuthHandler(authService).LoginUser)
+	r.GET("/api/v1/auth/confirm-email", accountHandler.ConfirmEmailHandler)
+	me := r.Group("/api/v1/me", middleware.AuthMiddlewareWith(sessionService, nil))
+	me.GET("", accountHandler.GetProfileHandler)
+	me.PATCH("", accountHandler.UpdateProfileHandler)
+	me.PUT("/password", accountHandler.ChangePasswordHandler)
+	me.POST("/email", accountHandler.ChangeEmailHandler)
+	me.DELETE("", accountHandler.DeleteAccountHandler)
+	me.POST("/restore", accountHandler.RestoreAccountHandler)
+
+	serve := func(method, path, body, token string) (*httptest.ResponseRecorder, map[string]interface{}) {
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w, response
+	}
+	login := func(email, password string) string {
+		w, response := serve("POST", "/api/v1/auth/login", `{"email":"`+email+`","password":"`+password+`"}`, "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		return response["token"].(string)
+	}
+	token := login("saya@test.com", "password123")
+	otherDevice := login("saya@test.com", "password123")
+
+	// Profil dibaca dari claims token
+	w, response := serve("GET", "/api/v1/me", "", token)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	profile := response["data"].(map[string]interface{})
+	assert.Equal(t, "saya@test.com", profile["email"])
+	assert.NotContains(t, profile, "PasswordHash")
+	w, _ = serve("GET", "/api/v1/me", "", "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w, response = serve("PATCH", "/api/v1/me", `{"name":"Nama Baru"}`, token)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "Nama Baru", response["data"].(map[string]interface{})["name"])
+	w, _ = serve("PATCH", "/api/v1/me", `{}`, token)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Ganti password: password lama wajib, perangkat lain logout
+	w, _ = serve("PUT", "/api/v1/me/password", `{"current_password":"salah","new_password":"passwordbaru"}`, token)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	w, _ = serve("PUT", "/api/v1/me/password", `{"current_password":"password123","new_password":"passwordbaru"}`, token)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w, _ = serve("GET", "/api/v1/me", "", otherDevice)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	w, _ = serve("GET", "/api/v1/me", "", token)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Ganti email: berlaku setelah link konfirmasi dibuka
+	w, _ = serve("POST", "/api/v1/me/email", `{"email":"baru@test.com","password":"passwordbaru"}`, token)
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	var pending models.User
+	require.NoError(t, testDB.First(&pending, user.ID).Error)
+	require.NotNil(t, pending.EmailChangeToken)
+	assert.Equal(t, "saya@test.com", pending.Email)
+
+	w, _ = serve("GET", "/api/v1/auth/confirm-email?token=salah", "", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w, _ = serve("GET", "/api/v1/auth/confirm-email?token="+*pending.EmailChangeToken, "", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	token = login("baru@test.com", "passwordbaru")
+
+	// Hapus akun: semua sesi berakhir, akun bisa dipulihkan selama masa tenggang
+	w, _ = serve("DELETE", "/api/v1/me", `{"password":"salah"}`, token)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	w, response = serve("DELETE", "/api/v1/me", `{"password":"passwordbaru"}`, token)
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	assert.NotNil(t, response["data"].(map[string]interface{})["deletionScheduledAt"])
+	w, _ = serve("GET", "/api/v1/me", "", token)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	token = login("baru@test.com", "passwordbaru")
+	w, response = serve("POST", "/api/v1/me/restore", "", token)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Nil(t, response["data"].(map[string]interface{})["deletionScheduledAt"])
+	w, _ = serve("POST", "/api/v1/me/restore", "", token)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	// Setelah masa tenggang lewat, akun dan datanya dihapus permanen
+	w, _ = serve("DELETE", "/api/v1/me", `{"password":"passwordbaru"}`, token)
+	require.Equal(t, http.StatusAccepted, w.Code)
+	accountService.Now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	purged, err := accountService.PurgeDue(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+
+	var users, sessions int64
+	testDB.Model(&models.User{}).Where("id = ?", user.ID).Count(&users)
+	testDB.Model(&models.Session{}).Where("user_id = ?", user.ID).Count(&sessions)
+	assert.Zero(t, users)
+	assert.Zero(t, sessions)
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"fullstack-crud-project-01/backend-go/dto"
+	"fullstack-crud-project-01/backend-go/middleware"
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/services"
+)
+
+// APIKeyHandler menyediakan endpoint pengguna yang sudah login untuk mengelola API key pribadi
+type APIKeyHandler struct {
+	APIKeySvc *services.APIKeyService
+}
+
+// NewAPIKeyHandler adalah konstruktor untuk APIKeyHandler
+func NewAPIKeyHandler(svc *services.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{APIKeySvc: svc}
+}
+
+// CreateAPIKeyHandler membuat API key baru.
+// Kunci asli hanya dikembalikan sekali di respons ini.
+func (h *APIKeyHandler) CreateAPIKeyHandler(c *gin.Context) {
+	var req dto.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Permintaan tidak valid", "details": err.Error()})
+		return
+	}
+
+	claims, _ := middleware.CurrentClaims(c)
+	key, raw, err := h.APIKeySvc.Create(auditContext(c), claims.UserID, req.Name, req.Scopes, req.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, models.ErrAPIKeyNameRequired) ||
+			errors.Is(err, models.ErrAPIKeyScopesRequired) ||
+			errors.Is(err, models.ErrAPIKeyScopeInvalid) ||
+			errors.Is(err, models.ErrAPIKeyExpiryInvalid) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal membuat API key"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": key, "key": raw})
+}
+
+// ListAPIKeysHandler mengembalikan API key milik pengguna (hanya prefix, tanpa kunci asli)
+func (h *APIKeyHandler) ListAPIKeysHandler(c *gin.Context) {
+	claims, _ := middleware.CurrentClaims(c)
+	keys, err := h.APIKeySvc.List(c.Request.Context(), claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil daftar API key"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": keys})
+}
+
+// RevokeAPIKeyHandler mencabut API key milik pengguna
+func (h *APIKeyHandler) RevokeAPIKeyHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID API key tidak valid"})
+		return
+	}
+
+	claims, _ := middleware.CurrentClaims(c)
+	if err := h.APIKeySvc.Revoke(auditContext(c), claims.UserID, uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "API key tidak ditemukan"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mencabut API key"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
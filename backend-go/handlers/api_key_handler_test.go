@@ -0,0 +1,88 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"fullstack-crud-project-01/backend-go/handlers"
+	"fullstack-crud-project-01/backend-go/middleware"
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/repositories"
+	"fullstack-crud-project-01/backend-go/services"
+	"fullstack-crud-project-01/backend-go/utils"
+)
+
+func TestAPIKey_ProductAccess(t *testing.T) {
+	testDB.Exec("DELETE FROM api_keys")
+	testDB.Exec("DELETE FROM products")
+	testDB.Exec("DELETE FROM users")
+	user := models.User{Email: "bot@apikey.com", PasswordHash: "-", IsActive: true, Role: models.RoleUser}
+	require.NoError(t, testDB.Create(&user).Error)
+	token, err := utils.GenerateSessionToken(user.ID, user.Email, user.Role, "sesi-test")
+	require.NoError(t, err)
+
+	apiKeyService := services.NewAPIKeyService(repositories.NewAPIKeyRepository(testDB), repositories.NewUserRepository(testDB))
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService)
+	productHandler := handlers.NewProductHandler(services.NewProductService(repositories.NewProductRepository(testDB)))
+
+	r := gin.New()
+	apiKeys := r.Group("/api/v1/auth/api-keys", middleware.AuthMiddleware())
+	apiKeys.POST("", apiKeyHandler.CreateAPIKeyHandler)
+	apiKeys.GET("", apiKeyHandler.ListAPIKeysHandler)
+	apiKeys.DELETE("/:id", apiKeyHandler.RevokeAPIKeyHandler)
+	products := r.Group("/api/v1/products", middleware.AuthMiddlewareWithAPIKeys(apiKeyService))
+	products.GET("", middleware.RequireScope(models.ScopeProductsRead), productHandler.ReadAllProductsHandler)
+	products.POST("", middleware.RequireScope(models.ScopeProductsWrite), productHandler.CreateProductHandler)
+
+	serve := func(method, path, body string, header [2]string) (*httptest.ResponseRecorder, map[string]interface{}) {
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set(header[0], header[1])
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w, response
+	}
+	withJWT := [2]string{"Authorization", "Bearer " + token}
+
+	w, _ := serve("POST", "/api/v1/auth/api-keys", `{"name":"CI","scopes":["admin:all"]}`, withJWT)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w, response := serve("POST", "/api/v1/auth/api-keys", `{"name":"CI","scopes":["products:read"]}`, withJWT)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	rawKey, _ := response["key"].(string)
+	require.NotEmpty(t, rawKey)
+	data, _ := response["data"].(map[string]interface{})
+	assert.NotContains(t, data, "key_hash")
+	keyID := strconv.Itoa(int(data["id"].(float64)))
+
+	withKey := [2]string{middleware.APIKeyHeader, rawKey}
+	w, _ = serve("GET", "/api/v1/products", "", withKey)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w, _ = serve("POST", "/api/v1/products", `{"name":"Bot","price":1000}`, withKey)
+	assert.Equal(t, http.StatusForbidden, w.Code, "Kunci hanya products:read")
+
+	// Kunci tidak bisa dipakai untuk mengelola API key
+	w, _ = serve("GET", "/api/v1/auth/api-keys", "", withKey)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w, response = serve("GET", "/api/v1/auth/api-keys", "", withJWT)
+	assert.Equal(t, http.StatusOK, w.Code)
+	keys, _ := response["data"].([]interface{})
+	require.Len(t, keys, 1)
+	assert.NotNil(t, keys[0].(map[string]interface{})["last_used_at"], "Pemakaian terakhir tercatat")
+
+	w, _ = serve("DELETE", "/api/v1/auth/api-keys/"+keyID, "", withJWT)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	w, _ = serve("GET", "/api/v1/products", "", withKey)
+	assert.Equal(t, http.StatusUnauthorized, w.Code, "Kunci yang dicabut")
+}
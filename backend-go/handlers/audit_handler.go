@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"fullstack-crud-project-01/backend-go/middleware"
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/services"
+)
+
+// AuditHandler menyediakan endpoint admin untuk membaca audit log
+type AuditHandler struct {
+	AuditSvc *services.AuditService
+}
+
+// NewAuditHandler adalah konstruktor untuk AuditHandler
+func NewAuditHandler(svc *services.AuditService) *AuditHandler {
+	return &AuditHandler{AuditSvc: svc}
+}
+
+// ListAuditLogsHandler mengembalikan audit log yang difilter berdasarkan
+// query string actor_id, resource, from, to (RFC3339) dan limit.
+func (h *AuditHandler) ListAuditLogsHandler(c *gin.Context) {
+	var filter services.AuditFilter
+
+	if raw := c.Query("actor_id"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "actor_id tidak valid"})
+			return
+		}
+		actorID := uint(id)
+		filter.ActorID = &actorID
+	}
+	filter.Resource = c.Query("resource")
+
+	var err error
+	if filter.From, err = parseTimeQuery(c, "from"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from harus berformat RFC3339"})
+		return
+	}
+	if filter.To, err = parseTimeQuery(c, "to"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to harus berformat RFC3339"})
+		return
+	}
+
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit tidak valid"})
+			return
+		}
+		filter.Limit = limit
+	}
+
+	logs, err := h.AuditSvc.Query(c.Request.Context(), filter)
+	if err != nil {
+		if errors.Is(err, models.ErrAuditInvalidTimeRange) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil audit log"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": logs})
+}
+
+// parseTimeQuery membaca query string berformat RFC3339. Mengembalikan nil jika kosong.
+func parseTimeQuery(c *gin.Context, key string) (*time.Time, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// auditContext mengembalikan context request yang membawa aktor (dari claims), IP dan user agent
+// sehingga service dapat mencatat audit log tanpa bergantung pada gin.Context.
+func auditContext(c *gin.Context) context.Context {
+	actor := services.AuditActor{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+	if claims, ok := middleware.CurrentClaims(c); ok {
+		actorID := claims.UserID
+		actor.UserID = &actorID
+		actor.Email = claims.Email
+	}
+	return services.WithAuditActor(c.Request.Context(), actor)
+}
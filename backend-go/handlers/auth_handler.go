@@ -1,117 +1,161 @@
 package handlers
 
 import (
-    "net/http"
-    "time"
+	"errors"
+	"net/http"
+	"strconv"
 
-    "github.com/gin-gonic/gin"
-    "github.com/google/uuid"
-    "gorm.io/gorm"
+	"github.com/gin-gonic/gin"
 
-    "fullstack-crud-project-01/backend-go/models" // Ganti dengan path model Anda
-    "fullstack-crud-project-01/backend-go/utils"  // Ganti dengan path utilitas Anda
-    "fullstack-crud-project-01/backend-go/dto"   // Ganti dengan path DTO Anda
+	"fullstack-crud-project-01/backend-go/dto"
+	"fullstack-crud-project-01/backend-go/metrics"
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/ratelimit"
+	"fullstack-crud-project-01/backend-go/services"
 )
 
+// AuthHandler menerjemahkan request HTTP autentikasi ke AuthService
 type AuthHandler struct {
-    DB *gorm.DB
+	AuthSvc *services.AuthService
+}
+
+// NewAuthHandler adalah konstruktor untuk AuthHandler
+func NewAuthHandler(svc *services.AuthService) *AuthHandler {
+	return &AuthHandler{AuthSvc: svc}
 }
 
 // RegisterUser menghandle proses pendaftaran pengguna baru
 func (h *AuthHandler) RegisterUser(c *gin.Context) {
-    var req dto.RegisterRequest
-    
-    // 1. Binding & Validasi
-    if err := c.ShouldBindJSON(&req); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "Permintaan tidak valid", "details": err.Error()})
-        return
-    }
-
-    // 2. Cek Duplikasi Email
-    var existingUser models.User
-    if err := h.DB.Where("email = ?", req.Email).First(&existingUser).Error; err == nil {
-        c.JSON(http.StatusConflict, gin.H{"error": "Email sudah terdaftar."})
-        return
-    }
-
-    // 3. Hash Password
-    passwordHash, err := utils.HashPassword(req.Password)
-    if err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses password."})
-        return
-    }
-
-    // 4. Buat Token Aktivasi
-    activationToken := uuid.NewString()
-
-    newUser := models.User{
-        Email:    req.Email,
-        PasswordHash: passwordHash,
-        Name:     req.Name,
-        IsActive: false, // Wajib FALSE
-        ActivationToken: &activationToken,
-        CreatedAt: time.Now(),
-        UpdatedAt: time.Now(),
-    }
-
-    // 5. Simpan User
-    if err := h.DB.Create(&newUser).Error; err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan user ke database."})
-        return
-    }
-
-    // 6. Kirim Email (SIMULASI - Ganti dengan logika kirim email sesungguhnya)
-    // Di sini Anda akan memanggil layanan email Anda untuk mengirim link:
-    // Contoh link: http://localhost:3000/activate?token=activationToken
-    
-    c.JSON(http.StatusCreated, gin.H{
-        "message": "Pendaftaran berhasil. Silakan cek email Anda untuk aktivasi akun.",
-        "user_id": newUser.ID,
-    })
+	var req dto.RegisterRequest
+
+	// 1. Binding & Validasi
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Permintaan tidak valid", "details": err.Error()})
+		return
+	}
+
+	// 2. Daftarkan user (cek duplikasi, hash password, token aktivasi)
+	newUser, err := h.AuthSvc.Register(auditContext(c), req.Email, req.Password, req.Name)
+	if err != nil {
+		if errors.Is(err, models.ErrEmailAlreadyRegistered) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Email sudah terdaftar."})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan user ke database."})
+		return
+	}
+
+	// 3. Kirim Email (SIMULASI - Ganti dengan logika kirim email sesungguhnya)
+	// Di sini Anda akan memanggil layanan email Anda untuk mengirim link:
+	// Contoh link: http://localhost:3000/activate?token=activationToken
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Pendaftaran berhasil. Silakan cek email Anda untuk aktivasi akun.",
+		"user_id": newUser.ID,
+	})
+}
+
+// ActivateUser mengaktifkan akun menggunakan token dari email aktivasi
+func (h *AuthHandler) ActivateUser(c *gin.Context) {
+	if _, err := h.AuthSvc.Activate(auditContext(c), c.Query("token")); err != nil {
+		if errors.Is(err, models.ErrActivationTokenInvalid) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Token aktivasi tidak valid atau sudah digunakan."})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengaktifkan akun."})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Akun berhasil diaktifkan. Silakan login."})
 }
 
-// LoginUser menghandle proses login pengguna
+// LoginUser menghandle proses login pengguna. Untuk akun dengan TOTP aktif, response
+// berisi mfa_token (bukan token) yang ditukar lewat LoginMFA bersama kode TOTP.
 func (h *AuthHandler) LoginUser(c *gin.Context) {
-    var req dto.LoginRequest // Anda perlu membuat struct ini
-    
-    if err := c.ShouldBindJSON(&req); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "Permintaan tidak valid"})
-        return
-    }
-
-    // 1. Cari User berdasarkan Email
-    var user models.User
-    if err := h.DB.Where("email = ?", req.Email).First(&user).Error; err != nil {
-        // Gagal mencari user (atau email tidak ditemukan)
-        c.JSON(http.StatusUnauthorized, gin.H{"error": "Kredensial tidak valid."})
-        return
-    }
-
-    // 2. Verifikasi Password
-    if err := utils.CheckPasswordHash(user.PasswordHash, req.Password); err != nil {
-        // Password tidak cocok
-        c.JSON(http.StatusUnauthorized, gin.H{"error": "Kredensial tidak valid."})
-        return
-    }
-    
-    // 3. Cek Status Aktif (Penting!)
-    if !user.IsActive {
-        c.JSON(http.StatusForbidden, gin.H{"error": "Akun belum diaktifkan. Silakan cek email Anda."})
-        return
-    }
-
-    // 4. Buat JWT
-    token, err := utils.GenerateToken(user.ID, user.Email) // Asumsikan utilitas sudah dibuat
-    if err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal membuat token otentikasi."})
-        return
-    }
-
-    // 5. Response Sukses
-    c.JSON(http.StatusOK, gin.H{
-        "message": "Login berhasil!",
-        "token":   token,
-        "user_id": user.ID,
-        "name": user.Name,
-    })
-}
\ No newline at end of file
+	var req dto.LoginRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Permintaan tidak valid"})
+		return
+	}
+
+	result, err := h.AuthSvc.Login(auditContext(c), req.Email, req.Password)
+	if err != nil {
+		respondLoginError(c, err)
+		return
+	}
+
+	respondLoginResult(c, result)
+}
+
+// LoginMFA menyelesaikan login dua langkah dengan kode TOTP atau kode pemulihan
+func (h *AuthHandler) LoginMFA(c *gin.Context) {
+	var req dto.MFALoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Permintaan tidak valid"})
+		return
+	}
+
+	result, err := h.AuthSvc.LoginMFA(auditContext(c), req.MFAToken, req.Code)
+	if err != nil {
+		respondLoginError(c, err)
+		return
+	}
+	respondLoginSuccess(c, result)
+}
+
+// respondLoginResult mengirim hasil langkah pertama login: token tantangan MFA jika TOTP
+// aktif, selain itu access token
+func respondLoginResult(c *gin.Context, result *services.LoginResult) {
+	if result.MFAToken != "" {
+		metrics.LoginAttempts.WithLabelValues(metrics.LoginMFARequired).Inc()
+		c.JSON(http.StatusOK, gin.H{
+			"message":      "Masukkan kode dari aplikasi authenticator Anda.",
+			"mfa_required": true,
+			"mfa_token":    result.MFAToken,
+		})
+		return
+	}
+	respondLoginSuccess(c, result)
+}
+
+// respondLoginSuccess mengirim access token hasil login
+func respondLoginSuccess(c *gin.Context, result *services.LoginResult) {
+	metrics.LoginAttempts.WithLabelValues(metrics.LoginSuccess).Inc()
+
+	// Response Sukses
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Login berhasil!",
+		"token":   result.Token,
+		"user_id": result.User.ID,
+		"name":    result.User.Name,
+	})
+}
+
+// respondLoginError memetakan error login (kedua langkah) ke response HTTP
+func respondLoginError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, models.ErrInvalidCredentials):
+		metrics.LoginAttempts.WithLabelValues(metrics.LoginInvalidCredentials).Inc()
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Kredensial tidak valid."})
+	case errors.Is(err, models.ErrMFACodeInvalid):
+		metrics.LoginAttempts.WithLabelValues(metrics.LoginInvalidMFACode).Inc()
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Kode autentikasi tidak valid."})
+	case errors.Is(err, models.ErrMFATokenInvalid):
+		metrics.LoginAttempts.WithLabelValues(metrics.LoginInvalidMFACode).Inc()
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Sesi login kedaluwarsa. Silakan login ulang."})
+	case errors.Is(err, models.ErrAccountNotActive):
+		metrics.LoginAttempts.WithLabelValues(metrics.LoginNotActive).Inc()
+		c.JSON(http.StatusForbidden, gin.H{"error": "Akun belum diaktifkan. Silakan cek email Anda."})
+	case errors.Is(err, models.ErrTooManyLoginAttempts):
+		metrics.LoginAttempts.WithLabelValues(metrics.LoginThrottled).Inc()
+		var throttled *services.LoginThrottledError
+		if errors.As(err, &throttled) {
+			c.Header("Retry-After", strconv.Itoa(ratelimit.Seconds(throttled.RetryAfter)))
+		}
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Terlalu banyak percobaan login. Coba lagi nanti."})
+	default:
+		metrics.LoginAttempts.WithLabelValues(metrics.LoginError).Inc()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal membuat token otentikasi."})
+	}
+}
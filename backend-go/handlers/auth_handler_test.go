@@ -2,23 +2,30 @@ package handlers_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/pquerna/otp/totp"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"fullstack-crud-project-01/backend-go/handlers"
 	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/ratelimit"
+	"fullstack-crud-project-01/backend-go/repositories"
+	"fullstack-crud-project-01/backend-go/services"
 	"fullstack-crud-project-01/backend-go/utils"
 	"golang.org/x/crypto/bcrypt"
 )
 
 func setupAuthRouter() *gin.Engine {
 	// 1. Inisialisasi Handler dengan DB Test
-	authHandler := handlers.AuthHandler{DB: testDB}
+	authHandler := handlers.NewAuthHandler(services.NewAuthService(repositories.NewUserRepository(testDB)))
 	
 	// 2. Setup Router
 	r := gin.Default()
@@ -27,6 +34,7 @@ func setupAuthRouter() *gin.Engine {
 	{
 		auth.POST("/register", authHandler.RegisterUser)
 		auth.POST("/login", authHandler.LoginUser)
+		auth.GET("/activate", authHandler.ActivateUser)
 	}
 	return r
 }
@@ -104,4 +112,82 @@ func TestAuth_LoginUser_WrongPassword(t *testing.T) {
 	r.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
-}
\ No newline at end of file
+}
+
+func TestAuth_LoginUser_Locked(t *testing.T) {
+	testDB.Exec("DELETE FROM users")
+	passwordHash, _ := bcrypt.GenerateFromPassword([]byte("correct_password"), bcrypt.DefaultCost)
+	testDB.Create(&models.User{Email: "locked@pass.com", PasswordHash: string(passwordHash), IsActive: true})
+
+	// Akun dikunci setelah satu kali gagal
+	authService := services.NewAuthService(repositories.NewUserRepository(testDB))
+	authService.Limiter = ratelimit.NewLoginGuard(ratelimit.NewMemoryStore(),
+		ratelimit.Limit{Burst: 10, Period: time.Minute}, 1, 10*time.Minute)
+	r := gin.New()
+	r.POST("/api/v1/auth/login", handlers.NewAuthHandler(authService).LoginUser)
+
+	login := func(password string) *httptest.ResponseRecorder {
+		body := bytes.NewBufferString(`{"email":"locked@pass.com", "password":"` + password + `"}`)
+		req, _ := http.NewRequest("POST", "/api/v1/auth/login", body)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, login("wrong_password").Code)
+
+	w := login("correct_password")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "600", w.Header().Get("Retry-After"))
+}
+
+func TestAuth_LoginUser_TOTP(t *testing.T) {
+	testDB.Exec("DELETE FROM recovery_codes")
+	testDB.Exec("DELETE FROM users")
+	passwordHash, _ := bcrypt.GenerateFromPassword([]byte("correct_password"), bcrypt.DefaultCost)
+	user := models.User{Email: "totp@login.com", PasswordHash: string(passwordHash), IsActive: true, Role: models.RoleAdmin}
+	require.NoError(t, testDB.Create(&user).Error)
+
+	userRepo := repositories.NewUserRepository(testDB)
+	mfaService := services.NewMFAService(userRepo, repositories.NewRecoveryCodeRepository(testDB), "Test")
+	authService := services.NewAuthService(userRepo)
+	authService.MFA = mfaService
+	r := gin.New()
+	r.POST("/api/v1/auth/login", handlers.NewAuthHandler(authService).LoginUser)
+	r.POST("/api/v1/auth/login/mfa", handlers.NewAuthHandler(authService).LoginMFA)
+
+	post := func(path, body string) (*httptest.ResponseRecorder, map[string]interface{}) {
+		req, _ := http.NewRequest("POST", path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w, response
+	}
+
+	// Aktifkan TOTP: kode pertama dari "authenticator" untuk periode sebelumnya
+	setup, err := mfaService.SetupTOTP(context.Background(), user.ID)
+	require.NoError(t, err)
+	code, _ := totp.GenerateCode(setup.Secret, time.Now().Add(-30*time.Second))
+	recoveryCodes, err := mfaService.EnableTOTP(context.Background(), user.ID, code)
+	require.NoError(t, err)
+
+	w, response := post("/api/v1/auth/login", `{"email":"totp@login.com", "password":"correct_password"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, true, response["mfa_required"])
+	assert.NotContains(t, response, "token", "Access token belum terbit sebelum kode TOTP")
+	mfaToken, _ := response["mfa_token"].(string)
+	require.NotEmpty(t, mfaToken)
+
+	w, _ = post("/api/v1/auth/login/mfa", `{"mfa_token":"`+mfaToken+`", "code":"000000"}`)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w, response = post("/api/v1/auth/login/mfa", `{"mfa_token":"`+mfaToken+`", "code":"`+recoveryCodes[0]+`"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEmpty(t, response["token"])
+
+	w, _ = post("/api/v1/auth/login/mfa", `{"mfa_token":"`+mfaToken+`", "code":"`+recoveryCodes[0]+`"}`)
+	assert.Equal(t, http.StatusUnauthorized, w.Code, "Kode pemulihan hanya berlaku sekali")
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StatusClientClosedRequest adalah status non-standar (dipopulerkan nginx) untuk
+// request yang dibatalkan karena klien menutup koneksi sebelum respons dikirim.
+const StatusClientClosedRequest = 499
+
+// respondContextError memetakan error pembatalan context ke respons HTTP:
+// klien terputus -> 499, deadline query habis -> 504.
+// Mengembalikan true jika error tersebut sudah ditangani.
+func respondContextError(c *gin.Context, err error) bool {
+	// Driver database tidak selalu membungkus error context, jadi cek juga context request-nya
+	ctxErr := c.Request.Context().Err()
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded) || errors.Is(ctxErr, context.DeadlineExceeded):
+		c.JSON(http.StatusGatewayTimeout, gin.H{"error": "Permintaan melebihi batas waktu"})
+	case errors.Is(err, context.Canceled) || errors.Is(ctxErr, context.Canceled):
+		c.AbortWithStatus(StatusClientClosedRequest)
+	default:
+		return false
+	}
+	return true
+}
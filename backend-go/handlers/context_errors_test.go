@@ -0,0 +1,100 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"fullstack-crud-project-01/backend-go/handlers"
+	"fullstack-crud-project-01/backend-go/middleware"
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/services"
+)
+
+// blockingProductRepo mensimulasikan query lambat: setiap operasi menunggu sampai context
+// request berakhir, lalu mengembalikan err (atau ctx.Err() jika err nil)
+type blockingProductRepo struct {
+	err error
+}
+
+func (r *blockingProductRepo) wait(ctx context.Context) error {
+	<-ctx.Done()
+	if r.err != nil {
+		return r.err
+	}
+	return ctx.Err()
+}
+
+func (r *blockingProductRepo) Create(ctx context.Context, product *models.Product) error {
+	return r.wait(ctx)
+}
+
+func (r *blockingProductRepo) ReadAll(ctx context.Context) ([]models.Product, error) {
+	return nil, r.wait(ctx)
+}
+
+func (r *blockingProductRepo) ReadByID(ctx context.Context, id uint) (*models.Product, error) {
+	return nil, r.wait(ctx)
+}
+
+func (r *blockingProductRepo) Update(ctx context.Context, product *models.Product) error {
+	return r.wait(ctx)
+}
+
+func (r *blockingProductRepo) Delete(ctx context.Context, id uint) error {
+	return r.wait(ctx)
+}
+
+func setupBlockingProductRouter(repo *blockingProductRepo) *gin.Engine {
+	productHandler := handlers.NewProductHandler(services.NewProductService(repo))
+	r := gin.New()
+	products := r.Group("/api/v1/products", middleware.Timeout(20*time.Millisecond))
+	products.GET("", productHandler.ReadAllProductsHandler)
+	products.GET("/:id", productHandler.ReadProductByIDHandler)
+	return r
+}
+
+func TestProductHandler_DeadlineExceeded(t *testing.T) {
+	tests := []struct {
+		name string
+		repo *blockingProductRepo
+		path string
+	}{
+		{"ErrorContextDibungkus", &blockingProductRepo{}, "/api/v1/products"},
+		// Driver yang tidak membungkus error context tetap dipetakan lewat context request
+		{"ErrorDriverTanpaContext", &blockingProductRepo{err: errors.New("driver: bad connection")}, "/api/v1/products/1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := setupBlockingProductRouter(tt.repo)
+			req, _ := http.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+			start := time.Now()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+			assert.Contains(t, w.Body.String(), "batas waktu")
+			assert.Less(t, time.Since(start), 500*time.Millisecond)
+		})
+	}
+}
+
+func TestProductHandler_ClientClosedRequest(t *testing.T) {
+	router := setupBlockingProductRouter(&blockingProductRepo{})
+
+	// Klien menutup koneksi sebelum deadline Timeout tercapai
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(5*time.Millisecond, cancel)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/api/v1/products", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, handlers.StatusClientClosedRequest, w.Code)
+	assert.Empty(t, w.Body.String())
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"fullstack-crud-project-01/backend-go/services"
+)
+
+// HealthHandler menyediakan probe liveness/readiness dan status sistem untuk admin
+type HealthHandler struct {
+	HealthSvc *services.HealthService
+}
+
+// NewHealthHandler adalah konstruktor untuk HealthHandler
+func NewHealthHandler(svc *services.HealthService) *HealthHandler {
+	return &HealthHandler{HealthSvc: svc}
+}
+
+// LivenessHandler (/healthz) hanya memastikan proses masih melayani request.
+// Dependensi sengaja tidak diperiksa agar database yang mati tidak membuat orchestrator
+// me-restart semua instance.
+func (h *HealthHandler) LivenessHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"status": services.HealthStatusOK}})
+}
+
+// ReadinessHandler (/readyz) mengembalikan 503 jika ada dependensi yang gagal
+// atau server sedang graceful shutdown.
+func (h *HealthHandler) ReadinessHandler(c *gin.Context) {
+	report := h.HealthSvc.Readiness(c.Request.Context())
+	code := http.StatusOK
+	if !report.Ready() {
+		code = http.StatusServiceUnavailable
+	}
+	c.JSON(code, gin.H{"data": report})
+}
+
+// StatusHandler (/api/v1/admin/status) mengembalikan versi build, uptime, statistik pool
+// database dan latensi setiap pemeriksaan. Selalu 200 agar detail tetap terbaca saat tidak siap.
+func (h *HealthHandler) StatusHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.HealthSvc.Status(c.Request.Context())})
+}
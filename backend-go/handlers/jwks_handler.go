@@ -0,0 +1,18 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"fullstack-crud-project-01/backend-go/utils"
+)
+
+// JWKSHandler (/.well-known/jwks.json) mempublikasikan kunci publik penandatangan JWT
+// agar layanan lain dapat memverifikasi token tanpa memegang secret. Format respons
+// mengikuti RFC 7517 ({"keys": [...]}), bukan amplop {"data": ...}, supaya bisa dibaca
+// langsung oleh library JWT. Kosong jika token masih ditandatangani HS256.
+func JWKSHandler(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, gin.H{"keys": utils.JWKS()})
+}
@@ -0,0 +1,53 @@
+package handlers_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"fullstack-crud-project-01/backend-go/handlers"
+	"fullstack-crud-project-01/backend-go/utils"
+)
+
+func TestJWKSHandler(t *testing.T) {
+	r := gin.New()
+	r.GET("/.well-known/jwks.json", handlers.JWKSHandler)
+	serve := func() map[string][]map[string]string {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", "/.well-known/jwks.json", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		var body map[string][]map[string]string
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return body
+	}
+
+	// HS256: secret tidak pernah dipublikasikan
+	assert.Empty(t, serve()["keys"])
+
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "jwt.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600))
+	require.NoError(t, utils.ConfigureJWTKeys(path, nil))
+	t.Cleanup(func() { utils.ConfigureJWTKeys("", nil) })
+
+	keys := serve()["keys"]
+	require.Len(t, keys, 1)
+	assert.Equal(t, "OKP", keys[0]["kty"])
+	assert.Equal(t, "EdDSA", keys[0]["alg"])
+	assert.NotEmpty(t, keys[0]["kid"])
+	assert.NotContains(t, keys[0], "d", "Bagian privat tidak ikut dipublikasikan")
+}
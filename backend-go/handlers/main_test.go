@@ -6,7 +6,6 @@ import (
 	"testing"
 
 	"fullstack-crud-project-01/backend-go/config"
-	"fullstack-crud-project-01/backend-go/models"
 	"gorm.io/gorm"
 )
 
@@ -17,6 +16,10 @@ var testDB *gorm.DB
 func TestMain(m *testing.M) {
 	// Muat .env untuk mendapatkan kredensial DB dan JWT Secret
 	config.LoadEnv()
+	if os.Getenv("JWT_SECRET_KEY") == "" {
+		// Tanpa .env, gunakan secret khusus tes
+		os.Setenv("JWT_SECRET_KEY", "test-secret-key")
+	}
 
 	// Setup: Koneksi ke database test
 	var err error
@@ -24,7 +27,9 @@ func TestMain(m *testing.M) {
 	if err != nil {
 		log.Fatalf("Gagal konek ke DB test: %v", err)
 	}
-	testDB.AutoMigrate(&models.User{}, &models.Product{})
+	if err := config.RunMigrations(testDB); err != nil {
+		log.Fatalf("Gagal migrasi DB test: %v", err)
+	}
 
 	os.Exit(m.Run())
 }
\ No newline at end of file
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"fullstack-crud-project-01/backend-go/dto"
+	"fullstack-crud-project-01/backend-go/middleware"
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/services"
+)
+
+// MFAHandler menyediakan endpoint pengguna yang sudah login untuk mengelola TOTP
+type MFAHandler struct {
+	MFASvc *services.MFAService
+}
+
+// NewMFAHandler adalah konstruktor untuk MFAHandler
+func NewMFAHandler(svc *services.MFAService) *MFAHandler {
+	return &MFAHandler{MFASvc: svc}
+}
+
+// StatusHandler mengembalikan status TOTP dan sisa kode pemulihan
+func (h *MFAHandler) StatusHandler(c *gin.Context) {
+	claims, _ := middleware.CurrentClaims(c)
+	status, err := h.MFASvc.Status(c.Request.Context(), claims.UserID)
+	if err != nil {
+		respondMFAError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{
+		"totp_enabled":             status.TOTPEnabled,
+		"recovery_codes_remaining": status.RecoveryCodesRemaining,
+	}})
+}
+
+// SetupTOTPHandler membuat secret TOTP baru beserta URI otpauth:// dan QR code PNG (base64).
+// TOTP belum aktif sampai dikonfirmasi lewat EnableTOTPHandler.
+func (h *MFAHandler) SetupTOTPHandler(c *gin.Context) {
+	claims, _ := middleware.CurrentClaims(c)
+	setup, err := h.MFASvc.SetupTOTP(c.Request.Context(), claims.UserID)
+	if err != nil {
+		respondMFAError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{
+		"secret":      setup.Secret,
+		"otpauth_url": setup.URL,
+		"qr_png":      base64.StdEncoding.EncodeToString(setup.QRCode),
+	}})
+}
+
+// EnableTOTPHandler mengaktifkan TOTP dengan kode pertama dari authenticator.
+// Kode pemulihan hanya dikembalikan sekali di respons ini.
+func (h *MFAHandler) EnableTOTPHandler(c *gin.Context) {
+	var req dto.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Permintaan tidak valid", "details": err.Error()})
+		return
+	}
+
+	claims, _ := middleware.CurrentClaims(c)
+	codes, err := h.MFASvc.EnableTOTP(auditContext(c), claims.UserID, req.Code)
+	if err != nil {
+		respondMFAError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"recovery_codes": codes}})
+}
+
+// DisableTOTPHandler mematikan TOTP; butuh kode TOTP atau kode pemulihan
+func (h *MFAHandler) DisableTOTPHandler(c *gin.Context) {
+	var req dto.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Permintaan tidak valid", "details": err.Error()})
+		return
+	}
+
+	claims, _ := middleware.CurrentClaims(c)
+	if err := h.MFASvc.DisableTOTP(auditContext(c), claims.UserID, req.Code); err != nil {
+		respondMFAError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Autentikasi dua faktor dinonaktifkan."})
+}
+
+// RegenerateRecoveryCodesHandler mengganti semua kode pemulihan
+func (h *MFAHandler) RegenerateRecoveryCodesHandler(c *gin.Context) {
+	var req dto.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Permintaan tidak valid", "details": err.Error()})
+		return
+	}
+
+	claims, _ := middleware.CurrentClaims(c)
+	codes, err := h.MFASvc.RegenerateRecoveryCodes(auditContext(c), claims.UserID, req.Code)
+	if err != nil {
+		respondMFAError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"recovery_codes": codes}})
+}
+
+// respondMFAError memetakan error MFAService ke response HTTP
+func respondMFAError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, models.ErrMFACodeInvalid):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Kode autentikasi tidak valid."})
+	case errors.Is(err, models.ErrMFAAlreadyEnabled),
+		errors.Is(err, models.ErrMFANotEnabled),
+		errors.Is(err, models.ErrMFANotSetUp):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses autentikasi dua faktor."})
+	}
+}
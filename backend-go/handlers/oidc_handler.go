@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"fullstack-crud-project-01/backend-go/metrics"
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/services"
+	"fullstack-crud-project-01/backend-go/utils"
+)
+
+const (
+	// OIDCFlowCookie menyimpan state, nonce dan PKCE verifier (ditandatangani) selama
+	// pengguna berada di halaman penyedia identitas
+	OIDCFlowCookie     = "oidc_flow"
+	oidcFlowCookiePath = "/api/v1/auth/oidc"
+)
+
+// OIDCHandler menyediakan endpoint login lewat penyedia OpenID Connect
+type OIDCHandler struct {
+	OIDCSvc *services.OIDCService
+}
+
+// NewOIDCHandler adalah konstruktor untuk OIDCHandler
+func NewOIDCHandler(svc *services.OIDCService) *OIDCHandler {
+	return &OIDCHandler{OIDCSvc: svc}
+}
+
+// LoginHandler mengarahkan browser ke halaman login penyedia identitas
+func (h *OIDCHandler) LoginHandler(c *gin.Context) {
+	req, err := h.OIDCSvc.Begin(c.Request.Context())
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Gagal memulai login OIDC", "error", err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Penyedia identitas tidak dapat dihubungi."})
+		return
+	}
+
+	h.setFlowCookie(c, req.FlowToken, int(utils.OIDCFlowTTL.Seconds()))
+	c.Redirect(http.StatusFound, req.URL)
+}
+
+// CallbackHandler menyelesaikan login setelah penyedia identitas mengarahkan kembali
+// dengan code dan state. Response-nya sama dengan LoginUser (token atau mfa_token).
+func (h *OIDCHandler) CallbackHandler(c *gin.Context) {
+	flowToken, _ := c.Cookie(OIDCFlowCookie)
+	// Cookie alur hanya berlaku untuk satu callback
+	h.setFlowCookie(c, "", -1)
+
+	if idpError := c.Query("error"); idpError != "" {
+		metrics.LoginAttempts.WithLabelValues(metrics.LoginOIDCRejected).Inc()
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Login dibatalkan oleh penyedia identitas.", "details": idpError})
+		return
+	}
+
+	result, err := h.OIDCSvc.Complete(auditContext(c), flowToken, c.Query("state"), c.Query("code"))
+	if err != nil {
+		respondOIDCError(c, err)
+		return
+	}
+	respondLoginResult(c, result)
+}
+
+func (h *OIDCHandler) setFlowCookie(c *gin.Context, value string, maxAge int) {
+	secure := strings.HasPrefix(h.OIDCSvc.RedirectURL, "https://")
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(OIDCFlowCookie, value, maxAge, oidcFlowCookiePath, "", secure, true)
+}
+
+// respondOIDCError memetakan error khusus OIDC; error lain (akun nonaktif, throttling)
+// ditangani seperti login biasa
+func respondOIDCError(c *gin.Context, err error) {
+	var status int
+	var message string
+	switch {
+	case errors.Is(err, models.ErrOIDCStateInvalid):
+		status, message = http.StatusUnauthorized, "Sesi login kedaluwarsa atau tidak valid. Silakan ulangi login."
+	case errors.Is(err, models.ErrOIDCLoginFailed):
+		status, message = http.StatusUnauthorized, "Login melalui penyedia identitas gagal."
+	case errors.Is(err, models.ErrOIDCEmailNotVerified):
+		status, message = http.StatusForbidden, "Email Anda belum diverifikasi oleh penyedia identitas."
+	case errors.Is(err, models.ErrOIDCDomainNotAllowed):
+		status, message = http.StatusForbidden, "Domain email Anda tidak diizinkan."
+	case errors.Is(err, models.ErrOIDCAccountNotFound):
+		status, message = http.StatusForbidden, "Akun belum terdaftar."
+	case errors.Is(err, models.ErrOIDCIdentityConflict):
+		status, message = http.StatusForbidden, "Email ini sudah terhubung dengan identitas lain."
+	default:
+		respondLoginError(c, err)
+		return
+	}
+	metrics.LoginAttempts.WithLabelValues(metrics.LoginOIDCRejected).Inc()
+	c.JSON(status, gin.H{"error": message})
+}
@@ -0,0 +1,118 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"fullstack-crud-project-01/backend-go/handlers"
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/repositories"
+	"fullstack-crud-project-01/backend-go/services"
+	"fullstack-crud-project-01/backend-go/services/oidctest"
+	"fullstack-crud-project-01/backend-go/utils"
+)
+
+func TestOIDC_LoginFlow(t *testing.T) {
+	testDB.Exec("DELETE FROM users")
+	provider := oidctest.NewProvider(t, "backend", "rahasia-client")
+	provider.SetIdentity(oidctest.Identity{Subject: "google-42", Email: "sso@example.com", EmailVerified: true, Name: "Pengguna SSO"})
+
+	authService := services.NewAuthService(repositories.NewUserRepository(testDB))
+	oidcService := services.NewOIDCService(authService, provider.Issuer(), "backend", "rahasia-client",
+		"https://app.test/api/v1/auth/oidc/callback")
+	oidcHandler := handlers.NewOIDCHandler(oidcService)
+
+	r := gin.New()
+	auth := r.Group("/api/v1/auth")
+	auth.GET("/oidc/login", oidcHandler.LoginHandler)
+	auth.GET("/oidc/callback", oidcHandler.CallbackHandler)
+
+	serve := func(path string, cookie *http.Cookie) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", path, nil)
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	flowCookie := func(w *httptest.ResponseRecorder) *http.Cookie {
+		for _, cookie := range w.Result().Cookies() {
+			if cookie.Name == handlers.OIDCFlowCookie {
+				return cookie
+			}
+		}
+		return nil
+	}
+
+	// 1. Mulai login: redirect ke penyedia dengan cookie alur
+	w := serve("/api/v1/auth/oidc/login", nil)
+	require.Equal(t, http.StatusFound, w.Code, w.Body.String())
+	location := w.Header().Get("Location")
+	assert.True(t, strings.HasPrefix(location, provider.Issuer()+"/authorize?"))
+	cookie := flowCookie(w)
+	require.NotNil(t, cookie)
+	assert.True(t, cookie.HttpOnly)
+	assert.True(t, cookie.Secure, "Redirect URL https")
+	assert.Equal(t, "/api/v1/auth/oidc", cookie.Path)
+
+	// 2. Penyedia mengarahkan kembali ke callback
+	callback := provider.Authorize(t, location)
+	assert.Equal(t, "/api/v1/auth/oidc/callback", callback.Path)
+
+	// Callback tanpa cookie alur (mis. dipicu dari situs lain) ditolak
+	w = serve(callback.RequestURI(), nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// 3. Callback dengan cookie: akun dibuat dan JWT aplikasi diterbitkan
+	w = serve(callback.RequestURI(), cookie)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	token, _ := response["token"].(string)
+	claims, err := utils.ValidateToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, "sso@example.com", claims.Email)
+	assert.Equal(t, -1, flowCookie(w).MaxAge, "Cookie alur dihapus")
+
+	var user models.User
+	require.NoError(t, testDB.Where("email = ?", "sso@example.com").First(&user).Error)
+	assert.True(t, user.IsActive)
+	assert.Equal(t, "google-42", *user.OIDCSubject)
+
+	// Penyedia mengembalikan error (mis. pengguna menolak persetujuan)
+	w = serve("/api/v1/auth/oidc/callback?error=access_denied&state=x", cookie)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "access_denied")
+}
+
+func TestOIDC_Callback_DomainNotAllowed(t *testing.T) {
+	testDB.Exec("DELETE FROM users")
+	provider := oidctest.NewProvider(t, "backend", "")
+	oidcService := services.NewOIDCService(services.NewAuthService(repositories.NewUserRepository(testDB)),
+		provider.Issuer(), "backend", "", "http://app.test/api/v1/auth/oidc/callback")
+	oidcService.AllowedDomains = []string{"perusahaan.co.id"}
+
+	req, err := oidcService.Begin(t.Context())
+	require.NoError(t, err)
+	callback := provider.Authorize(t, req.URL)
+
+	r := gin.New()
+	r.GET("/api/v1/auth/oidc/callback", handlers.NewOIDCHandler(oidcService).CallbackHandler)
+	httpReq, _ := http.NewRequest("GET", callback.RequestURI(), nil)
+	httpReq.AddCookie(&http.Cookie{Name: handlers.OIDCFlowCookie, Value: req.FlowToken})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httpReq)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	var count int64
+	testDB.Model(&models.User{}).Count(&count)
+	assert.Zero(t, count)
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	"fullstack-crud-project-01/backend-go/middleware"
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/presence"
+	"fullstack-crud-project-01/backend-go/utils"
+)
+
+// errTokenExpired menutup koneksi WebSocket yang token-nya sudah kedaluwarsa
+var errTokenExpired = errors.New("token kedaluwarsa")
+
+// PresenceHandler meng-upgrade request menjadi WebSocket untuk kehadiran & perubahan produk
+type PresenceHandler struct {
+	Hub            *presence.Hub
+	AllowedOrigins []string
+	Sessions       middleware.SessionValidator // Opsional: nil berarti sesi token tidak diperiksa
+	upgrader       websocket.Upgrader
+}
+
+// NewPresenceHandler adalah konstruktor untuk PresenceHandler.
+// allowedOrigins membatasi Origin browser yang boleh membuka koneksi (klien non-browser tanpa Origin selalu diizinkan).
+func NewPresenceHandler(hub *presence.Hub, allowedOrigins []string) *PresenceHandler {
+	h := &PresenceHandler{Hub: hub, AllowedOrigins: allowedOrigins}
+	h.upgrader = websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+		CheckOrigin:     h.checkOrigin,
+	}
+	return h
+}
+
+// ServeWebSocketHandler memvalidasi JWT lalu meng-upgrade koneksi.
+// Browser tidak bisa mengirim header Authorization pada WebSocket, sehingga token
+// juga diterima melalui query string ?token=.
+func (h *PresenceHandler) ServeWebSocketHandler(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		token = strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	}
+	if token == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token diperlukan."})
+		return
+	}
+
+	claims, err := utils.ValidateToken(token)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token tidak valid atau kedaluwarsa.", "details": err.Error()})
+		return
+	}
+	if h.Sessions != nil {
+		if err := h.Sessions.Validate(c.Request.Context(), claims); err != nil {
+			if errors.Is(err, models.ErrSessionRevoked) {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Sesi telah berakhir. Silakan login ulang."})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memeriksa sesi."})
+			return
+		}
+	}
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// Upgrader sudah menulis respons error ke klien
+		return
+	}
+
+	// Sesi diperiksa ulang selama koneksi terbuka agar logout/pencabutan sesi juga memutus WebSocket
+	ctx := c.Request.Context()
+	h.Hub.ServeWithCheck(conn, presence.User{ID: claims.UserID, Email: claims.Email}, func() error {
+		return h.recheck(ctx, claims)
+	})
+}
+
+// recheck memastikan token koneksi belum kedaluwarsa dan sesinya belum dicabut. Kegagalan
+// memeriksa sesi (mis. database sementara tidak tersedia) hanya di-log agar koneksi tidak putus.
+func (h *PresenceHandler) recheck(ctx context.Context, claims *utils.CustomClaims) error {
+	if claims.ExpiresAt != nil && !time.Now().Before(claims.ExpiresAt.Time) {
+		return errTokenExpired
+	}
+	if h.Sessions == nil {
+		return nil
+	}
+	err := h.Sessions.Validate(ctx, claims)
+	if errors.Is(err, models.ErrSessionRevoked) {
+		return err
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "Gagal memeriksa ulang sesi WebSocket", "user_id", claims.UserID, "error", err)
+	}
+	return nil
+}
+
+func (h *PresenceHandler) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	for _, allowed := range h.AllowedOrigins {
+		if origin == allowed {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"fullstack-crud-project-01/backend-go/events"
+)
+
+// defaultSSEHeartbeat adalah interval komentar keep-alive agar proxy tidak menutup koneksi idle
+const defaultSSEHeartbeat = 15 * time.Second
+
+// ProductEventHandler mengalirkan perubahan produk ke klien melalui Server-Sent Events
+type ProductEventHandler struct {
+	Bus       *events.Bus
+	Heartbeat time.Duration
+
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// NewProductEventHandler adalah konstruktor untuk ProductEventHandler
+func NewProductEventHandler(bus *events.Bus) *ProductEventHandler {
+	return &ProductEventHandler{Bus: bus, Heartbeat: defaultSSEHeartbeat, closed: make(chan struct{})}
+}
+
+// Close mengakhiri semua stream yang sedang terbuka. Dipanggil saat server shutdown
+// karena stream SSE tidak pernah selesai sendiri; klien akan tersambung ulang ke instance lain.
+func (h *ProductEventHandler) Close() {
+	h.closeOnce.Do(func() { close(h.closed) })
+}
+
+// StreamProductEventsHandler membuka stream SSE berisi event product.created/updated/deleted.
+// Klien bisa melanjutkan stream dengan header Last-Event-ID (atau query last_event_id).
+// Jika event yang terlewat sudah tidak ada di log, dikirim event "reset" agar klien memuat ulang daftar produk.
+func (h *ProductEventHandler) StreamProductEventsHandler(c *gin.Context) {
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+
+	var afterID uint64
+	if lastEventID != "" {
+		id, err := strconv.ParseUint(lastEventID, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Last-Event-ID tidak valid"})
+			return
+		}
+		afterID = id
+	}
+
+	sub, replay, complete := h.Bus.Subscribe(afterID)
+	defer sub.Cancel()
+
+	// Stream berumur panjang: lepaskan WriteTimeout server untuk koneksi ini saja
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Matikan buffering di reverse proxy (nginx)
+	c.Status(http.StatusOK)
+
+	if !complete {
+		writeSSE(c, h.Bus.LastID(), "reset", []byte(`{}`))
+	}
+	for _, event := range replay {
+		writeSSE(c, event.ID, event.Type, event.Data)
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(h.Heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-h.closed:
+			return
+		case event, ok := <-sub.C:
+			if !ok {
+				// Subscriber diputus oleh bus (terlalu lambat); klien akan tersambung ulang
+				return
+			}
+			writeSSE(c, event.ID, event.Type, event.Data)
+			c.Writer.Flush()
+		case <-heartbeat.C:
+			fmt.Fprint(c.Writer, ": ping\n\n")
+			c.Writer.Flush()
+		}
+	}
+}
+
+// writeSSE menulis satu event dalam format text/event-stream
+func writeSSE(c *gin.Context, id uint64, eventType string, data []byte) {
+	fmt.Fprintf(c.Writer, "id: %d\nevent: %s\ndata: %s\n\n", id, eventType, data)
+}
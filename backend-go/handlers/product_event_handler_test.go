@@ -0,0 +1,224 @@
+package handlers_test
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"fullstack-crud-project-01/backend-go/events"
+	"fullstack-crud-project-01/backend-go/handlers"
+	"fullstack-crud-project-01/backend-go/middleware"
+	"fullstack-crud-project-01/backend-go/utils"
+)
+
+// sseEvent adalah satu event yang dibaca dari stream text/event-stream
+type sseEvent struct {
+	ID   string
+	Type string
+	Data string
+}
+
+// setupEventStream menyiapkan server dengan rantai middleware yang sama seperti route /products/events
+func setupEventStream(t *testing.T, bus *events.Bus) (*httptest.Server, *handlers.ProductEventHandler) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	handler := handlers.NewProductEventHandler(bus)
+
+	r := gin.New()
+	r.GET("/products/events", middleware.TokenFromQuery(), middleware.AuthMiddlewareWith(nil, nil), handler.StreamProductEventsHandler)
+	r.GET("/products/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	srv := httptest.NewServer(r)
+	t.Cleanup(func() {
+		handler.Close()
+		srv.Close()
+	})
+	return srv, handler
+}
+
+// openStream membuka stream SSE dan mengembalikan channel event yang dibaca darinya
+func openStream(t *testing.T, req *http.Request) (*http.Response, <-chan sseEvent) {
+	t.Helper()
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+
+	received := make(chan sseEvent, 16)
+	go func() {
+		defer close(received)
+		var event sseEvent
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case strings.HasPrefix(line, "id: "):
+				event.ID = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "event: "):
+				event.Type = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				event.Data = strings.TrimPrefix(line, "data: ")
+			case line == "" && event.Type != "":
+				received <- event
+				event = sseEvent{}
+			}
+		}
+	}()
+	return resp, received
+}
+
+// nextEvent menunggu event berikutnya dari stream
+func nextEvent(t *testing.T, received <-chan sseEvent) sseEvent {
+	t.Helper()
+	select {
+	case event, ok := <-received:
+		require.True(t, ok, "Stream ditutup sebelum event diterima")
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timeout menunggu event SSE")
+		return sseEvent{}
+	}
+}
+
+func newStreamRequest(t *testing.T, ctx context.Context, url string) *http.Request {
+	t.Helper()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	require.NoError(t, err)
+	return req
+}
+
+func TestProductEventHandler_Auth(t *testing.T) {
+	srv, _ := setupEventStream(t, events.NewBus(10))
+	token, err := utils.GenerateSessionToken(1, "stream@test.com", "user", "sesi-test")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		query        string
+		header       string
+		expectedCode int
+	}{
+		{name: "Failure_NoToken", expectedCode: http.StatusUnauthorized},
+		{name: "Failure_InvalidQueryToken", query: "?token=salah", expectedCode: http.StatusUnauthorized},
+		{name: "Success_QueryToken", query: "?token=" + token, expectedCode: http.StatusOK},
+		{name: "Success_HeaderToken", header: "Bearer " + token, expectedCode: http.StatusOK},
+		{name: "Failure_HeaderTakesPrecedence", query: "?token=" + token, header: "Bearer salah", expectedCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			req := newStreamRequest(t, ctx, srv.URL+"/products/events"+tt.query)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.expectedCode, resp.StatusCode)
+			if tt.expectedCode == http.StatusOK {
+				assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestProductEventHandler_ResumeFromLastEventID(t *testing.T) {
+	bus := events.NewBus(10)
+	srv, _ := setupEventStream(t, bus)
+	token, err := utils.GenerateSessionToken(1, "stream@test.com", "user", "sesi-test")
+	require.NoError(t, err)
+
+	require.NoError(t, bus.Publish(events.ProductCreated, 1, map[string]uint{"id": 1}))
+	require.NoError(t, bus.Publish(events.ProductUpdated, 1, map[string]uint{"id": 1}))
+	require.NoError(t, bus.Publish(events.ProductDeleted, 1, map[string]uint{"id": 1}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req := newStreamRequest(t, ctx, srv.URL+"/products/events?token="+token)
+	req.Header.Set("Last-Event-ID", "1")
+	resp, received := openStream(t, req)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Event setelah Last-Event-ID diputar ulang berurutan, event 1 tidak dikirim lagi
+	assert.Equal(t, sseEvent{ID: "2", Type: events.ProductUpdated, Data: `{"id":1}`}, nextEvent(t, received))
+	assert.Equal(t, sseEvent{ID: "3", Type: events.ProductDeleted, Data: `{"id":1}`}, nextEvent(t, received))
+
+	// Event baru diteruskan secara langsung setelah replay
+	require.NoError(t, bus.Publish(events.ProductCreated, 2, map[string]uint{"id": 2}))
+	assert.Equal(t, sseEvent{ID: "4", Type: events.ProductCreated, Data: `{"id":2}`}, nextEvent(t, received))
+}
+
+func TestProductEventHandler_ResetWhenEventsMissing(t *testing.T) {
+	bus := events.NewBus(2)
+	srv, _ := setupEventStream(t, bus)
+	token, err := utils.GenerateSessionToken(1, "stream@test.com", "user", "sesi-test")
+	require.NoError(t, err)
+
+	for id := uint(1); id <= 4; id++ {
+		require.NoError(t, bus.Publish(events.ProductCreated, id, map[string]uint{"id": id}))
+	}
+
+	tests := []struct {
+		name        string
+		lastEventID string
+	}{
+		{name: "EventsDroppedFromLog", lastEventID: "1"},
+		{name: "UnknownIDAfterRestart", lastEventID: "99"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			// EventSource mengirim ulang ID terakhir lewat header; query last_event_id untuk klien fetch
+			req := newStreamRequest(t, ctx, srv.URL+"/products/events?token="+token+"&last_event_id="+tt.lastEventID)
+			resp, received := openStream(t, req)
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			// Klien harus memuat ulang daftar produk; ID reset adalah ID terakhir di bus
+			assert.Equal(t, sseEvent{ID: "4", Type: "reset", Data: `{}`}, nextEvent(t, received))
+		})
+	}
+}
+
+func TestProductEventHandler_InvalidLastEventID(t *testing.T) {
+	srv, _ := setupEventStream(t, events.NewBus(10))
+	token, err := utils.GenerateSessionToken(1, "stream@test.com", "user", "sesi-test")
+	require.NoError(t, err)
+
+	req := newStreamRequest(t, context.Background(), srv.URL+"/products/events?token="+token)
+	req.Header.Set("Last-Event-ID", "bukan-angka")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestProductEventHandler_CloseEndsStream(t *testing.T) {
+	srv, handler := setupEventStream(t, events.NewBus(10))
+	token, err := utils.GenerateSessionToken(1, "stream@test.com", "user", "sesi-test")
+	require.NoError(t, err)
+
+	resp, received := openStream(t, newStreamRequest(t, context.Background(), srv.URL+"/products/events?token="+token))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	handler.Close()
+	select {
+	case _, ok := <-received:
+		assert.False(t, ok, "Stream harus berakhir setelah Close")
+	case <-time.After(2 * time.Second):
+		t.Fatal("Stream tidak berakhir setelah Close")
+	}
+}
@@ -24,13 +24,17 @@ func NewProductHandler(svc *services.ProductService) *ProductHandler {
 func (h *ProductHandler) CreateProductHandler(c *gin.Context) {
 	var product models.Product
     
-	if err := c.ShouldBindJSON(&product); err != nil {
+	if err := bindJSON(c, &product); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
     // Panggil Service
-	if err := h.ProductSvc.CreateProduct(&product); err != nil {
+    // auditContext membawa aktor, IP dan user agent untuk audit log di service
+	if err := h.ProductSvc.CreateProduct(auditContext(c), &product); err != nil {
+        if respondContextError(c, err) {
+            return
+        }
         if err == models.ErrProductNameRequired {
              c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()}) // Bad Request for validation
              return
@@ -44,8 +48,11 @@ func (h *ProductHandler) CreateProductHandler(c *gin.Context) {
 
 // ReadAllProductsHandler
 func (h *ProductHandler) ReadAllProductsHandler(c *gin.Context) {
-	products, err := h.ProductSvc.ReadAllProducts()
+	products, err := h.ProductSvc.ReadAllProducts(c.Request.Context())
 	if err != nil {
+		if respondContextError(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil daftar produk"})
 		return
 	}
@@ -60,8 +67,11 @@ func (h *ProductHandler) ReadProductByIDHandler(c *gin.Context) {
 		return
 	}
 
-	product, err := h.ProductSvc.ReadProductByID(uint(id))
+	product, err := h.ProductSvc.ReadProductByID(c.Request.Context(), uint(id))
 	if err != nil {
+		if respondContextError(c, err) {
+			return
+		}
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Produk tidak ditemukan"})
 			return
@@ -81,7 +91,7 @@ func (h *ProductHandler) UpdateProductHandler(c *gin.Context) {
 	}
 
 	var input models.Product
-	if err := c.ShouldBindJSON(&input); err != nil {
+	if err := bindJSON(c, &input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -89,7 +99,10 @@ func (h *ProductHandler) UpdateProductHandler(c *gin.Context) {
     // Set ID dari URL ke struct input
     input.ID = uint(id)
 
-    if err := h.ProductSvc.UpdateProduct(&input); err != nil {
+    if err := h.ProductSvc.UpdateProduct(auditContext(c), &input); err != nil {
+        if respondContextError(c, err) {
+            return
+        }
         if errors.Is(err, gorm.ErrRecordNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Produk tidak ditemukan"})
 			return
@@ -109,7 +122,10 @@ func (h *ProductHandler) DeleteProductHandler(c *gin.Context) {
 		return
 	}
 
-	if err := h.ProductSvc.DeleteProduct(uint(id)); err != nil {
+	if err := h.ProductSvc.DeleteProduct(auditContext(c), uint(id)); err != nil {
+		if respondContextError(c, err) {
+			return
+		}
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Produk tidak ditemukan"})
 			return
@@ -117,6 +133,7 @@ func (h *ProductHandler) DeleteProductHandler(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghapus produk"})
 		return
 	}
+
     
     // Status 204 No Content untuk operasi penghapusan yang sukses
 	c.JSON(http.StatusNoContent, nil) 
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"fullstack-crud-project-01/backend-go/middleware"
+	"fullstack-crud-project-01/backend-go/services"
+)
+
+// SessionHandler menyediakan endpoint pengguna yang sudah login untuk melihat dan mencabut sesi
+type SessionHandler struct {
+	SessionSvc *services.SessionService
+}
+
+// NewSessionHandler adalah konstruktor untuk SessionHandler
+func NewSessionHandler(svc *services.SessionService) *SessionHandler {
+	return &SessionHandler{SessionSvc: svc}
+}
+
+// ListSessionsHandler mengembalikan sesi aktif milik pengguna; sesi request ini ditandai current
+func (h *SessionHandler) ListSessionsHandler(c *gin.Context) {
+	claims, _ := middleware.CurrentClaims(c)
+	sessions, err := h.SessionSvc.List(c.Request.Context(), claims.UserID, claims.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil daftar sesi"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": sessions})
+}
+
+// RevokeSessionHandler mencabut sesi milik pengguna. Mencabut sesi sendiri sama dengan logout.
+func (h *SessionHandler) RevokeSessionHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID sesi tidak valid"})
+		return
+	}
+
+	claims, _ := middleware.CurrentClaims(c)
+	if err := h.SessionSvc.Revoke(auditContext(c), claims.UserID, uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Sesi tidak ditemukan"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mencabut sesi"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,129 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"fullstack-crud-project-01/backend-go/handlers"
+	"fullstack-crud-project-01/backend-go/middleware"
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/repositories"
+	"fullstack-crud-project-01/backend-go/services"
+	"fullstack-crud-project-01/backend-go/utils"
+)
+
+func TestSessions_ListAndRevoke(t *testing.T) {
+	testDB.Exec("DELETE FROM sessions")
+	testDB.Exec("DELETE FROM users")
+	hash, err := utils.HashPassword("password123")
+	require.NoError(t, err)
+	user := models.User{Email: "sesi@test.com", PasswordHash: hash, IsActive: true, Role: models.RoleUser}
+	require.NoError(t, testDB.Create(&user).Error)
+
+	sessionService := services.NewSessionService(repositories.NewSessionRepository(testDB))
+	authService := services.NewAuthService(repositories.NewUserRepository(testDB))
+	authService.Sessions = sessionService
+	sessionHandler := handlers.NewSessionHandler(sessionService)
+
+	r := gin.New()
+	r.POST("/api/v1/auth/login", handlers.NewAuthHandler(authService).LoginUser)
+	sessions := r.Group("/api/v1/auth/sessions", middleware.AuthMiddlewareWith(sessionService, nil))
+	sessions.GET("", sessionHandler.ListSessionsHandler)
+	sessions.DELETE("/:id", sessionHandler.RevokeSessionHandler)
+
+	serve := func(method, path, body, userAgent, token string) (*httptest.ResponseRecorder, map[string]interface{}) {
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", userAgent)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w, response
+	}
+	login := func(userAgent string) string {
+		w, response := serve("POST", "/api/v1/auth/login", `{"email":"sesi@test.com","password":"password123"}`, userAgent, "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		token, _ := response["token"].(string)
+		return token
+	}
+
+	// Login dari dua perangkat
+	laptop := login("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0 Safari/537.36")
+	phone := login("Mozilla/5.0 (iPhone; CPU iPhone OS 17_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.5 Mobile/15E148 Safari/604.1")
+
+	w, response := serve("GET", "/api/v1/auth/sessions", "", "", laptop)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	list, _ := response["data"].([]interface{})
+	require.Len(t, list, 2)
+	var phoneID string
+	for _, item := range list {
+		session := item.(map[string]interface{})
+		assert.NotContains(t, session, "token_id")
+		switch session["device"] {
+		case "Chrome di Windows":
+			assert.Equal(t, true, session["current"])
+		case "Safari di iOS":
+			assert.Equal(t, false, session["current"])
+			phoneID = strconv.Itoa(int(session["id"].(float64)))
+		default:
+			t.Errorf("Perangkat tidak terduga: %v", session["device"])
+		}
+	}
+	require.NotEmpty(t, phoneID)
+
+	// Mencabut sesi ponsel: tokennya langsung ditolak, sesi laptop tetap berlaku
+	w, _ = serve("DELETE", "/api/v1/auth/sessions/"+phoneID, "", "", laptop)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	w, _ = serve("GET", "/api/v1/auth/sessions", "", "", phone)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	w, response = serve("GET", "/api/v1/auth/sessions", "", "", laptop)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, response["data"], 1)
+
+	w, _ = serve("DELETE", "/api/v1/auth/sessions/"+phoneID, "", "", laptop)
+	assert.Equal(t, http.StatusNotFound, w.Code, "Sudah dicabut")
+	w, _ = serve("DELETE", "/api/v1/auth/sessions/abc", "", "", laptop)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Sesi milik pengguna lain tidak bisa dicabut
+	otherSession, err := sessionService.Start(context.Background(), &models.User{ID: user.ID + 1})
+	require.NoError(t, err)
+	other, err := utils.GenerateSessionToken(user.ID+1, "lain@test.com", models.RoleUser, otherSession.TokenID)
+	require.NoError(t, err)
+	var laptopSession models.Session
+	require.NoError(t, testDB.Where("user_id = ?", user.ID).First(&laptopSession).Error)
+	w, _ = serve("DELETE", "/api/v1/auth/sessions/"+strconv.Itoa(int(laptopSession.ID)), "", "", other)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// Token tanpa jti yang terbit setelah proses dimulai ditolak meskipun tanda tangannya sah
+	now := time.Now()
+	legacy, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &utils.CustomClaims{
+		UserID: user.ID,
+		Email:  user.Email,
+		Role:   user.Role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{"go-backend-api"},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		},
+	}).SignedString([]byte(os.Getenv("JWT_SECRET_KEY")))
+	require.NoError(t, err)
+	w, _ = serve("GET", "/api/v1/auth/sessions", "", "", legacy)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// tracerName adalah nama instrumentasi span di level handler
+const tracerName = "fullstack-crud-project-01/backend-go/handlers"
+
+// bindJSON mem-parse body JSON dalam span tersendiri sehingga waktu binding
+// bisa dibedakan dari validasi dan query di trace
+func bindJSON(c *gin.Context, obj interface{}) error {
+	_, span := otel.Tracer(tracerName).Start(c.Request.Context(), "bind JSON")
+	defer span.End()
+
+	err := c.ShouldBindJSON(obj)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
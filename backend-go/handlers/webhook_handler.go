@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"fullstack-crud-project-01/backend-go/dto"
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/services"
+)
+
+// WebhookHandler menyediakan endpoint admin untuk mengelola webhook keluar
+type WebhookHandler struct {
+	WebhookSvc *services.WebhookService
+}
+
+// NewWebhookHandler adalah konstruktor untuk WebhookHandler
+func NewWebhookHandler(svc *services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{WebhookSvc: svc}
+}
+
+// CreateWebhookHandler mendaftarkan subscription baru.
+// Secret hanya dikembalikan sekali di respons ini untuk memverifikasi tanda tangan.
+func (h *WebhookHandler) CreateWebhookHandler(c *gin.Context) {
+	var req dto.WebhookSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Permintaan tidak valid", "details": err.Error()})
+		return
+	}
+
+	sub := models.WebhookSubscription{URL: req.URL, Secret: req.Secret, Events: req.EventTypes}
+	if err := h.WebhookSvc.CreateSubscription(&sub); err != nil {
+		if errors.Is(err, models.ErrWebhookURLInvalid) ||
+			errors.Is(err, models.ErrWebhookEventTypesRequired) ||
+			errors.Is(err, models.ErrWebhookEventTypeInvalid) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan webhook"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": sub, "secret": sub.Secret})
+}
+
+// ListWebhooksHandler mengembalikan semua subscription
+func (h *WebhookHandler) ListWebhooksHandler(c *gin.Context) {
+	subs, err := h.WebhookSvc.ListSubscriptions()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil daftar webhook"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": subs})
+}
+
+// DeleteWebhookHandler menghapus subscription beserta antreannya
+func (h *WebhookHandler) DeleteWebhookHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID webhook tidak valid"})
+		return
+	}
+
+	if err := h.WebhookSvc.DeleteSubscription(uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Webhook tidak ditemukan"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghapus webhook"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListDeliveriesHandler mengembalikan log pengiriman terbaru sebuah subscription
+func (h *WebhookHandler) ListDeliveriesHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID webhook tidak valid"})
+		return
+	}
+
+	deliveries, err := h.WebhookSvc.ListDeliveries(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Webhook tidak ditemukan"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil log pengiriman"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": deliveries})
+}
+
+// RedeliverHandler menjadwalkan ulang sebuah pengiriman secara manual
+func (h *WebhookHandler) RedeliverHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID pengiriman tidak valid"})
+		return
+	}
+
+	delivery, err := h.WebhookSvc.Redeliver(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Pengiriman tidak ditemukan"})
+			return
+		}
+		if errors.Is(err, models.ErrWebhookDeliveryPending) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menjadwalkan ulang pengiriman"})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"data": delivery})
+}
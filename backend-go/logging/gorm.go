@@ -0,0 +1,107 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// defaultSlowQuery adalah batas durasi query yang dicatat sebagai lambat
+const defaultSlowQuery = 200 * time.Millisecond
+
+// GormLogger meneruskan log GORM ke slog dengan request ID dari context query.
+// SQL selalu ditulis dengan placeholder tanpa nilai parameter agar password,
+// hash dan token di dalam query tidak pernah masuk ke log.
+//
+// Query biasa dicatat di level debug, query lambat di warn dan error di error.
+type GormLogger struct {
+	Logger        *slog.Logger // nil: memakai slog.Default() saat log ditulis
+	SlowThreshold time.Duration
+	silent        bool
+}
+
+// NewGormLogger adalah konstruktor untuk GormLogger
+func NewGormLogger(logger *slog.Logger) *GormLogger {
+	return &GormLogger{Logger: logger, SlowThreshold: defaultSlowQuery}
+}
+
+func (l *GormLogger) logger() *slog.Logger {
+	if l.Logger != nil {
+		return l.Logger
+	}
+	return slog.Default()
+}
+
+// LogMode memenuhi logger.Interface; hanya level Silent yang berpengaruh karena
+// penyaringan level diatur oleh slog
+func (l *GormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	clone := *l
+	clone.silent = level == gormlogger.Silent
+	return &clone
+}
+
+func (l *GormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	l.log(ctx, slog.LevelInfo, msg, data...)
+}
+
+func (l *GormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	l.log(ctx, slog.LevelWarn, msg, data...)
+}
+
+func (l *GormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	l.log(ctx, slog.LevelError, msg, data...)
+}
+
+func (l *GormLogger) log(ctx context.Context, level slog.Level, msg string, data ...interface{}) {
+	if l.silent {
+		return
+	}
+	l.logger().Log(ctx, level, fmt.Sprintf(msg, data...))
+}
+
+// Trace mencatat satu query beserta durasi dan jumlah baris
+func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.silent {
+		return
+	}
+	elapsed := time.Since(begin)
+	slow := l.SlowThreshold > 0 && elapsed > l.SlowThreshold
+	failed := err != nil && !errors.Is(err, gorm.ErrRecordNotFound)
+
+	level := slog.LevelDebug
+	switch {
+	case failed:
+		level = slog.LevelError
+	case slow:
+		level = slog.LevelWarn
+	}
+	logger := l.logger()
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+
+	sql, rows := fc()
+	attrs := []slog.Attr{
+		slog.String("sql", sql),
+		slog.Int64("rows", rows),
+		slog.Float64("duration_ms", float64(elapsed)/float64(time.Millisecond)),
+	}
+	msg := "query"
+	if failed {
+		msg = "query gagal"
+		attrs = append(attrs, slog.String("error", err.Error()))
+	} else if slow {
+		msg = "query lambat"
+	}
+	logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// ParamsFilter membuang nilai parameter sehingga SQL dicatat dengan placeholder
+func (l *GormLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	return sql, nil
+}
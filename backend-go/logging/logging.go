@@ -0,0 +1,101 @@
+// Package logging menyiapkan logger terstruktur (log/slog) aplikasi: format JSON atau
+// teks, request ID dari context di setiap baris, dan penyamaran data sensitif.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Format log yang didukung (nilai LOG_FORMAT)
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// Redacted menggantikan nilai atribut sensitif
+const Redacted = "[REDACTED]"
+
+// sensitiveKeys adalah nama atribut (tanpa membedakan huruf besar/kecil) yang nilainya
+// tidak pernah ditulis ke log
+var sensitiveKeys = map[string]bool{
+	"authorization": true,
+	"cookie":        true,
+	"password":      true,
+	"password_hash": true,
+	"secret":        true,
+	"token":         true,
+}
+
+// New membuat logger yang menulis ke w dengan level minimum dan format tertentu
+func New(w io.Writer, level slog.Leveler, format string) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: level, ReplaceAttr: redact}
+	var handler slog.Handler
+	if format == FormatText {
+		handler = slog.NewTextHandler(w, opts)
+	} else {
+		handler = slog.NewJSONHandler(w, opts)
+	}
+	return slog.New(contextHandler{handler})
+}
+
+// Setup memasang logger ke stderr sebagai slog default. Paket log standar ikut
+// diarahkan ke logger ini sehingga log dari library pihak ketiga tetap terstruktur.
+func Setup(level slog.Level, format string) *slog.Logger {
+	logger := New(os.Stderr, level, format)
+	slog.SetDefault(logger)
+	return logger
+}
+
+// redact menyamarkan atribut sensitif, termasuk yang berada di dalam group (mis. header)
+func redact(groups []string, a slog.Attr) slog.Attr {
+	if sensitiveKeys[strings.ToLower(a.Key)] {
+		return slog.String(a.Key, Redacted)
+	}
+	return a
+}
+
+type requestIDKey struct{}
+
+// WithRequestID menyimpan request ID ke dalam context
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFrom mengambil request ID dari context; kosong jika tidak ada
+func RequestIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// contextHandler menambahkan request_id serta trace_id/span_id OpenTelemetry dari context
+// ke setiap record, sehingga cukup memanggil slog.InfoContext(ctx, ...) di service
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestIDFrom(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(
+			slog.String("trace_id", sc.TraceID().String()),
+			slog.String("span_id", sc.SpanID().String()),
+		)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,103 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"fullstack-crud-project-01/backend-go/logging"
+)
+
+// decodeLines mem-parse setiap baris output JSON logger
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var lines []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &entry), line)
+		lines = append(lines, entry)
+	}
+	return lines
+}
+
+func TestLogger_RequestIDAndRedaction(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.New(&buf, slog.LevelInfo, logging.FormatJSON)
+	ctx := logging.WithRequestID(context.Background(), "req-123")
+
+	logger.InfoContext(ctx, "login",
+		"email", "budi@example.com",
+		"Password", "rahasia123",
+		slog.Group("headers", "Authorization", "Bearer abc.def", "Accept", "application/json"),
+	)
+	logger.Debug("tidak tercatat di level info")
+
+	lines := decodeLines(t, &buf)
+	require.Len(t, lines, 1)
+	entry := lines[0]
+	assert.Equal(t, "req-123", entry["request_id"])
+	assert.Equal(t, "budi@example.com", entry["email"])
+	assert.Equal(t, logging.Redacted, entry["Password"])
+	headers := entry["headers"].(map[string]interface{})
+	assert.Equal(t, logging.Redacted, headers["Authorization"])
+	assert.Equal(t, "application/json", headers["Accept"])
+	assert.NotContains(t, buf.String(), "rahasia123")
+}
+
+func TestGormLogger_Trace(t *testing.T) {
+	tests := []struct {
+		name      string
+		level     slog.Level
+		elapsed   time.Duration
+		err       error
+		wantLevel string
+		wantMsg   string
+	}{
+		{"Query biasa di level debug", slog.LevelDebug, time.Millisecond, nil, "DEBUG", "query"},
+		{"Query biasa tidak tercatat di level info", slog.LevelInfo, time.Millisecond, nil, "", ""},
+		{"Record not found bukan error", slog.LevelInfo, time.Millisecond, gorm.ErrRecordNotFound, "", ""},
+		{"Query lambat", slog.LevelInfo, time.Second, nil, "WARN", "query lambat"},
+		{"Query gagal", slog.LevelInfo, time.Millisecond, errors.New("no such table"), "ERROR", "query gagal"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			gormLogger := logging.NewGormLogger(logging.New(&buf, tt.level, logging.FormatJSON))
+			ctx := logging.WithRequestID(context.Background(), "req-sql")
+
+			gormLogger.Trace(ctx, time.Now().Add(-tt.elapsed), func() (string, int64) {
+				return "SELECT * FROM users WHERE email = ?", 1
+			}, tt.err)
+
+			lines := decodeLines(t, &buf)
+			if tt.wantLevel == "" {
+				assert.Empty(t, lines)
+				return
+			}
+			require.Len(t, lines, 1)
+			assert.Equal(t, tt.wantLevel, lines[0]["level"])
+			assert.Equal(t, tt.wantMsg, lines[0]["msg"])
+			assert.Equal(t, "req-sql", lines[0]["request_id"])
+			assert.Equal(t, "SELECT * FROM users WHERE email = ?", lines[0]["sql"])
+		})
+	}
+}
+
+func TestGormLogger_ParamsFilterDropsValues(t *testing.T) {
+	sql, params := logging.NewGormLogger(nil).ParamsFilter(context.Background(), "UPDATE users SET password = ?", "$2a$10$hash")
+	assert.Equal(t, "UPDATE users SET password = ?", sql)
+	assert.Empty(t, params)
+}
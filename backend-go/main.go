@@ -1,53 +1,249 @@
 package main
 
 import (
-	"log"
+	"context"
+	"flag"
+	"log/slog"
+	"net/http"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-contrib/cors"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	
 	"fullstack-crud-project-01/backend-go/config"
+	"fullstack-crud-project-01/backend-go/database"
+	"fullstack-crud-project-01/backend-go/events"
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/metrics"
 	"fullstack-crud-project-01/backend-go/middleware"
 	"fullstack-crud-project-01/backend-go/handlers"
+	"fullstack-crud-project-01/backend-go/outbox"
+	"fullstack-crud-project-01/backend-go/presence"
+	"fullstack-crud-project-01/backend-go/ratelimit"
 	"fullstack-crud-project-01/backend-go/services"
+	"fullstack-crud-project-01/backend-go/tracing"
 	"fullstack-crud-project-01/backend-go/repositories"
 )
 
+// Batas waktu query database per jenis route produk
+const (
+	readQueryTimeout  = 3 * time.Second
+	listQueryTimeout  = 5 * time.Second
+	writeQueryTimeout = 5 * time.Second
+)
+
+// version diisi saat build: go build -ldflags "-X main.version=v1.2.3"
+var version = "dev"
+
 func main() {
-	// Muat variabel lingkungan dari .env SEBELUM hal lain dilakukan
-	config.LoadEnv()
+	os.Exit(runCLI(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// runServe menjalankan HTTP API (subcommand "serve", juga default tanpa argumen)
+func runServe(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := fs.String("addr", cfg.Server.Addr, "alamat listen HTTP (menimpa SERVER_ADDR)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	// Tracing dipasang sebelum database agar plugin GORM memakai TracerProvider yang benar
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing, version)
+	if err != nil {
+		return err
+	}
 
 	// 1. KONEKSI DATABASE
 	// Inisialisasi koneksi ke database (mengatur variabel global config.DB)
-	config.ConnectDatabase()
-	db := config.DB // Gunakan variabel global yang sudah diinisialisasi
-	if db == nil {
-		log.Fatal("Gagal terhubung ke database. Cek konfigurasi.")
+	if err := config.ConnectDatabase(cfg.Database); err != nil {
+		return err
 	}
+	db := config.DB // Gunakan variabel global yang sudah diinisialisasi
 	
-	r := gin.Default()
+	// Metrik Prometheus dan span tracing untuk setiap query GORM
+	if err := db.Use(metrics.GormPlugin{}); err != nil {
+		return err
+	}
+	if err := db.Use(tracing.GormPlugin{}); err != nil {
+		return err
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		if err := metrics.RegisterDBStats(sqlDB, cfg.Database.Name); err != nil {
+			return err
+		}
+	}
+
+	// Worker latar belakang dihentikan terbalik dari urutan start saat shutdown
+	var workers workerGroup
+
+	// Logger teks dan mode debug gin hanya dipakai saat LOG_LEVEL=debug; access log
+	// ditulis oleh middleware.AccessLog sebagai log terstruktur dengan request ID
+	if cfg.Log.Level > slog.LevelDebug {
+		gin.SetMode(gin.ReleaseMode)
+	}
+	r := gin.New()
+	// X-Forwarded-For hanya dipercaya dari proxy yang dikenal; tanpa ini IP klien bisa
+	// dipalsukan untuk melewati rate limit per IP
+	if err := r.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		return err
+	}
+	r.Use(
+		// Span per route; traceparent dari klien/proxy dilanjutkan. Probe dan scrape tidak di-trace.
+		otelgin.Middleware(cfg.Tracing.ServiceName, otelgin.WithFilter(func(req *http.Request) bool {
+			switch req.URL.Path {
+			case "/healthz", "/readyz", "/metrics":
+				return false
+			}
+			return true
+		})),
+		middleware.RequestID(), middleware.AccessLog(), middleware.Recovery(), middleware.Metrics(),
+	)
+
+	// Origin frontend yang diizinkan (dipakai oleh CORS dan WebSocket)
+	allowedOrigins := cfg.Server.AllowedOrigins
 
 	// --- KONFIGURASI CORS ---
-	// Konfigurasi ini penting untuk mengizinkan frontend React (http://localhost:5173) mengakses API
+	// Konfigurasi ini penting untuk mengizinkan frontend React (CORS_ALLOWED_ORIGINS) mengakses API
 	r.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"http://localhost:5173"},
+		AllowOrigins:     allowedOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}, // Tambahkan OPTIONS untuk CORS Preflight
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"}, // Tambahkan Authorization untuk JWT
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", middleware.APIKeyHeader, middleware.RequestIDHeader, "traceparent", "tracestate"}, // Tambahkan Authorization untuk JWT
+		ExposeHeaders: []string{
+			middleware.RequestIDHeader, "Retry-After",
+			middleware.RateLimitLimitHeader, middleware.RateLimitRemainingHeader, middleware.RateLimitResetHeader,
+		},
 		AllowCredentials: true,
 		MaxAge:           3600,
 	}))
 
 	// --- Inisialisasi Dependencies (Repository, Service, Handler) ---
 
+	// Event bus in-process untuk feed perubahan produk (menyimpan 1000 event terakhir untuk resume)
+	eventBus := events.NewBus(1000)
+
+	// Hub WebSocket untuk kehadiran editor dan notifikasi perubahan per produk
+	presenceHub := presence.NewHub(eventBus)
+	workers.Go("presence", presenceHub.Run)
+
 	// Inisialisasi Repository dengan koneksi DB
 	productRepo := repositories.NewProductRepository(db)
+	auditRepo := repositories.NewAuditRepository(db)
+	webhookRepo := repositories.NewWebhookRepository(db)
+	userRepo := repositories.NewUserRepository(db)
 	
 	// Inisialisasi Service dengan Repository
+	auditService := services.NewAuditService(auditRepo)
 	productService := services.NewProductService(productRepo)
+	if cfg.DemoMode {
+		// Mode demo: produk disimpan di memori (hilang saat restart) dan event
+		// dipublikasikan langsung ke bus karena tidak melewati outbox
+		slog.Warn("DEMO_MODE aktif: produk disimpan di memori")
+		productService.Repo = repositories.NewMemoryProductRepository()
+		productService.Audit = auditService
+		productService.Events = eventBus
+	} else {
+		productService.Tx = repositories.NewTxManager(db) // Produk + audit ditulis dalam satu transaksi
+	}
+	webhookService := services.NewWebhookService(webhookRepo)
+	authService := services.NewAuthService(userRepo)
+	authService.Audit = auditService
+	mfaService := services.NewMFAService(userRepo, repositories.NewRecoveryCodeRepository(db), cfg.MFA.Issuer)
+	mfaService.Audit = auditService
+	mfaService.Tx = repositories.NewTxManager(db) // Status TOTP + kode pemulihan ditulis dalam satu transaksi
+	authService.MFA = mfaService
+	apiKeyService := services.NewAPIKeyService(repositories.NewAPIKeyRepository(db), userRepo)
+	apiKeyService.Audit = auditService
+	sessionService := services.NewSessionService(repositories.NewSessionRepository(db))
+	sessionService.Audit = auditService
+	authService.Sessions = sessionService
+	accountService := services.NewAccountService(userRepo, cfg.Account.DeletionGracePeriod)
+	accountService.Sessions = sessionService
+	accountService.Audit = auditService
+	var oidcService *services.OIDCService
+	if cfg.OIDC.Enabled() {
+		oidcService = services.NewOIDCService(authService,
+			cfg.OIDC.IssuerURL, cfg.OIDC.ClientID, cfg.OIDC.ClientSecret, cfg.OIDC.RedirectURL)
+		oidcService.AllowedDomains = cfg.OIDC.AllowedDomains
+		oidcService.AutoProvision = cfg.OIDC.AutoProvision
+	}
+
+	// Rate limit endpoint autentikasi. Store in-memory berlaku per instance; untuk beberapa
+	// replika ganti dengan implementasi ratelimit.Store bersama (mis. Redis).
+	loginLimit := []gin.HandlerFunc{}
+	registerLimit := []gin.HandlerFunc{}
+	mfaLimit := []gin.HandlerFunc{}
+	accountLimit := []gin.HandlerFunc{}
+	if cfg.RateLimit.Enabled {
+		limitStore := ratelimit.NewMemoryStore()
+		authService.Limiter = ratelimit.NewLoginGuard(limitStore,
+			cfg.RateLimit.LoginPerAccount, cfg.RateLimit.LockoutThreshold, cfg.RateLimit.LockoutDuration)
+		loginLimit = append(loginLimit, middleware.RateLimit(limitStore, "login_ip", cfg.RateLimit.LoginPerIP))
+		registerLimit = append(registerLimit, middleware.RateLimit(limitStore, "register_ip", cfg.RateLimit.RegisterPerIP))
+		// Endpoint yang menerima kode TOTP/pemulihan dari pengguna yang sudah login
+		mfaLimit = append(mfaLimit, middleware.RateLimit(limitStore, "mfa_ip", cfg.RateLimit.LoginPerIP))
+		// Endpoint /me yang memverifikasi password saat ini
+		accountLimit = append(accountLimit, middleware.RateLimit(limitStore, "account_ip", cfg.RateLimit.LoginPerIP))
+	}
+
+	// Worker webhook: mengirim antrean persisten dengan retry. Seperti relay outbox, hanya
+	// pemegang lock yang memproses antrean agar pengiriman tidak terkirim dari setiap replika.
+	webhookService.Leader = database.NewLeaderLock(db, "webhook_worker")
+	workers.Go("webhook", func(ctx context.Context) {
+		webhookService.Run(ctx, 5*time.Second)
+	})
+
+	// Relay outbox: event produk ditulis ke tabel outbox dalam transaksi yang sama dengan
+	// perubahan produk, lalu diteruskan ke event bus (SSE/WebSocket) dan antrean webhook
+	outboxRelay := outbox.NewRelay(
+		repositories.NewOutboxRepository(db),
+		outbox.MultiPublisher{
+			&outbox.WebhookPublisher{Webhooks: webhookService},
+			&outbox.BusPublisher{Bus: eventBus}, // Terakhir: hanya menerima pesan yang sudah masuk antrean webhook
+		},
+		time.Second,
+	)
+	// Setiap replika menjalankan relay, tetapi hanya pemegang lock yang memproses outbox
+	outboxRelay.Leader = database.NewLeaderLock(db, "outbox_relay")
+	workers.Go("outbox-relay", outboxRelay.Run)
+
+	// Penghapusan permanen akun yang masa tenggangnya sudah lewat
+	workers.Go("account-purge", func(ctx context.Context) {
+		accountService.Run(ctx, time.Hour)
+	})
 
 	// Inisialisasi Handler dengan Service
 	productHandler := handlers.NewProductHandler(productService)
-	authHandler := handlers.AuthHandler{DB: db} 
+	authHandler := handlers.NewAuthHandler(authService)
+	mfaHandler := handlers.NewMFAHandler(mfaService)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService)
+	sessionHandler := handlers.NewSessionHandler(sessionService)
+	accountHandler := handlers.NewAccountHandler(accountService)
+	auditHandler := handlers.NewAuditHandler(auditService)
+	productEventHandler := handlers.NewProductEventHandler(eventBus)
+	presenceHandler := handlers.NewPresenceHandler(presenceHub, allowedOrigins)
+	presenceHandler.Sessions = sessionService
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
+
+	// Pemeriksaan readiness: koneksi database dan migrasi yang belum diterapkan
+	healthService := services.NewHealthService(version,
+		services.HealthCheck{Name: "database", Check: config.Ping},
+		services.HealthCheck{Name: "migrations", Check: config.CheckMigrations},
+	)
+	if sqlDB, err := db.DB(); err == nil {
+		healthService.PoolStats = sqlDB.Stats
+	}
+	healthHandler := handlers.NewHealthHandler(healthService)
+
+	// Probe untuk load balancer/orchestrator (tanpa autentikasi, di luar /api/v1)
+	r.GET("/healthz", healthHandler.LivenessHandler)
+	r.GET("/readyz", healthHandler.ReadinessHandler)
+	r.GET("/metrics", gin.WrapH(metrics.Handler())) // Format Prometheus untuk scraping
+	r.GET("/.well-known/jwks.json", handlers.JWKSHandler) // Kunci publik untuk memverifikasi JWT
 	
 	// Membuat grup route utama /api/v1
 	api := r.Group("/api/v1")
@@ -58,26 +254,150 @@ func main() {
 	auth := api.Group("/auth")
 	{
 		// Endpoint Pendaftaran (Register)
-		auth.POST("/register", authHandler.RegisterUser) 
+		auth.POST("/register", append(registerLimit, authHandler.RegisterUser)...)
 		
-		// Endpoint Login
-		auth.POST("/login", authHandler.LoginUser)
+		// Endpoint Login (batas per IP di middleware, per akun dan penguncian di AuthService)
+		auth.POST("/login", append(loginLimit, authHandler.LoginUser)...)
+
+		// Langkah kedua login untuk akun dengan TOTP aktif (mfa_token + kode)
+		auth.POST("/login/mfa", append(loginLimit, authHandler.LoginMFA)...)
+
+		// Endpoint Aktivasi Akun (token dari email)
+		auth.GET("/activate", authHandler.ActivateUser)
+
+		// Konfirmasi email baru (token dari email yang dikirim oleh POST /me/email)
+		auth.GET("/confirm-email", accountHandler.ConfirmEmailHandler)
+
+		// Login lewat penyedia OpenID Connect (hanya jika OIDC_ISSUER_URL diisi)
+		if oidcService != nil {
+			oidcHandler := handlers.NewOIDCHandler(oidcService)
+			auth.GET("/oidc/login", oidcHandler.LoginHandler)
+			auth.GET("/oidc/callback", append(loginLimit, oidcHandler.CallbackHandler)...)
+		}
+	}
+	
+	// Pengelolaan TOTP oleh pengguna yang sudah login
+	mfa := auth.Group("/mfa")
+	mfa.Use(middleware.AuthMiddlewareWith(sessionService, nil))
+	{
+		mfa.GET("", mfaHandler.StatusHandler)
+		mfa.POST("/totp/setup", mfaHandler.SetupTOTPHandler)
+		mfa.POST("/totp/enable", append(mfaLimit, mfaHandler.EnableTOTPHandler)...)
+		mfa.POST("/totp/disable", append(mfaLimit, mfaHandler.DisableTOTPHandler)...)
+		mfa.POST("/recovery-codes", append(mfaLimit, mfaHandler.RegenerateRecoveryCodesHandler)...)
+	}
+
+	// Pengelolaan API key pribadi. Hanya dengan JWT: API key tidak bisa membuat kunci baru.
+	apiKeys := auth.Group("/api-keys")
+	apiKeys.Use(middleware.AuthMiddlewareWith(sessionService, nil))
+	{
+		apiKeys.POST("", apiKeyHandler.CreateAPIKeyHandler)
+		apiKeys.GET("", apiKeyHandler.ListAPIKeysHandler)
+		apiKeys.DELETE("/:id", apiKeyHandler.RevokeAPIKeyHandler)
+	}
+
+	// Sesi login pengguna (perangkat yang sedang login); mencabut sesi langsung membatalkan tokennya
+	sessions := auth.Group("/sessions")
+	sessions.Use(middleware.AuthMiddlewareWith(sessionService, nil))
+	{
+		sessions.GET("", sessionHandler.ListSessionsHandler)
+		sessions.DELETE("/:id", sessionHandler.RevokeSessionHandler)
+	}
+
+	// Profil dan pengelolaan akun oleh pemiliknya sendiri
+	me := api.Group("/me")
+	me.Use(middleware.AuthMiddlewareWith(sessionService, nil))
+	{
+		me.GET("", accountHandler.GetProfileHandler)
+		me.PATCH("", accountHandler.UpdateProfileHandler)
+		me.PUT("/password", append(accountLimit, accountHandler.ChangePasswordHandler)...)
+		me.POST("/email", append(accountLimit, accountHandler.ChangeEmailHandler)...)
+		me.DELETE("", append(accountLimit, accountHandler.DeleteAccountHandler)...)
+		me.POST("/restore", accountHandler.RestoreAccountHandler)
 	}
 	
 	// ===================================
 	// B. ROUTE TERLINDUNGI (CRUD PRODUK)
 	// ===================================
 	products := api.Group("/products")
-	products.Use(middleware.AuthMiddleware()) // Melindungi semua route di dalam grup /products
+	products.Use(middleware.AuthMiddlewareWith(sessionService, apiKeyService)) // Melindungi semua route di dalam grup /products (JWT atau X-API-Key)
 	{
-		products.POST("", productHandler.CreateProductHandler)
-		products.GET("", productHandler.ReadAllProductsHandler)
-		products.GET("/:id", productHandler.ReadProductByIDHandler)
-		products.PUT("/:id", productHandler.UpdateProductHandler)
-		products.DELETE("/:id", productHandler.DeleteProductHandler)
+		// Scope hanya membatasi API key; pengguna yang login dengan JWT punya akses penuh
+		canRead := middleware.RequireScope(models.ScopeProductsRead)
+		canWrite := middleware.RequireScope(models.ScopeProductsWrite)
+
+		// Deadline query per route: klien terputus -> 499, deadline habis -> 504
+		products.POST("", canWrite, middleware.Timeout(writeQueryTimeout), productHandler.CreateProductHandler)
+		products.GET("", canRead, middleware.Timeout(listQueryTimeout), productHandler.ReadAllProductsHandler)
+		products.GET("/:id", canRead, middleware.Timeout(readQueryTimeout), productHandler.ReadProductByIDHandler)
+		products.PUT("/:id", canWrite, middleware.Timeout(writeQueryTimeout), productHandler.UpdateProductHandler)
+		products.DELETE("/:id", canWrite, middleware.Timeout(writeQueryTimeout), productHandler.DeleteProductHandler)
 	}
 
+	// Server-Sent Events perubahan produk (tanpa deadline). EventSource di browser tidak bisa
+	// mengirim header Authorization, jadi access token juga diterima via ?token= seperti WebSocket.
+	api.GET("/products/events",
+		middleware.TokenFromQuery(),
+		middleware.AuthMiddlewareWith(sessionService, apiKeyService),
+		middleware.RequireScope(models.ScopeProductsRead),
+		productEventHandler.StreamProductEventsHandler,
+	)
+
+	// WebSocket kehadiran produk. Token JWT divalidasi di handler (via ?token=)
+	// karena browser tidak bisa mengirim header Authorization saat handshake.
+	api.GET("/ws/products", presenceHandler.ServeWebSocketHandler)
+
+	// ===================================
+	// C. ROUTE ADMIN (AUDIT LOG & WEBHOOK)
+	// ===================================
+	admin := api.Group("/admin")
+	admin.Use(middleware.AuthMiddlewareWith(sessionService, nil), middleware.RequireRole(models.RoleAdmin))
+	{
+		admin.GET("/audit-logs", auditHandler.ListAuditLogsHandler)
+		admin.GET("/status", healthHandler.StatusHandler)
+
+		admin.POST("/webhooks", webhookHandler.CreateWebhookHandler)
+		admin.GET("/webhooks", webhookHandler.ListWebhooksHandler)
+		admin.DELETE("/webhooks/:id", webhookHandler.DeleteWebhookHandler)
+		admin.GET("/webhooks/:id/deliveries", webhookHandler.ListDeliveriesHandler)
+		admin.POST("/webhook-deliveries/:id/redeliver", webhookHandler.RedeliverHandler)
+	}
 
-	log.Println("Server berjalan di http://localhost:8080")
-	r.Run(":8080")
+	srv := newHTTPServer(*addr, r, cfg.Server)
+	// Stream SSE tidak pernah selesai sendiri; akhiri saat Shutdown dimulai agar tidak menahan drain
+	srv.RegisterOnShutdown(productEventHandler.Close)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ln, err := net.Listen("tcp", srv.Addr)
+	if err != nil {
+		return err
+	}
+	slog.Info("Server berjalan", "addr", ln.Addr().String(), "version", version)
+	serveErr := runHTTPServer(ctx, srv, ln, shutdownOptions{
+		OnSignal: func() {
+			stop()                          // Sinyal kedua langsung menghentikan proses
+			healthService.SetShuttingDown() // /readyz menjadi 503 selama jeda dan drain
+		},
+		Delay:   cfg.Server.ShutdownDelay,
+		Timeout: cfg.Server.ShutdownTimeout,
+	})
+
+	// Urutan berhenti: HTTP (sudah di-drain) -> relay outbox -> worker webhook -> hub -> trace -> pool database
+	stopCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+	if err := workers.Stop(stopCtx); err != nil {
+		slog.Error("Shutdown worker gagal", "error", err)
+	}
+	if err := shutdownTracing(stopCtx); err != nil {
+		slog.Error("Gagal mengirim sisa trace", "error", err)
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		if err := sqlDB.Close(); err != nil {
+			slog.Error("Gagal menutup pool database", "error", err)
+		}
+	}
+	slog.Info("Server berhenti")
+	return serveErr
 }
\ No newline at end of file
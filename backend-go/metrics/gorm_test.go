@@ -0,0 +1,53 @@
+package metrics_test
+
+import (
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"fullstack-crud-project-01/backend-go/metrics"
+)
+
+// sampleCount mengembalikan jumlah observasi histogram durasi query untuk label tertentu
+func sampleCount(t *testing.T, operation, table string) uint64 {
+	var m dto.Metric
+	require.NoError(t, metrics.DBQueryDuration.WithLabelValues(operation, table).(prometheus.Metric).Write(&m))
+	return m.GetHistogram().GetSampleCount()
+}
+
+type widget struct {
+	ID   uint
+	Name string
+}
+
+func TestGormPlugin_RecordsQueriesAndErrors(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(metrics.GormPlugin{}))
+	require.NoError(t, db.AutoMigrate(&widget{}))
+
+	require.NoError(t, db.Create(&widget{Name: "a"}).Error)
+	var found widget
+	require.NoError(t, db.First(&found).Error)
+	assert.Error(t, db.First(&found, 999).Error, "record not found")
+	assert.Error(t, db.Table("tidak_ada").Create(map[string]interface{}{"name": "x"}).Error)
+
+	assert.Equal(t, uint64(1), sampleCount(t, "create", "widgets"))
+	assert.Equal(t, uint64(2), sampleCount(t, "query", "widgets"))
+	assert.Zero(t, testutil.ToFloat64(metrics.DBQueryErrors.WithLabelValues("query", "widgets")), "Record not found bukan error database")
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.DBQueryErrors.WithLabelValues("create", "tidak_ada")))
+}
+
+func TestHandler_ExposesRegistry(t *testing.T) {
+	metrics.LoginAttempts.WithLabelValues(metrics.LoginSuccess).Inc()
+
+	count, err := testutil.GatherAndCount(metrics.Registry, "backend_auth_login_attempts_total")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
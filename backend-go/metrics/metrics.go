@@ -0,0 +1,117 @@
+// Package metrics berisi metrik Prometheus aplikasi (HTTP, database, autentikasi)
+// dan handler /metrics untuk scraping.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace adalah prefix semua metrik aplikasi
+const namespace = "backend"
+
+// Registry menampung semua metrik aplikasi beserta metrik runtime Go dan proses.
+// Registry sendiri (bukan DefaultRegisterer) agar tes bisa membaca nilai tanpa efek global lain.
+var Registry = prometheus.NewRegistry()
+
+var (
+	// HTTPRequests menghitung request per method, template route dan status
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Jumlah request HTTP per method, route dan status.",
+	}, []string{"method", "route", "status"})
+
+	// HTTPDuration mengukur latensi request per method, template route dan status
+	HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latensi request HTTP dalam detik.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// DBQueryDuration mengukur durasi query GORM per operasi dan tabel
+	DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Durasi query database dalam detik.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "table"})
+
+	// DBQueryErrors menghitung query GORM yang gagal (record not found tidak dihitung)
+	DBQueryErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "db_query_errors_total",
+		Help:      "Jumlah query database yang gagal.",
+	}, []string{"operation", "table"})
+
+	// LoginAttempts menghitung percobaan login per hasil
+	LoginAttempts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "auth_login_attempts_total",
+		Help:      "Jumlah percobaan login per hasil (success, mfa_required, invalid_credentials, invalid_mfa_code, not_active, throttled, oidc_rejected, error).",
+	}, []string{"result"})
+
+	// TokenValidationFailures menghitung token yang ditolak AuthMiddleware per alasan
+	TokenValidationFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "auth_token_validation_failures_total",
+		Help:      "Jumlah token yang ditolak per alasan (missing, malformed, invalid, api_key_invalid, session_revoked).",
+	}, []string{"reason"})
+
+	// RateLimitRejections menghitung request yang ditolak middleware RateLimit per limiter
+	RateLimitRejections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limit_rejections_total",
+		Help:      "Jumlah request yang ditolak karena melewati batas laju, per limiter.",
+	}, []string{"limiter"})
+)
+
+// Hasil login untuk label LoginAttempts
+const (
+	LoginSuccess            = "success"
+	LoginInvalidCredentials = "invalid_credentials"
+	LoginMFARequired        = "mfa_required"
+	LoginInvalidMFACode     = "invalid_mfa_code"
+	LoginNotActive          = "not_active"
+	LoginThrottled          = "throttled"
+	LoginOIDCRejected       = "oidc_rejected"
+	LoginError              = "error"
+)
+
+// Alasan penolakan token untuk label TokenValidationFailures
+const (
+	TokenMissing        = "missing"
+	TokenMalformed      = "malformed"
+	TokenInvalid        = "invalid"
+	TokenAPIKeyInvalid  = "api_key_invalid"
+	TokenSessionRevoked = "session_revoked"
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPDuration,
+		DBQueryDuration,
+		DBQueryErrors,
+		LoginAttempts,
+		TokenValidationFailures,
+		RateLimitRejections,
+	)
+}
+
+// RegisterDBStats mendaftarkan gauge pool database (koneksi terbuka, in use, idle, wait)
+func RegisterDBStats(db *sql.DB, dbName string) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, dbName))
+}
+
+// Handler mengembalikan handler HTTP untuk endpoint /metrics
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
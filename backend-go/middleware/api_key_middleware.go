@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"fullstack-crud-project-01/backend-go/metrics"
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHeader adalah header untuk autentikasi dengan API key sebagai pengganti JWT
+const APIKeyHeader = "X-API-Key"
+
+// apiKeyKey adalah kunci Gin Context untuk API key yang dipakai request
+const apiKeyKey = "current_api_key"
+
+// APIKeyAuthenticator memverifikasi API key ("port"), mis. services.APIKeyService.
+// Kunci yang tidak valid harus dikembalikan sebagai models.ErrAPIKeyInvalid.
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, key string) (*models.APIKey, *models.User, error)
+}
+
+// authenticateAPIKey memverifikasi API key dan menyuntikkan claims pemiliknya, sama seperti
+// JWT, sehingga handler dan audit log tidak perlu membedakan keduanya
+func authenticateAPIKey(c *gin.Context, keys APIKeyAuthenticator, raw string) {
+	key, user, err := keys.Authenticate(c.Request.Context(), raw)
+	if errors.Is(err, models.ErrAPIKeyInvalid) {
+		metrics.TokenValidationFailures.WithLabelValues(metrics.TokenAPIKeyInvalid).Inc()
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "API key tidak valid atau kedaluwarsa."})
+		c.Abort()
+		return
+	}
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Gagal memverifikasi API key", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memverifikasi API key."})
+		c.Abort()
+		return
+	}
+
+	c.Set(UserKey, &utils.CustomClaims{UserID: user.ID, Email: user.Email, Role: user.Role})
+	c.Set(apiKeyKey, key)
+	c.Next()
+}
+
+// CurrentAPIKey mengambil API key yang dipakai request; false jika request memakai JWT.
+func CurrentAPIKey(c *gin.Context) (*models.APIKey, bool) {
+	value, exists := c.Get(apiKeyKey)
+	if !exists {
+		return nil, false
+	}
+	key, ok := value.(*models.APIKey)
+	return key, ok
+}
+
+// RequireScope membatasi request dengan API key pada kunci yang memiliki scope tertentu.
+// Request dengan JWT (login interaktif) selalu lolos. Harus dipasang SETELAH AuthMiddlewareWithAPIKeys.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if key, ok := CurrentAPIKey(c); ok && !key.HasScope(scope) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "API key tidak memiliki scope '" + scope + "'."})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,90 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"fullstack-crud-project-01/backend-go/metrics"
+	"fullstack-crud-project-01/backend-go/middleware"
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubAPIKeys menerima tepat satu kunci berscope products:read
+type stubAPIKeys struct{}
+
+func (stubAPIKeys) Authenticate(ctx context.Context, raw string) (*models.APIKey, *models.User, error) {
+	switch raw {
+	case "bk_valid":
+		return &models.APIKey{ID: 7, Scopes: []string{models.ScopeProductsRead}},
+			&models.User{ID: 42, Email: "bot@test.com", Role: models.RoleUser}, nil
+	case "bk_broken":
+		return nil, nil, errors.New("database down")
+	default:
+		return nil, nil, models.ErrAPIKeyInvalid
+	}
+}
+
+func TestAuthMiddlewareWithAPIKeys(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("JWT_SECRET_KEY", "kunci-rahasia-untuk-testing-middleware")
+	r := gin.New()
+	products := r.Group("/products", middleware.AuthMiddlewareWithAPIKeys(stubAPIKeys{}))
+	products.GET("", middleware.RequireScope(models.ScopeProductsRead), func(c *gin.Context) {
+		claims, _ := middleware.CurrentClaims(c)
+		_, viaKey := middleware.CurrentAPIKey(c)
+		c.JSON(http.StatusOK, gin.H{"user_id": claims.UserID, "api_key": viaKey})
+	})
+	products.POST("", middleware.RequireScope(models.ScopeProductsWrite), func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+
+	serve := func(method string, header, value string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, "/products", nil)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	rejected := testutil.ToFloat64(metrics.TokenValidationFailures.WithLabelValues(metrics.TokenAPIKeyInvalid))
+
+	w := serve("GET", middleware.APIKeyHeader, "bk_valid")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"user_id": 42, "api_key": true}`, w.Body.String())
+
+	w = serve("POST", middleware.APIKeyHeader, "bk_valid")
+	assert.Equal(t, http.StatusForbidden, w.Code, "Kunci tanpa products:write")
+	assert.Contains(t, w.Body.String(), models.ScopeProductsWrite)
+
+	w = serve("GET", middleware.APIKeyHeader, "bk_wrong")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, rejected+1, testutil.ToFloat64(metrics.TokenValidationFailures.WithLabelValues(metrics.TokenAPIKeyInvalid)))
+
+	assert.Equal(t, http.StatusInternalServerError, serve("GET", middleware.APIKeyHeader, "bk_broken").Code)
+
+	// JWT tetap diterima dan tidak dibatasi scope
+	token, err := utils.GenerateSessionToken(1, "admin@test.com", models.RoleAdmin, "sesi-test")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, serve("POST", "Authorization", "Bearer "+token).Code)
+	assert.Equal(t, http.StatusUnauthorized, serve("GET", "", "").Code)
+}
+
+func TestAuthMiddleware_IgnoresAPIKeyHeader(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/protected", middleware.AuthMiddleware(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req, _ := http.NewRequest("GET", "/protected", nil)
+	req.Header.Set(middleware.APIKeyHeader, "bk_valid")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code, "Route khusus JWT tidak menerima API key")
+}
@@ -1,22 +1,51 @@
 package middleware
 
 import (
+	"context"
+	"errors"
+	"log/slog"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"fullstack-crud-project-01/backend-go/metrics"
+	"fullstack-crud-project-01/backend-go/models"
 	"fullstack-crud-project-01/backend-go/utils" // Import utils/jwt_utils.go
 )
 
 // UserKey adalah kunci yang digunakan untuk menyimpan data pengguna di Gin Context
 const UserKey = "current_user"
 
+// SessionValidator memeriksa bahwa sesi login milik token belum dicabut ("port"),
+// mis. services.SessionService. Sesi yang dicabut harus dikembalikan sebagai models.ErrSessionRevoked.
+type SessionValidator interface {
+	Validate(ctx context.Context, claims *utils.CustomClaims) error
+}
+
 // AuthMiddleware memverifikasi JWT dari header Authorization.
 func AuthMiddleware() gin.HandlerFunc {
+	return AuthMiddlewareWith(nil, nil)
+}
+
+// AuthMiddlewareWithAPIKeys seperti AuthMiddleware, tetapi juga menerima API key di header
+// X-API-Key (diperiksa lebih dulu jika ada). keys nil berarti hanya JWT yang diterima.
+func AuthMiddlewareWithAPIKeys(keys APIKeyAuthenticator) gin.HandlerFunc {
+	return AuthMiddlewareWith(nil, keys)
+}
+
+// AuthMiddlewareWith memverifikasi JWT beserta sesinya (sessions nil berarti sesi tidak
+// diperiksa) dan, jika keys tidak nil, juga menerima API key di header X-API-Key.
+func AuthMiddlewareWith(sessions SessionValidator, keys APIKeyAuthenticator) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if raw := c.GetHeader(APIKeyHeader); keys != nil && raw != "" {
+			authenticateAPIKey(c, keys, raw)
+			return
+		}
+
 		// 1. Ekstrak Token dari Header
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
+			metrics.TokenValidationFailures.WithLabelValues(metrics.TokenMissing).Inc()
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Header otorisasi diperlukan."})
 			c.Abort() // Menghentikan eksekusi handler berikutnya
 			return
@@ -25,6 +54,7 @@ func AuthMiddleware() gin.HandlerFunc {
 		// Header biasanya berbentuk: "Bearer <token>"
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
+			metrics.TokenValidationFailures.WithLabelValues(metrics.TokenMalformed).Inc()
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Format token tidak valid. Gunakan 'Bearer <token>'."})
 			c.Abort()
 			return
@@ -36,16 +66,56 @@ func AuthMiddleware() gin.HandlerFunc {
 		claims, err := utils.ValidateToken(tokenString)
 		if err != nil {
 			// Token tidak valid (expired, signature salah, dll.)
+			metrics.TokenValidationFailures.WithLabelValues(metrics.TokenInvalid).Inc()
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token tidak valid atau kedaluwarsa.", "details": err.Error()})
 			c.Abort()
 			return
 		}
 
-		// 3. Suntikkan Data Pengguna ke Konteks
+		// 3. Tolak token milik sesi yang sudah dicabut (mis. logout dari perangkat lain)
+		if sessions != nil {
+			if err := sessions.Validate(c.Request.Context(), claims); err != nil {
+				if errors.Is(err, models.ErrSessionRevoked) {
+					metrics.TokenValidationFailures.WithLabelValues(metrics.TokenSessionRevoked).Inc()
+					c.JSON(http.StatusUnauthorized, gin.H{"error": "Sesi telah berakhir. Silakan login ulang."})
+				} else {
+					slog.ErrorContext(c.Request.Context(), "Gagal memeriksa sesi", "error", err)
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memeriksa sesi."})
+				}
+				c.Abort()
+				return
+			}
+		}
+
+		// 4. Suntikkan Data Pengguna ke Konteks
 		// Data ini (UserID dan Email) sekarang bisa diakses oleh handler produk
 		c.Set(UserKey, claims) 
 		
 		// Lanjutkan ke handler berikutnya (misalnya, CreateProductHandler)
 		c.Next()
 	}
-}
\ No newline at end of file
+}
+
+// TokenQueryParam adalah nama query string pembawa access token untuk klien yang tidak
+// bisa mengirim header Authorization (EventSource dan WebSocket di browser)
+const TokenQueryParam = "token"
+
+// TokenFromQuery memindahkan token dari query ?token= ke header Authorization agar bisa
+// diperiksa AuthMiddlewareWith. Pasang hanya pada route stream; header yang sudah ada
+// (Authorization atau X-API-Key) selalu didahulukan. Token dihapus dari URL request agar
+// tidak ikut tercatat oleh middleware atau handler sesudahnya.
+func TokenFromQuery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := c.Request.URL.Query()
+		token := query.Get(TokenQueryParam)
+		if token != "" && c.GetHeader("Authorization") == "" && c.GetHeader(APIKeyHeader) == "" {
+			c.Request.Header.Set("Authorization", "Bearer "+token)
+		}
+		if query.Has(TokenQueryParam) {
+			query.Del(TokenQueryParam)
+			c.Request.URL.RawQuery = query.Encode()
+			c.Request.RequestURI = c.Request.URL.RequestURI()
+		}
+		c.Next()
+	}
+}
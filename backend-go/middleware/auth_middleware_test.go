@@ -76,7 +76,7 @@ func TestAuthMiddleware(t *testing.T) {
 		// Buat token yang valid
 		userID := uint(99)
 		email := "test.middleware@example.com"
-		validToken, err := utils.GenerateToken(userID, email)
+		validToken, err := utils.GenerateSessionToken(userID, email, "user", "sesi-test")
 		assert.NoError(t, err)
 
 		req, _ := http.NewRequest("GET", "/protected", nil)
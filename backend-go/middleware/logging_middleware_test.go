@@ -0,0 +1,69 @@
+package middleware_test
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"fullstack-crud-project-01/backend-go/metrics"
+	"fullstack-crud-project-01/backend-go/middleware"
+	"fullstack-crud-project-01/backend-go/utils"
+)
+
+func TestTokenFromQuery_NotRecorded(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("JWT_SECRET_KEY", "kunci-rahasia-untuk-testing-middleware")
+	token, err := utils.GenerateSessionToken(1, "stream@test.com", "user", "sesi-test")
+	require.NoError(t, err)
+
+	var logs bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&logs, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	spans := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(spans))
+
+	// Rantai middleware global sama seperti main.go
+	var handlerURL string
+	r := gin.New()
+	r.Use(otelgin.Middleware("test", otelgin.WithTracerProvider(provider)), middleware.AccessLog(), middleware.Metrics())
+	r.GET("/products/events", middleware.TokenFromQuery(), middleware.AuthMiddleware(), func(c *gin.Context) {
+		handlerURL = c.Request.URL.String()
+		assert.Equal(t, "3", c.Query("last_event_id"), "Query lain tetap tersedia")
+		c.Status(http.StatusOK)
+	})
+
+	req, _ := http.NewRequest("GET", "/products/events?token="+token+"&last_event_id=3", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	assert.NotContains(t, handlerURL, token)
+	assert.Contains(t, logs.String(), `"path":"/products/events"`)
+	assert.NotContains(t, logs.String(), token, "Token tidak boleh tercatat di access log")
+
+	require.Len(t, spans.GetSpans(), 1)
+	for _, attr := range spans.GetSpans()[0].Attributes {
+		assert.NotContains(t, attr.Value.Emit(), token, "Token tidak boleh menjadi atribut span %s", attr.Key)
+	}
+
+	families, err := metrics.Registry.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				assert.NotContains(t, label.GetValue(), token, "Token tidak boleh menjadi label metrik %s", family.GetName())
+			}
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"fullstack-crud-project-01/backend-go/metrics"
+)
+
+// unmatchedRoute adalah label route untuk request yang tidak cocok dengan route mana pun,
+// agar path acak (mis. scanner) tidak membuat label baru tanpa batas
+const unmatchedRoute = "unmatched"
+
+// Metrics mencatat jumlah dan latensi request per method, template route
+// (mis. /api/v1/products/:id, bukan path aslinya) dan status response.
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		status := strconv.Itoa(c.Writer.Status())
+		metrics.HTTPRequests.WithLabelValues(c.Request.Method, route, status).Inc()
+		metrics.HTTPDuration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,63 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"fullstack-crud-project-01/backend-go/metrics"
+	"fullstack-crud-project-01/backend-go/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics_LabelsByRouteTemplate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.Metrics())
+	r.GET("/items/:id", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	requests := func(method, route, status string) float64 {
+		return testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues(method, route, status))
+	}
+	beforeItem := requests("GET", "/items/:id", "204")
+	beforeUnmatched := requests("GET", "unmatched", "404")
+
+	for _, path := range []string{"/items/1", "/items/2", "/acak/abc"} {
+		req, _ := http.NewRequest("GET", path, nil)
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	assert.Equal(t, beforeItem+2, requests("GET", "/items/:id", "204"), "Path berbeda dengan template sama harus satu label")
+	assert.Equal(t, beforeUnmatched+1, requests("GET", "unmatched", "404"))
+}
+
+func TestAuthMiddleware_CountsTokenFailures(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/private", middleware.AuthMiddleware(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	tests := []struct {
+		header string
+		reason string
+	}{
+		{"", metrics.TokenMissing},
+		{"Token abc", metrics.TokenMalformed},
+		{"Bearer bukan-jwt", metrics.TokenInvalid},
+	}
+	for _, tt := range tests {
+		counter := metrics.TokenValidationFailures.WithLabelValues(tt.reason)
+		before := testutil.ToFloat64(counter)
+
+		req, _ := http.NewRequest("GET", "/private", nil)
+		if tt.header != "" {
+			req.Header.Set("Authorization", tt.header)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Equal(t, before+1, testutil.ToFloat64(counter), tt.reason)
+	}
+}
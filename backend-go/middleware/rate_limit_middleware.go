@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"fullstack-crud-project-01/backend-go/metrics"
+	"fullstack-crud-project-01/backend-go/ratelimit"
+)
+
+// Header batas laju (draft IETF RateLimit header fields)
+const (
+	RateLimitLimitHeader     = "RateLimit-Limit"
+	RateLimitRemainingHeader = "RateLimit-Remaining"
+	RateLimitResetHeader     = "RateLimit-Reset"
+)
+
+// RateLimit membatasi request per IP klien dengan token bucket bernama name (mis. "login_ip").
+// Setiap response membawa header RateLimit-*; request yang melewati batas ditolak dengan
+// 429 dan Retry-After. IP diambil dari c.ClientIP(), jadi X-Forwarded-For hanya dipercaya
+// dari proxy di SERVER_TRUSTED_PROXIES. Jika store gagal, request tetap dilayani.
+func RateLimit(store ratelimit.Store, name string, limit ratelimit.Limit) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		result, err := store.Take(c.Request.Context(), name+":ip:"+c.ClientIP(), limit)
+		if err != nil {
+			slog.WarnContext(c.Request.Context(), "Rate limit tidak dapat diperiksa", "limiter", name, "error", err)
+			c.Next()
+			return
+		}
+
+		c.Header(RateLimitLimitHeader, strconv.Itoa(result.Limit))
+		c.Header(RateLimitRemainingHeader, strconv.Itoa(result.Remaining))
+		c.Header(RateLimitResetHeader, strconv.Itoa(ratelimit.Seconds(result.Reset)))
+		if !result.Allowed {
+			metrics.RateLimitRejections.WithLabelValues(name).Inc()
+			c.Header("Retry-After", strconv.Itoa(ratelimit.Seconds(result.RetryAfter)))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "Terlalu banyak permintaan. Coba lagi nanti."})
+			return
+		}
+		c.Next()
+	}
+}
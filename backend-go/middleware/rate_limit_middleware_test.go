@@ -0,0 +1,51 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"fullstack-crud-project-01/backend-go/metrics"
+	"fullstack-crud-project-01/backend-go/middleware"
+	"fullstack-crud-project-01/backend-go/ratelimit"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	assert.NoError(t, r.SetTrustedProxies(nil))
+	store := ratelimit.NewMemoryStore()
+	r.POST("/login", middleware.RateLimit(store, "test_login", ratelimit.Limit{Burst: 2, Period: time.Minute}),
+		func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	login := func(remoteAddr string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", "/login", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-Forwarded-For", "203.0.113.9") // Tidak dipercaya: bukan dari proxy terdaftar
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	rejected := testutil.ToFloat64(metrics.RateLimitRejections.WithLabelValues("test_login"))
+
+	w := login("192.0.2.1:1234")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get(middleware.RateLimitLimitHeader))
+	assert.Equal(t, "1", w.Header().Get(middleware.RateLimitRemainingHeader))
+	assert.Equal(t, "30", w.Header().Get(middleware.RateLimitResetHeader))
+	assert.Empty(t, w.Header().Get("Retry-After"))
+
+	assert.Equal(t, http.StatusOK, login("192.0.2.1:1234").Code)
+
+	w = login("192.0.2.1:5678")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code, "Batas per IP, bukan per koneksi")
+	assert.Equal(t, "0", w.Header().Get(middleware.RateLimitRemainingHeader))
+	assert.Equal(t, "30", w.Header().Get("Retry-After"))
+	assert.Equal(t, rejected+1, testutil.ToFloat64(metrics.RateLimitRejections.WithLabelValues("test_login")))
+
+	assert.Equal(t, http.StatusOK, login("192.0.2.2:1234").Code, "IP lain punya bucket sendiri")
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"fullstack-crud-project-01/backend-go/logging"
+)
+
+// RequestIDHeader adalah header untuk korelasi request antar layanan
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDKey adalah kunci request ID di Gin Context
+const RequestIDKey = "request_id"
+
+// maxRequestIDLength membatasi ID dari klien agar tidak membengkakkan log
+const maxRequestIDLength = 128
+
+// RequestID memakai X-Request-ID dari klien/proxy jika valid, atau membuat UUID baru.
+// ID dikirim balik di response dan disimpan di context request sehingga ikut tercatat
+// di setiap baris log (termasuk SQL) yang memakai context tersebut.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.NewString()
+		}
+
+		c.Set(RequestIDKey, id)
+		c.Header(RequestIDHeader, id)
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), id))
+		c.Next()
+	}
+}
+
+// validRequestID hanya menerima karakter aman agar ID dari luar tidak bisa
+// menyisipkan baris palsu ke log
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,51 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"fullstack-crud-project-01/backend-go/logging"
+	"fullstack-crud-project-01/backend-go/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.RequestID())
+	r.GET("/", func(c *gin.Context) {
+		c.String(http.StatusOK, logging.RequestIDFrom(c.Request.Context()))
+	})
+
+	tests := []struct {
+		name     string
+		incoming string
+		keep     bool
+	}{
+		{"ID dari klien dipakai ulang", "abc-123_def.456", true},
+		{"Tanpa header dibuatkan UUID", "", false},
+		{"Karakter berbahaya ditolak", "abc\ninjected", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/", nil)
+			if tt.incoming != "" {
+				req.Header.Set(middleware.RequestIDHeader, tt.incoming)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			id := w.Header().Get(middleware.RequestIDHeader)
+			assert.Equal(t, id, w.Body.String(), "ID di response harus sama dengan ID di context")
+			if tt.keep {
+				assert.Equal(t, tt.incoming, id)
+			} else {
+				_, err := uuid.Parse(id)
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+
+	"fullstack-crud-project-01/backend-go/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole membatasi akses hanya untuk pengguna dengan salah satu peran yang diberikan.
+// Harus dipasang SETELAH AuthMiddleware karena membaca claims dari UserKey.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := CurrentClaims(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Autentikasi diperlukan."})
+			c.Abort()
+			return
+		}
+
+		for _, role := range roles {
+			if claims.Role == role {
+				c.Next()
+				return
+			}
+		}
+
+		c.JSON(http.StatusForbidden, gin.H{"error": "Anda tidak memiliki akses ke resource ini."})
+		c.Abort()
+	}
+}
+
+// CurrentClaims mengambil claims pengguna yang disuntikkan oleh AuthMiddleware.
+func CurrentClaims(c *gin.Context) (*utils.CustomClaims, bool) {
+	value, exists := c.Get(UserKey)
+	if !exists {
+		return nil, false
+	}
+	claims, ok := value.(*utils.CustomClaims)
+	return claims, ok
+}
@@ -0,0 +1,47 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"fullstack-crud-project-01/backend-go/middleware"
+	"fullstack-crud-project-01/backend-go/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireRole(t *testing.T) {
+	os.Setenv("JWT_SECRET_KEY", "kunci-rahasia-untuk-testing-role")
+	defer os.Unsetenv("JWT_SECRET_KEY")
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/admin", middleware.AuthMiddleware(), middleware.RequireRole("admin"), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "Halo admin"})
+	})
+
+	tests := []struct {
+		name         string
+		role         string
+		expectedCode int
+	}{
+		{name: "Success_Admin", role: "admin", expectedCode: http.StatusOK},
+		{name: "Failure_RegularUser", role: "user", expectedCode: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := utils.GenerateSessionToken(1, "role@example.com", tt.role, "sesi-test")
+			assert.NoError(t, err)
+
+			req, _ := http.NewRequest("GET", "/admin", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"fullstack-crud-project-01/backend-go/metrics"
+	"fullstack-crud-project-01/backend-go/middleware"
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubSessions hanya menerima sesi "aktif"; token tanpa jti tidak terikat sesi
+type stubSessions struct{}
+
+func (stubSessions) Validate(ctx context.Context, claims *utils.CustomClaims) error {
+	switch claims.ID {
+	case "", "aktif":
+		return nil
+	case "rusak":
+		return errors.New("database down")
+	default:
+		return models.ErrSessionRevoked
+	}
+}
+
+func TestAuthMiddlewareWith_Sessions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("JWT_SECRET_KEY", "kunci-rahasia-untuk-testing-middleware")
+	r := gin.New()
+	r.GET("/protected", middleware.AuthMiddlewareWith(stubSessions{}, nil), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	serve := func(sessionID string) *httptest.ResponseRecorder {
+		token, err := utils.GenerateSessionToken(1, "sesi@test.com", models.RoleUser, sessionID)
+		require.NoError(t, err)
+		req, _ := http.NewRequest("GET", "/protected", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	revoked := testutil.ToFloat64(metrics.TokenValidationFailures.WithLabelValues(metrics.TokenSessionRevoked))
+
+	assert.Equal(t, http.StatusOK, serve("aktif").Code)
+	assert.Equal(t, http.StatusOK, serve("").Code, "Token tanpa jti")
+
+	w := serve("dicabut")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "Sesi telah berakhir")
+	assert.Equal(t, revoked+1, testutil.ToFloat64(metrics.TokenValidationFailures.WithLabelValues(metrics.TokenSessionRevoked)))
+
+	assert.Equal(t, http.StatusInternalServerError, serve("rusak").Code)
+}
@@ -0,0 +1,41 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"fullstack-crud-project-01/backend-go/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	var (
+		deadline    time.Time
+		hasDeadline bool
+		ctxErr      error
+	)
+	r.GET("/slow", middleware.Timeout(20*time.Millisecond), func(c *gin.Context) {
+		ctx := c.Request.Context()
+		deadline, hasDeadline = ctx.Deadline()
+		<-ctx.Done() // Dibatalkan oleh middleware, bukan oleh handler ini
+		ctxErr = ctx.Err()
+		c.Status(http.StatusOK)
+	})
+
+	req, _ := http.NewRequest("GET", "/slow", nil)
+	w := httptest.NewRecorder()
+	start := time.Now()
+	r.ServeHTTP(w, req)
+
+	assert.True(t, hasDeadline, "Context request harus memiliki deadline")
+	assert.WithinDuration(t, start.Add(20*time.Millisecond), deadline, 10*time.Millisecond)
+	assert.ErrorIs(t, ctxErr, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Scope yang bisa diberikan ke API key
+const (
+	ScopeProductsRead  = "products:read"
+	ScopeProductsWrite = "products:write"
+)
+
+// APIKeyScopes adalah semua scope yang dikenali
+var APIKeyScopes = []string{ScopeProductsRead, ScopeProductsWrite}
+
+// APIKey adalah kunci milik pengguna untuk akses mesin-ke-mesin tanpa login interaktif.
+// Hanya hash SHA-256 yang disimpan; Prefix ditampilkan agar kunci mudah dikenali.
+type APIKey struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	UserID     uint       `gorm:"not null;index" json:"-"`
+	Name       string     `gorm:"size:100;not null" json:"name"`
+	Prefix     string     `gorm:"size:16;not null;uniqueIndex" json:"prefix"`
+	KeyHash    string     `gorm:"size:64;not null" json:"-"`
+	ScopeList  string     `gorm:"column:scopes;size:255;not null" json:"-"` // Disimpan sebagai daftar dipisah koma
+	Scopes     []string   `gorm:"-" json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"` // NULL berarti tidak kedaluwarsa
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// BeforeSave menyimpan Scopes ke kolom scopes
+func (k *APIKey) BeforeSave(tx *gorm.DB) error {
+	if len(k.Scopes) > 0 {
+		k.ScopeList = strings.Join(k.Scopes, ",")
+	}
+	return nil
+}
+
+// AfterFind mengisi Scopes dari kolom scopes
+func (k *APIKey) AfterFind(tx *gorm.DB) error {
+	k.Scopes = nil
+	if k.ScopeList != "" {
+		k.Scopes = strings.Split(k.ScopeList, ",")
+	}
+	return nil
+}
+
+// HasScope mengecek apakah kunci diberi scope tertentu.
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Expired mengecek apakah kunci sudah kedaluwarsa pada waktu now.
+func (k *APIKey) Expired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
+
+// Error kustom untuk API key
+var (
+	ErrAPIKeyInvalid        = errors.New("API key tidak valid atau kedaluwarsa")
+	ErrAPIKeyNameRequired   = errors.New("nama API key wajib diisi")
+	ErrAPIKeyScopesRequired = errors.New("minimal satu scope harus dipilih")
+	ErrAPIKeyScopeInvalid   = errors.New("scope API key tidak dikenal")
+	ErrAPIKeyExpiryInvalid  = errors.New("waktu kedaluwarsa API key harus di masa depan")
+)
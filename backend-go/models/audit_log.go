@@ -0,0 +1,66 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// AuditLog merekam satu aksi yang dilakukan terhadap sebuah resource (siapa mengubah apa).
+type AuditLog struct {
+	ID         uint            `gorm:"primaryKey" json:"id"`
+	ActorID    *uint           `gorm:"index" json:"actor_id"` // NULL jika aktor tidak diketahui (mis. login gagal)
+	ActorEmail string          `gorm:"size:255" json:"actor_email"`
+	Action     string          `gorm:"size:50;not null;index" json:"action"`
+	Resource   string          `gorm:"size:50;not null;index" json:"resource"`
+	ResourceID string          `gorm:"size:64" json:"resource_id"`
+	Before     json.RawMessage `gorm:"type:text" json:"before,omitempty"` // Snapshot JSON sebelum perubahan
+	After      json.RawMessage `gorm:"type:text" json:"after,omitempty"`  // Snapshot JSON sesudah perubahan
+	IP         string          `gorm:"size:45" json:"ip"`
+	UserAgent  string          `gorm:"size:255" json:"user_agent"`
+	CreatedAt  time.Time       `gorm:"index" json:"created_at"`
+}
+
+// Nama resource yang diaudit
+const (
+	AuditResourceProduct = "product"
+	AuditResourceUser    = "user"
+	AuditResourceAPIKey  = "api_key"
+	AuditResourceSession = "session"
+)
+
+// Aksi yang direkam di audit log
+const (
+	AuditActionProductCreate = "product.create"
+	AuditActionProductUpdate = "product.update"
+	AuditActionProductDelete = "product.delete"
+	AuditActionUserRegister  = "user.register"
+	AuditActionUserActivate  = "user.activate"
+	AuditActionUserSetRole   = "user.set_role"
+	AuditActionPasswordReset = "user.password_reset"
+	AuditActionLoginSuccess  = "auth.login.success"
+	AuditActionLoginFailure  = "auth.login.failure"
+	AuditActionMFAEnable     = "auth.mfa.enable"
+	AuditActionMFADisable    = "auth.mfa.disable"
+	AuditActionMFARecovery   = "auth.mfa.recovery_codes"
+	AuditActionAPIKeyCreate  = "auth.api_key.create"
+	AuditActionAPIKeyRevoke  = "auth.api_key.revoke"
+	AuditActionOIDCLink      = "auth.oidc.link"
+	AuditActionSessionRevoke = "auth.session.revoke"
+)
+
+// Aksi layanan mandiri akun (/me)
+const (
+	AuditActionProfileUpdate      = "user.profile_update"
+	AuditActionPasswordChange     = "user.password_change"
+	AuditActionEmailChangeRequest = "user.email_change_request"
+	AuditActionEmailChange        = "user.email_change"
+	AuditActionDeletionSchedule   = "user.deletion_schedule"
+	AuditActionDeletionCancel     = "user.deletion_cancel"
+	AuditActionUserDelete         = "user.delete"
+)
+
+// Error kustom untuk query audit log
+var (
+	ErrAuditInvalidTimeRange = errors.New("rentang waktu tidak valid: 'from' harus sebelum 'to'")
+)
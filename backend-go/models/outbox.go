@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Tipe aggregate yang menulis ke outbox
+const (
+	AggregateProduct = "product"
+)
+
+// OutboxMessage adalah event domain yang ditulis dalam transaksi yang sama dengan
+// perubahan datanya, lalu dipublikasikan oleh relay secara terpisah.
+// Baris dengan published_at NULL berarti belum berhasil dipublikasikan; baris dengan
+// dead_letter_at terisi sudah melewati batas percobaan dan tidak dicoba lagi.
+type OutboxMessage struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	AggregateType string     `gorm:"size:50;not null;index:idx_outbox_aggregate" json:"aggregate_type"`
+	AggregateID   uint       `gorm:"not null;index:idx_outbox_aggregate" json:"aggregate_id"`
+	EventType     string     `gorm:"size:50;not null" json:"event_type"`
+	Payload       string     `gorm:"type:text;not null" json:"payload"`
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	LastError     string     `gorm:"type:text" json:"last_error,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	PublishedAt   *time.Time `gorm:"index" json:"published_at,omitempty"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"` // Jadwal percobaan ulang setelah gagal
+	DeadLetterAt  *time.Time `gorm:"index" json:"dead_letter_at,omitempty"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Session adalah satu login interaktif. Access token membawa TokenID sebagai claim jti,
+// sehingga AuthMiddleware dapat menolak token milik sesi yang sudah dicabut.
+type Session struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	UserID     uint       `gorm:"not null;index" json:"-"`
+	TokenID    string     `gorm:"size:32;not null;uniqueIndex" json:"-"`
+	Device     string     `gorm:"size:100" json:"device"` // Ringkasan dari User-Agent, mis. "Firefox di Linux"
+	IP         string     `gorm:"size:45" json:"ip"`
+	UserAgent  string     `gorm:"size:255" json:"user_agent"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastSeenAt time.Time  `json:"last_seen_at"`
+	ExpiresAt  time.Time  `gorm:"index" json:"expires_at"` // Sama dengan masa berlaku access token
+	RevokedAt  *time.Time `json:"-"`
+	Current    bool       `gorm:"-" json:"current"` // Sesi milik token yang dipakai request ini
+}
+
+// Active mengecek apakah sesi belum dicabut dan belum kedaluwarsa pada waktu now.
+func (s *Session) Active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// ErrSessionRevoked dikembalikan untuk token yang sesinya sudah dicabut, kedaluwarsa atau tidak ada
+var ErrSessionRevoked = errors.New("sesi sudah dicabut atau berakhir")
@@ -3,6 +3,7 @@
 package models
 
 import (
+	"errors"
 	"time"
 )
 
@@ -13,13 +14,69 @@ type User struct {
     PasswordHash    string     `gorm:"not null" json:"-"` // Hash password disimpan, tidak diekspos di JSON
     Name            string     `json:"name"`
     IsActive        bool       `gorm:"default:false" json:"isActive"` // Status aktivasi akun (via email)
+    Role            string     `gorm:"size:20;not null;default:user" json:"role"` // Peran pengguna (user/admin)
     ActivationToken *string    `json:"-"` // Token acak untuk aktivasi email
     ResetToken      *string    `json:"-"` // Token acak untuk lupa password
     ResetTokenExpiry *time.Time `json:"-"` // Waktu kedaluwarsa token reset
+    TOTPSecret      *string    `gorm:"column:totp_secret" json:"-"` // Secret TOTP (base32); terisi sejak setup, berlaku setelah diaktifkan
+    TOTPEnabled     bool       `gorm:"column:totp_enabled;not null;default:false" json:"totpEnabled"` // Login wajib kode TOTP
+    TOTPLastCounter int64      `gorm:"column:totp_last_counter;not null;default:0" json:"-"` // Langkah waktu kode terakhir yang dipakai (anti replay)
+    OIDCIssuer      *string    `gorm:"column:oidc_issuer" json:"-"` // Penyedia identitas yang terhubung (claim iss)
+    OIDCSubject     *string    `gorm:"column:oidc_subject" json:"-"` // ID pengguna di penyedia identitas (claim sub)
+    PendingEmail    *string    `json:"pendingEmail"` // Email baru yang menunggu konfirmasi
+    EmailChangeToken *string   `json:"-"` // Token acak untuk konfirmasi email baru
+    EmailChangeExpiry *time.Time `json:"-"` // Waktu kedaluwarsa token konfirmasi email
+    DeletionScheduledAt *time.Time `json:"deletionScheduledAt"` // Akun dihapus permanen setelah waktu ini
     CreatedAt       time.Time
     UpdatedAt       time.Time
 }
 
+// Peran pengguna yang dikenali oleh middleware otorisasi
+const (
+    RoleUser  = "user"
+    RoleAdmin = "admin"
+)
+
+// IsValidRole memeriksa apakah role dikenali oleh middleware otorisasi
+func IsValidRole(role string) bool {
+    return role == RoleUser || role == RoleAdmin
+}
+
+// Error kustom untuk autentikasi dan registrasi
+var (
+    ErrEmailAlreadyRegistered  = errors.New("email sudah terdaftar")
+    ErrInvalidCredentials      = errors.New("kredensial tidak valid")
+    ErrAccountNotActive        = errors.New("akun belum diaktifkan")
+    ErrActivationTokenInvalid  = errors.New("token aktivasi tidak valid")
+    ErrPasswordTooShort        = errors.New("password minimal 8 karakter")
+    ErrInvalidRole             = errors.New("role tidak dikenal")
+    ErrTooManyLoginAttempts    = errors.New("terlalu banyak percobaan login")
+    ErrMFATokenInvalid         = errors.New("token MFA tidak valid atau kedaluwarsa")
+    ErrMFACodeInvalid          = errors.New("kode MFA tidak valid")
+    ErrMFAAlreadyEnabled       = errors.New("TOTP sudah aktif")
+    ErrMFANotEnabled           = errors.New("TOTP belum aktif")
+    ErrMFANotSetUp             = errors.New("TOTP belum disiapkan")
+    ErrOIDCStateInvalid        = errors.New("sesi login OIDC tidak valid atau kedaluwarsa")
+    ErrOIDCLoginFailed         = errors.New("login lewat penyedia identitas gagal")
+    ErrOIDCEmailNotVerified    = errors.New("email dari penyedia identitas belum terverifikasi")
+    ErrOIDCDomainNotAllowed    = errors.New("domain email tidak diizinkan untuk login OIDC")
+    ErrOIDCAccountNotFound     = errors.New("akun untuk email ini belum terdaftar")
+    ErrOIDCIdentityConflict    = errors.New("akun sudah terhubung ke identitas OIDC lain")
+    ErrEmailChangeTokenInvalid = errors.New("token konfirmasi email tidak valid atau kedaluwarsa")
+    ErrEmailUnchanged          = errors.New("email baru sama dengan email saat ini")
+    ErrDeletionNotScheduled    = errors.New("penghapusan akun tidak dijadwalkan")
+)
+
+// RecoveryCode adalah kode cadangan sekali pakai untuk login tanpa aplikasi authenticator.
+// Hanya hash SHA-256 yang disimpan; kode aslinya ditampilkan sekali saat dibuat.
+type RecoveryCode struct {
+    ID        uint       `gorm:"primaryKey" json:"-"`
+    UserID    uint       `gorm:"not null" json:"-"`
+    CodeHash  string     `gorm:"size:64;not null" json:"-"`
+    UsedAt    *time.Time `json:"-"`
+    CreatedAt time.Time  `json:"-"`
+}
+
 // Catatan:
 // 1. Tag `json:"-"` menyembunyikan field sensitif (PasswordHash, token) dari respons API JSON.
 // 2. Tipe *string dan *time.Time (pointer) membuat kolom-kolom token menjadi NULLABLE di database.
\ No newline at end of file
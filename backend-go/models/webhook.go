@@ -0,0 +1,94 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Status pengiriman webhook
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// WebhookSubscription adalah endpoint eksternal yang ingin menerima event tertentu.
+type WebhookSubscription struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	URL        string    `gorm:"size:2048;not null" json:"url"`
+	Secret     string    `gorm:"size:255;not null" json:"-"` // Kunci HMAC, hanya ditampilkan saat dibuat
+	EventTypes string    `gorm:"size:255;not null" json:"-"` // Disimpan sebagai daftar dipisah koma
+	Events     []string  `gorm:"-" json:"event_types"`
+	Active     bool      `gorm:"not null;default:true" json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// BeforeSave menyimpan Events ke kolom event_types
+func (s *WebhookSubscription) BeforeSave(tx *gorm.DB) error {
+	if len(s.Events) > 0 {
+		s.EventTypes = strings.Join(s.Events, ",")
+	}
+	return nil
+}
+
+// AfterFind mengisi Events dari kolom event_types
+func (s *WebhookSubscription) AfterFind(tx *gorm.DB) error {
+	s.Events = nil
+	if s.EventTypes != "" {
+		s.Events = strings.Split(s.EventTypes, ",")
+	}
+	return nil
+}
+
+// Wants mengecek apakah subscription ini berlangganan tipe event tertentu.
+func (s *WebhookSubscription) Wants(eventType string) bool {
+	for _, e := range s.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery adalah satu event yang antre untuk dikirim ke sebuah subscription.
+// Tabel ini sekaligus berfungsi sebagai antrean persisten: baris pending dengan
+// next_attempt_at <= sekarang akan diambil oleh worker.
+type WebhookDelivery struct {
+	ID             uint                 `gorm:"primaryKey" json:"id"`
+	SubscriptionID uint                 `gorm:"not null;index" json:"subscription_id"`
+	EventType      string               `gorm:"size:50;not null" json:"event_type"`
+	Payload        string               `gorm:"type:text;not null" json:"payload"`
+	Status         string               `gorm:"size:20;not null;index" json:"status"`
+	Attempts       int                  `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt  time.Time            `gorm:"index" json:"next_attempt_at"`
+	LastError      string               `gorm:"type:text" json:"last_error,omitempty"`
+	ResponseStatus int                  `json:"response_status,omitempty"`
+	DeliveredAt    *time.Time           `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time            `json:"created_at"`
+	UpdatedAt      time.Time            `json:"updated_at"`
+	Logs           []WebhookDeliveryLog `gorm:"foreignKey:DeliveryID" json:"logs,omitempty"`
+}
+
+// WebhookDeliveryLog merekam hasil satu percobaan pengiriman.
+type WebhookDeliveryLog struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	DeliveryID     uint      `gorm:"not null;index" json:"delivery_id"`
+	Attempt        int       `gorm:"not null" json:"attempt"`
+	ResponseStatus int       `json:"response_status,omitempty"`
+	Error          string    `gorm:"type:text" json:"error,omitempty"`
+	DurationMs     int64     `json:"duration_ms"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// Error kustom untuk webhook
+var (
+	ErrWebhookURLInvalid         = errors.New("URL webhook harus berupa URL http/https yang valid")
+	ErrWebhookEventTypesRequired = errors.New("minimal satu tipe event harus dipilih")
+	ErrWebhookEventTypeInvalid   = errors.New("tipe event webhook tidak dikenal")
+	ErrWebhookDeliveryPending    = errors.New("pengiriman webhook masih dalam antrean")
+	ErrWebhookTargetBlocked      = errors.New("tujuan webhook berada di jaringan internal")
+)
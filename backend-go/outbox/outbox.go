@@ -0,0 +1,47 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"fullstack-crud-project-01/backend-go/models"
+)
+
+// Publisher adalah tujuan publikasi pesan outbox (in-memory bus, webhook, broker, dll).
+// Publish harus mengembalikan error jika pesan belum pasti diterima agar relay mencobanya lagi.
+type Publisher interface {
+	Publish(ctx context.Context, msg models.OutboxMessage) error
+}
+
+// Store adalah penyimpanan pesan outbox yang dibaca oleh relay.
+type Store interface {
+	// Pending mengambil pesan yang belum dipublikasikan dan sudah jatuh tempo pada now, urut
+	// berdasarkan ID (urutan penulisan). Pesan milik aggregate yang pesan sebelumnya masih
+	// menunggu jadwal retry dilewati agar aggregate lain tidak ikut tertahan.
+	Pending(now time.Time, limit int) ([]models.OutboxMessage, error)
+	MarkPublished(id uint) error
+	// MarkFailed mencatat percobaan yang gagal dan menjadwalkan percobaan berikutnya.
+	MarkFailed(id uint, reason string, nextAttemptAt time.Time) error
+	// MarkDeadLettered mencatat percobaan terakhir yang gagal dan berhenti mencoba pesan tersebut.
+	MarkDeadLettered(id uint, reason string) error
+}
+
+// Write menambahkan pesan outbox menggunakan transaksi tx milik pemanggil,
+// sehingga pesan hanya tersimpan jika perubahan datanya ikut ter-commit.
+func Write(tx *gorm.DB, aggregateType string, aggregateID uint, eventType string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("gagal encode payload outbox: %w", err)
+	}
+	msg := models.OutboxMessage{
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		EventType:     eventType,
+		Payload:       string(data),
+	}
+	return tx.Create(&msg).Error
+}
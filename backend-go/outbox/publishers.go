@@ -0,0 +1,146 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+
+	"fullstack-crud-project-01/backend-go/events"
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/services"
+)
+
+// BusPublisher meneruskan pesan outbox ke events.Bus in-process (SSE, WebSocket).
+type BusPublisher struct {
+	Bus *events.Bus
+}
+
+// Publish mengirim pesan ke bus
+func (p *BusPublisher) Publish(ctx context.Context, msg models.OutboxMessage) error {
+	return p.Bus.Publish(msg.EventType, msg.AggregateID, json.RawMessage(msg.Payload))
+}
+
+// WebhookPublisher memasukkan pesan outbox ke antrean persisten webhook.
+type WebhookPublisher struct {
+	Webhooks *services.WebhookService
+}
+
+// Publish memasukkan pesan ke antrean pengiriman webhook
+func (p *WebhookPublisher) Publish(ctx context.Context, msg models.OutboxMessage) error {
+	return p.Webhooks.Enqueue(events.Event{
+		ID:          uint64(msg.ID),
+		Type:        msg.EventType,
+		AggregateID: msg.AggregateID,
+		Data:        json.RawMessage(msg.Payload),
+		OccurredAt:  msg.CreatedAt,
+	})
+}
+
+// MultiPublisher meneruskan pesan ke beberapa publisher secara berurutan dan berhenti pada
+// publisher pertama yang gagal. Saat dicoba ulang, publisher sebelum titik gagal menerima
+// pesan yang sama lagi (at-least-once). Karena itu publisher tahan lama (antrean webhook,
+// broker) diletakkan lebih dulu dan BusPublisher terakhir, agar klien SSE/WebSocket tidak
+// menerima perubahan yang sama dua kali dengan ID event yang berbeda.
+type MultiPublisher []Publisher
+
+// Publish mengirim pesan ke setiap publisher
+func (m MultiPublisher) Publish(ctx context.Context, msg models.OutboxMessage) error {
+	for _, p := range m {
+		if err := p.Publish(ctx, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SubjectConn adalah bagian minimal dari koneksi broker bergaya NATS.
+// *nats.Conn dari github.com/nats-io/nats.go sudah memenuhi interface ini.
+type SubjectConn interface {
+	Publish(subject string, data []byte) error
+}
+
+// SubjectPublisher mempublikasikan pesan ke subject "<prefix>.<event_type>",
+// mis. "catalog.product.created".
+type SubjectPublisher struct {
+	Conn   SubjectConn
+	Prefix string
+}
+
+// Publish mengirim payload pesan ke subject yang sesuai
+func (p *SubjectPublisher) Publish(ctx context.Context, msg models.OutboxMessage) error {
+	subject := msg.EventType
+	if p.Prefix != "" {
+		subject = p.Prefix + "." + subject
+	}
+	return p.Conn.Publish(subject, []byte(msg.Payload))
+}
+
+// ErrBrokerClosed dikembalikan saat publish ke MemoryBroker yang sudah ditutup
+var ErrBrokerClosed = errors.New("broker sudah ditutup")
+
+// MemoryBroker adalah pengganti broker NATS di dalam proses untuk pengembangan dan pengujian.
+// Mendukung wildcard subject NATS: "*" untuk satu token dan ">" untuk sisa token.
+type MemoryBroker struct {
+	mu       sync.RWMutex
+	closed   bool
+	handlers []brokerHandler
+}
+
+type brokerHandler struct {
+	pattern string
+	fn      func(subject string, data []byte)
+}
+
+// NewMemoryBroker membuat MemoryBroker kosong
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{}
+}
+
+// Subscribe mendaftarkan handler untuk subject yang cocok dengan pattern.
+func (b *MemoryBroker) Subscribe(pattern string, fn func(subject string, data []byte)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, brokerHandler{pattern: pattern, fn: fn})
+}
+
+// Publish memanggil semua handler yang cocok secara sinkron.
+func (b *MemoryBroker) Publish(subject string, data []byte) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return ErrBrokerClosed
+	}
+	for _, h := range b.handlers {
+		if subjectMatches(h.pattern, subject) {
+			h.fn(subject, data)
+		}
+	}
+	return nil
+}
+
+// Close menolak publish berikutnya
+func (b *MemoryBroker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+}
+
+// subjectMatches mencocokkan subject dengan pattern bergaya NATS
+func subjectMatches(pattern, subject string) bool {
+	patternTokens := strings.Split(pattern, ".")
+	subjectTokens := strings.Split(subject, ".")
+	for i, token := range patternTokens {
+		if token == ">" {
+			return len(subjectTokens) > i
+		}
+		if i >= len(subjectTokens) {
+			return false
+		}
+		if token != "*" && token != subjectTokens[i] {
+			return false
+		}
+	}
+	return len(patternTokens) == len(subjectTokens)
+}
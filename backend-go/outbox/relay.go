@@ -0,0 +1,156 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/retry"
+	"fullstack-crud-project-01/backend-go/services"
+)
+
+// Pengaturan default relay
+const (
+	defaultRelayBatchSize   = 100
+	defaultRelayMaxAttempts = 15
+	defaultRelayBaseBackoff = time.Second
+	defaultRelayMaxBackoff  = 10 * time.Minute
+)
+
+// Leader memastikan hanya satu relay yang aktif per database walaupun aplikasi berjalan
+// di beberapa replika.
+type Leader = services.Leader
+
+// Relay membaca pesan outbox yang belum terkirim dan meneruskannya ke Publisher.
+//
+// Urutan dijamin per aggregate: jika satu pesan gagal, pesan berikutnya untuk
+// aggregate yang sama ditahan hingga pesan tersebut berhasil. Pengiriman bersifat
+// at-least-once, jadi hanya satu relay yang boleh berjalan per database; isi Leader
+// jika aplikasi dijalankan lebih dari satu instance.
+//
+// Pesan yang gagal dicoba ulang dengan backoff eksponensial. Setelah MaxAttempts percobaan
+// pesan dipindahkan ke dead letter (dead_letter_at) dan pesan berikutnya untuk aggregate
+// yang sama kembali diproses.
+type Relay struct {
+	Store       Store
+	Publisher   Publisher
+	Leader      Leader // Opsional: nil berarti relay selalu berjalan
+	BatchSize   int
+	Interval    time.Duration
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	Now         func() time.Time // Dapat diganti saat pengujian
+}
+
+// NewRelay adalah konstruktor untuk Relay dengan pengaturan retry default.
+func NewRelay(store Store, publisher Publisher, interval time.Duration) *Relay {
+	return &Relay{
+		Store:       store,
+		Publisher:   publisher,
+		BatchSize:   defaultRelayBatchSize,
+		Interval:    interval,
+		MaxAttempts: defaultRelayMaxAttempts,
+		BaseBackoff: defaultRelayBaseBackoff,
+		MaxBackoff:  defaultRelayMaxBackoff,
+		Now:         time.Now,
+	}
+}
+
+// RunOnce mempublikasikan satu batch pesan pending. Mengembalikan jumlah pesan yang berhasil.
+func (r *Relay) RunOnce(ctx context.Context) (int, error) {
+	messages, err := r.Store.Pending(r.Now(), r.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	blocked := make(map[string]bool)
+	for _, msg := range messages {
+		if ctx.Err() != nil {
+			return published, ctx.Err()
+		}
+
+		key := fmt.Sprintf("%s:%d", msg.AggregateType, msg.AggregateID)
+		if blocked[key] {
+			continue
+		}
+
+		if err := r.Publisher.Publish(ctx, msg); err != nil {
+			blocked[key] = true
+			if markErr := r.markFailed(ctx, msg, err); markErr != nil {
+				return published, markErr
+			}
+			continue
+		}
+		if err := r.Store.MarkPublished(msg.ID); err != nil {
+			return published, err
+		}
+		published++
+	}
+	return published, nil
+}
+
+// Run menjalankan RunOnce secara berkala hingga ctx dibatalkan. Jika Leader diisi, batch
+// hanya diproses selama instance ini memegang lock leader.
+// Batch yang penuh langsung disusul batch berikutnya tanpa menunggu interval.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+	if r.Leader != nil {
+		defer func() {
+			// Lock tetap dilepas walaupun ctx sudah dibatalkan
+			if err := r.Leader.Release(context.Background()); err != nil {
+				slog.Error("Gagal melepas lock leader relay outbox", "error", err)
+			}
+		}()
+	}
+
+	leading := false
+	for {
+		n := 0
+		active, err := r.acquire(ctx)
+		if active != leading && err == nil {
+			leading = active
+			slog.InfoContext(ctx, "Status leader relay outbox berubah", "leader", leading)
+		}
+		if active {
+			n, err = r.RunOnce(ctx)
+		}
+		if err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "Relay outbox gagal", "error", err)
+		}
+		if n >= r.BatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// markFailed menjadwalkan percobaan ulang, atau memindahkan pesan ke dead letter jika
+// batas percobaan sudah tercapai
+func (r *Relay) markFailed(ctx context.Context, msg models.OutboxMessage, err error) error {
+	attempts := msg.Attempts + 1
+	if attempts >= r.MaxAttempts {
+		slog.ErrorContext(ctx, "Pesan outbox gagal dipublikasikan dan dipindahkan ke dead letter",
+			"outbox_id", msg.ID, "event", msg.EventType, "aggregate_id", msg.AggregateID,
+			"attempts", attempts, "error", err)
+		return r.Store.MarkDeadLettered(msg.ID, err.Error())
+	}
+	return r.Store.MarkFailed(msg.ID, err.Error(), r.Now().Add(retry.Backoff(attempts, r.BaseBackoff, r.MaxBackoff)))
+}
+
+// acquire memeriksa apakah relay ini boleh memproses batch
+func (r *Relay) acquire(ctx context.Context) (bool, error) {
+	if r.Leader == nil {
+		return true, nil
+	}
+	return r.Leader.Acquire(ctx)
+}
@@ -0,0 +1,265 @@
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"fullstack-crud-project-01/backend-go/events"
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/outbox"
+)
+
+// MockStore adalah implementasi in-memory dari outbox.Store
+type MockStore struct {
+	messages  []models.OutboxMessage
+	published map[uint]bool
+}
+
+func newMockStore(messages ...models.OutboxMessage) *MockStore {
+	return &MockStore{messages: messages, published: make(map[uint]bool)}
+}
+
+func (m *MockStore) Pending(now time.Time, limit int) ([]models.OutboxMessage, error) {
+	var pending []models.OutboxMessage
+	waiting := make(map[uint]bool) // Aggregate yang pesan sebelumnya menunggu jadwal retry
+	for _, msg := range m.messages {
+		if m.published[msg.ID] || msg.DeadLetterAt != nil {
+			continue
+		}
+		if msg.NextAttemptAt != nil && msg.NextAttemptAt.After(now) {
+			waiting[msg.AggregateID] = true
+			continue
+		}
+		if !waiting[msg.AggregateID] && len(pending) < limit {
+			pending = append(pending, msg)
+		}
+	}
+	return pending, nil
+}
+
+func (m *MockStore) MarkPublished(id uint) error {
+	m.published[id] = true
+	return nil
+}
+
+func (m *MockStore) MarkFailed(id uint, reason string, nextAttemptAt time.Time) error {
+	msg := m.find(id)
+	msg.Attempts++
+	msg.LastError = reason
+	msg.NextAttemptAt = &nextAttemptAt
+	return nil
+}
+
+func (m *MockStore) MarkDeadLettered(id uint, reason string) error {
+	msg := m.find(id)
+	now := time.Now()
+	msg.Attempts++
+	msg.LastError = reason
+	msg.DeadLetterAt = &now
+	return nil
+}
+
+func (m *MockStore) find(id uint) *models.OutboxMessage {
+	for i := range m.messages {
+		if m.messages[i].ID == id {
+			return &m.messages[i]
+		}
+	}
+	return nil
+}
+
+// recordingPublisher mencatat pesan yang diterima dan bisa digagalkan per ID pesan
+type recordingPublisher struct {
+	failIDs  map[uint]bool
+	received []uint
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, msg models.OutboxMessage) error {
+	if p.failIDs[msg.ID] {
+		return errors.New("broker tidak tersedia")
+	}
+	p.received = append(p.received, msg.ID)
+	return nil
+}
+
+func product(id, aggregateID uint) models.OutboxMessage {
+	return models.OutboxMessage{ID: id, AggregateType: models.AggregateProduct, AggregateID: aggregateID, EventType: events.ProductUpdated, Payload: `{}`}
+}
+
+func TestRelay_PreservesOrderPerAggregate(t *testing.T) {
+	// Pesan 1 & 3 milik produk 10, pesan 2 & 4 milik produk 20
+	store := newMockStore(product(1, 10), product(2, 20), product(3, 10), product(4, 20))
+	publisher := &recordingPublisher{failIDs: map[uint]bool{1: true}}
+	relay := outbox.NewRelay(store, publisher, 0)
+
+	// 1. Pesan 1 gagal: pesan 3 (aggregate sama) harus ditahan, produk 20 tetap jalan
+	n, err := relay.RunOnce(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []uint{2, 4}, publisher.received)
+
+	// 2. Selama jadwal retry pesan 1 belum tiba, produk 10 dilewati seluruhnya
+	publisher.failIDs = nil
+	n, err = relay.RunOnce(context.Background())
+	assert.NoError(t, err)
+	assert.Zero(t, n)
+
+	// 3. Setelah jadwal retry tiba, pesan 1 lalu 3 dipublikasikan sesuai urutan
+	relay.Now = func() time.Time { return time.Now().Add(time.Minute) }
+	n, err = relay.RunOnce(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []uint{2, 4, 1, 3}, publisher.received)
+}
+
+func TestRelay_BackoffAndDeadLetter(t *testing.T) {
+	// Pesan 1 selalu gagal, pesan 2 menunggu di belakangnya pada aggregate yang sama
+	store := newMockStore(product(1, 10), product(2, 10))
+	publisher := &recordingPublisher{failIDs: map[uint]bool{1: true}}
+	relay := outbox.NewRelay(store, publisher, 0)
+	relay.MaxAttempts = 3
+	relay.BaseBackoff = time.Second
+	relay.MaxBackoff = 3 * time.Second
+
+	now := time.Now()
+	relay.Now = func() time.Time { return now }
+
+	var delays []time.Duration
+	for i := 0; i < 2; i++ {
+		_, err := relay.RunOnce(context.Background())
+		assert.NoError(t, err)
+		msg := store.find(1)
+		assert.Equal(t, i+1, msg.Attempts)
+		delays = append(delays, msg.NextAttemptAt.Sub(now))
+		now = *msg.NextAttemptAt
+	}
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, delays)
+
+	// Percobaan ke-3 mencapai MaxAttempts: pesan 1 masuk dead letter dan pesan 2 tidak lagi tertahan
+	_, err := relay.RunOnce(context.Background())
+	assert.NoError(t, err)
+	assert.NotNil(t, store.find(1).DeadLetterAt)
+	assert.Equal(t, 3, store.find(1).Attempts)
+
+	n, err := relay.RunOnce(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, []uint{2}, publisher.received)
+}
+
+func TestMultiPublisher_RetryDoesNotRepeatLaterPublishers(t *testing.T) {
+	// Antrean webhook gagal sekali; bus diletakkan terakhir seperti di main.go
+	store := newMockStore(product(1, 10))
+	webhook := &recordingPublisher{failIDs: map[uint]bool{1: true}}
+	bus := &recordingPublisher{}
+	relay := outbox.NewRelay(store, outbox.MultiPublisher{webhook, bus}, 0)
+
+	_, err := relay.RunOnce(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, bus.received)
+
+	webhook.failIDs = nil
+	relay.Now = func() time.Time { return time.Now().Add(time.Hour) }
+	_, err = relay.RunOnce(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{1}, webhook.received)
+	assert.Equal(t, []uint{1}, bus.received, "Bus hanya menerima pesan sekali")
+}
+
+func TestBusPublisher(t *testing.T) {
+	bus := events.NewBus(10)
+	sub, _, _ := bus.Subscribe(0)
+	defer sub.Cancel()
+
+	publisher := &outbox.BusPublisher{Bus: bus}
+	err := publisher.Publish(context.Background(), models.OutboxMessage{ID: 1, AggregateID: 5, EventType: events.ProductCreated, Payload: `{"name":"Laptop"}`})
+	assert.NoError(t, err)
+
+	event := <-sub.C
+	assert.Equal(t, events.ProductCreated, event.Type)
+	assert.Equal(t, uint(5), event.AggregateID)
+	assert.JSONEq(t, `{"name":"Laptop"}`, string(event.Data))
+}
+
+func TestSubjectPublisher_WithMemoryBroker(t *testing.T) {
+	broker := outbox.NewMemoryBroker()
+	var subjects []string
+	broker.Subscribe("catalog.product.*", func(subject string, data []byte) { subjects = append(subjects, subject) })
+	broker.Subscribe("catalog.>", func(subject string, data []byte) { subjects = append(subjects, ">"+subject) })
+	broker.Subscribe("catalog.order.*", func(subject string, data []byte) { t.Errorf("Unexpected subject %s", subject) })
+
+	publisher := &outbox.SubjectPublisher{Conn: broker, Prefix: "catalog"}
+	assert.NoError(t, publisher.Publish(context.Background(), models.OutboxMessage{EventType: events.ProductDeleted, Payload: `{"id":1}`}))
+	assert.Equal(t, []string{"catalog.product.deleted", ">catalog.product.deleted"}, subjects)
+
+	broker.Close()
+	assert.ErrorIs(t, publisher.Publish(context.Background(), models.OutboxMessage{EventType: events.ProductDeleted}), outbox.ErrBrokerClosed)
+}
+
+// stubLeader adalah outbox.Leader yang status leader-nya diatur oleh pengujian
+type stubLeader struct {
+	mu       sync.Mutex
+	leader   bool
+	released bool
+}
+
+func (l *stubLeader) Acquire(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.leader, nil
+}
+
+func (l *stubLeader) Release(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.released = true
+	return nil
+}
+
+func (l *stubLeader) set(leader bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.leader = leader
+}
+
+// countingPublisher menghitung pesan yang dipublikasikan, aman dipakai dari goroutine relay
+type countingPublisher struct {
+	count atomic.Int32
+}
+
+func (p *countingPublisher) Publish(ctx context.Context, msg models.OutboxMessage) error {
+	p.count.Add(1)
+	return nil
+}
+
+func TestRelay_RunsOnlyWhileLeader(t *testing.T) {
+	store := newMockStore(product(1, 10))
+	publisher := &countingPublisher{}
+	leader := &stubLeader{}
+	relay := outbox.NewRelay(store, publisher, 10*time.Millisecond)
+	relay.Leader = leader
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		relay.Run(ctx)
+		close(done)
+	}()
+
+	// Replika lain memegang lock: tidak ada yang dipublikasikan
+	time.Sleep(50 * time.Millisecond)
+	assert.Zero(t, publisher.count.Load())
+
+	leader.set(true)
+	assert.Eventually(t, func() bool { return publisher.count.Load() == 1 }, time.Second, 10*time.Millisecond)
+
+	cancel()
+	<-done
+	assert.True(t, leader.released, "Lock harus dilepas saat relay berhenti")
+}
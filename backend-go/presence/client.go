@@ -0,0 +1,125 @@
+package presence
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// client adalah satu koneksi WebSocket milik seorang pengguna.
+type client struct {
+	hub  *Hub
+	conn *websocket.Conn
+	user User
+	send chan ServerMessage
+	// check dijalankan setiap RecheckInterval; error berarti koneksi harus ditutup (nil = tanpa pemeriksaan)
+	check func() error
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// enqueue mengirim pesan tanpa memblokir; klien yang antreannya penuh dianggap macet dan diputus.
+func (c *client) enqueue(msg ServerMessage) {
+	select {
+	case <-c.done:
+	case c.send <- msg:
+	default:
+		c.close()
+	}
+}
+
+func (c *client) close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.conn.Close()
+	})
+}
+
+// readPump membaca pesan klien dan memperpanjang read deadline setiap kali pong diterima.
+// Koneksi yang tidak membalas ping dalam pongWait akan gagal dibaca lalu dibersihkan.
+func (c *client) readPump() {
+	defer func() {
+		c.hub.leaveAll(c)
+		c.close()
+	}()
+
+	c.conn.SetReadLimit(maxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		var msg ClientMessage
+		if err := c.conn.ReadJSON(&msg); err != nil {
+			return
+		}
+		c.handle(msg)
+	}
+}
+
+func (c *client) handle(msg ClientMessage) {
+	if msg.ProductID == 0 {
+		c.enqueue(ServerMessage{Type: MessageError, Error: "product_id wajib diisi"})
+		return
+	}
+
+	switch msg.Type {
+	case MessageSubscribe:
+		mode := msg.Mode
+		if mode == "" {
+			mode = ModeViewing
+		}
+		if mode != ModeViewing && mode != ModeEditing {
+			c.enqueue(ServerMessage{Type: MessageError, ProductID: msg.ProductID, Error: "mode harus 'viewing' atau 'editing'"})
+			return
+		}
+		c.hub.join(c, msg.ProductID, mode)
+	case MessageUnsubscribe:
+		c.hub.leave(c, msg.ProductID)
+	default:
+		c.enqueue(ServerMessage{Type: MessageError, ProductID: msg.ProductID, Error: "tipe pesan tidak dikenal"})
+	}
+}
+
+// writePump menulis pesan antrean, mengirim ping berkala sebagai heartbeat dan memeriksa
+// ulang sesi pengguna. Koneksi yang sesinya tidak berlaku lagi ditutup dengan alasan di close frame.
+func (c *client) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.close()
+	}()
+
+	var recheck <-chan time.Time
+	if c.check != nil {
+		recheckTicker := time.NewTicker(c.hub.RecheckInterval)
+		defer recheckTicker.Stop()
+		recheck = recheckTicker.C
+	}
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case msg := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteJSON(msg); err != nil {
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-recheck:
+			if err := c.check(); err != nil {
+				c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+				c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.ClosePolicyViolation, err.Error()))
+				return
+			}
+		}
+	}
+}
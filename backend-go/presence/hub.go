@@ -0,0 +1,228 @@
+package presence
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"fullstack-crud-project-01/backend-go/events"
+)
+
+// Mode kehadiran pengguna pada sebuah produk
+const (
+	ModeViewing = "viewing"
+	ModeEditing = "editing"
+)
+
+// Tipe pesan yang dikirim/diterima melalui WebSocket
+const (
+	MessageSubscribe   = "subscribe"
+	MessageUnsubscribe = "unsubscribe"
+	MessagePresence    = "presence"
+	MessageError       = "error"
+)
+
+// Pengaturan heartbeat dan batas koneksi
+const (
+	writeWait      = 10 * time.Second
+	pongWait       = 60 * time.Second
+	pingPeriod     = (pongWait * 9) / 10 // Harus lebih kecil dari pongWait
+	maxMessageSize = 4096
+	sendBuffer     = 32
+)
+
+// User adalah identitas pengguna yang sudah terautentikasi untuk sebuah koneksi.
+type User struct {
+	ID    uint   `json:"user_id"`
+	Email string `json:"email"`
+}
+
+// Viewer adalah entri daftar kehadiran pada sebuah produk.
+type Viewer struct {
+	User
+	Mode string `json:"mode"`
+}
+
+// ClientMessage adalah pesan dari klien.
+type ClientMessage struct {
+	Type      string `json:"type"`
+	ProductID uint   `json:"product_id"`
+	Mode      string `json:"mode,omitempty"`
+}
+
+// ServerMessage adalah pesan dari server: daftar kehadiran, notifikasi perubahan, atau error.
+type ServerMessage struct {
+	Type      string          `json:"type"`
+	ProductID uint            `json:"product_id,omitempty"`
+	Users     []Viewer        `json:"users,omitempty"`
+	Data      json.RawMessage `json:"data,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// Hub mengelola koneksi WebSocket, ruang per produk dan penyiaran kehadiran serta perubahan.
+type Hub struct {
+	RecheckInterval time.Duration // Interval pemeriksaan ulang sesi koneksi (default sama dengan heartbeat ping)
+
+	mu    sync.Mutex
+	rooms map[uint]map[*client]string // productID -> client -> mode
+	bus   *events.Bus
+}
+
+// NewHub membuat Hub yang meneruskan event produk dari bus ke ruang yang relevan.
+func NewHub(bus *events.Bus) *Hub {
+	return &Hub{RecheckInterval: pingPeriod, rooms: make(map[uint]map[*client]string), bus: bus}
+}
+
+// Run meneruskan event perubahan produk dari bus ke subscriber hingga ctx dibatalkan.
+func (h *Hub) Run(ctx context.Context) {
+	if h.bus == nil {
+		return
+	}
+	for {
+		sub, _, _ := h.bus.Subscribe(0)
+		if !h.forward(ctx, sub) {
+			return
+		}
+		// Subscription diputus bus karena tertinggal; daftar ulang
+	}
+}
+
+func (h *Hub) forward(ctx context.Context, sub *events.Subscription) bool {
+	defer sub.Cancel()
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case event, ok := <-sub.C:
+			if !ok {
+				return true
+			}
+			h.broadcast(event.AggregateID, ServerMessage{
+				Type:      event.Type,
+				ProductID: event.AggregateID,
+				Data:      event.Data,
+			})
+		}
+	}
+}
+
+// Serve menjalankan siklus hidup satu koneksi yang sudah di-upgrade hingga koneksi ditutup.
+func (h *Hub) Serve(conn *websocket.Conn, user User) {
+	h.ServeWithCheck(conn, user, nil)
+}
+
+// ServeWithCheck seperti Serve, tetapi menjalankan check setiap RecheckInterval selama
+// koneksi terbuka (mis. memastikan sesi login belum dicabut). Jika check mengembalikan
+// error, koneksi ditutup dengan close frame 1008 berisi pesan error tersebut.
+func (h *Hub) ServeWithCheck(conn *websocket.Conn, user User, check func() error) {
+	c := &client{
+		hub:   h,
+		conn:  conn,
+		user:  user,
+		send:  make(chan ServerMessage, sendBuffer),
+		check: check,
+		done:  make(chan struct{}),
+	}
+	go c.writePump()
+	c.readPump()
+}
+
+// Viewers mengembalikan daftar kehadiran saat ini pada sebuah produk.
+func (h *Hub) Viewers(productID uint) []Viewer {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.viewersLocked(productID)
+}
+
+func (h *Hub) join(c *client, productID uint, mode string) {
+	h.mu.Lock()
+	room, ok := h.rooms[productID]
+	if !ok {
+		room = make(map[*client]string)
+		h.rooms[productID] = room
+	}
+	room[c] = mode
+	h.mu.Unlock()
+
+	h.broadcastPresence(productID)
+}
+
+func (h *Hub) leave(c *client, productID uint) {
+	h.mu.Lock()
+	room, ok := h.rooms[productID]
+	if ok {
+		delete(room, c)
+		if len(room) == 0 {
+			delete(h.rooms, productID)
+		}
+	}
+	h.mu.Unlock()
+
+	if ok {
+		h.broadcastPresence(productID)
+	}
+}
+
+// leaveAll membersihkan semua ruang milik klien yang terputus.
+func (h *Hub) leaveAll(c *client) {
+	h.mu.Lock()
+	var left []uint
+	for productID, room := range h.rooms {
+		if _, ok := room[c]; ok {
+			delete(room, c)
+			if len(room) == 0 {
+				delete(h.rooms, productID)
+			}
+			left = append(left, productID)
+		}
+	}
+	h.mu.Unlock()
+
+	for _, productID := range left {
+		h.broadcastPresence(productID)
+	}
+}
+
+func (h *Hub) broadcastPresence(productID uint) {
+	h.mu.Lock()
+	viewers := h.viewersLocked(productID)
+	h.mu.Unlock()
+
+	h.broadcast(productID, ServerMessage{Type: MessagePresence, ProductID: productID, Users: viewers})
+}
+
+func (h *Hub) broadcast(productID uint, msg ServerMessage) {
+	h.mu.Lock()
+	targets := make([]*client, 0, len(h.rooms[productID]))
+	for c := range h.rooms[productID] {
+		targets = append(targets, c)
+	}
+	h.mu.Unlock()
+
+	for _, c := range targets {
+		c.enqueue(msg)
+	}
+}
+
+// viewersLocked menggabungkan koneksi per pengguna; mode "editing" mengalahkan "viewing".
+// Pemanggil wajib memegang h.mu.
+func (h *Hub) viewersLocked(productID uint) []Viewer {
+	byUser := make(map[uint]Viewer)
+	for c, mode := range h.rooms[productID] {
+		existing, ok := byUser[c.user.ID]
+		if !ok || (existing.Mode != ModeEditing && mode == ModeEditing) {
+			byUser[c.user.ID] = Viewer{User: c.user, Mode: mode}
+		}
+	}
+
+	viewers := make([]Viewer, 0, len(byUser))
+	for _, v := range byUser {
+		viewers = append(viewers, v)
+	}
+	sort.Slice(viewers, func(i, j int) bool { return viewers[i].ID < viewers[j].ID })
+	return viewers
+}
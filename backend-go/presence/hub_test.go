@@ -0,0 +1,148 @@
+package presence_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"fullstack-crud-project-01/backend-go/events"
+	"fullstack-crud-project-01/backend-go/presence"
+)
+
+// setupHubServer membuat server WebSocket uji; identitas pengguna diambil dari query ?user_id=
+func setupHubServer(t *testing.T, hub *presence.Hub) *httptest.Server {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, _ := strconv.Atoi(r.URL.Query().Get("user_id"))
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		hub.Serve(conn, presence.User{ID: uint(id), Email: "user" + strconv.Itoa(id) + "@example.com"})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func dial(t *testing.T, server *httptest.Server, userID int) *websocket.Conn {
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/?user_id=" + strconv.Itoa(userID)
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// readUntil membaca pesan hingga menemukan pesan yang memenuhi kondisi
+func readUntil(t *testing.T, conn *websocket.Conn, match func(presence.ServerMessage) bool) presence.ServerMessage {
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		var msg presence.ServerMessage
+		require.NoError(t, conn.ReadJSON(&msg))
+		if match(msg) {
+			return msg
+		}
+	}
+}
+
+func TestHub_PresenceAndChangeNotifications(t *testing.T) {
+	bus := events.NewBus(10)
+	hub := presence.NewHub(bus)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+
+	server := setupHubServer(t, hub)
+	alice := dial(t, server, 1)
+	bob := dial(t, server, 2)
+
+	// 1. Alice melihat produk 5, Bob mengeditnya
+	require.NoError(t, alice.WriteJSON(presence.ClientMessage{Type: presence.MessageSubscribe, ProductID: 5}))
+	readUntil(t, alice, func(m presence.ServerMessage) bool { return m.Type == presence.MessagePresence })
+
+	require.NoError(t, bob.WriteJSON(presence.ClientMessage{Type: presence.MessageSubscribe, ProductID: 5, Mode: presence.ModeEditing}))
+	msg := readUntil(t, alice, func(m presence.ServerMessage) bool {
+		return m.Type == presence.MessagePresence && len(m.Users) == 2
+	})
+	assert.Equal(t, presence.ModeViewing, msg.Users[0].Mode)
+	assert.Equal(t, presence.ModeEditing, msg.Users[1].Mode)
+
+	// 2. Perubahan produk diteruskan ke ruang produk tersebut
+	require.NoError(t, bus.Publish(events.ProductUpdated, 5, map[string]string{"name": "Baru"}))
+	msg = readUntil(t, bob, func(m presence.ServerMessage) bool { return m.Type == events.ProductUpdated })
+	assert.Equal(t, uint(5), msg.ProductID)
+	assert.JSONEq(t, `{"name":"Baru"}`, string(msg.Data))
+
+	// 3. Koneksi yang ditutup dibersihkan dari daftar kehadiran
+	bob.Close()
+	msg = readUntil(t, alice, func(m presence.ServerMessage) bool {
+		return m.Type == presence.MessagePresence && len(m.Users) == 1
+	})
+	assert.Equal(t, uint(1), msg.Users[0].ID)
+	assert.Eventually(t, func() bool { return len(hub.Viewers(5)) == 1 }, time.Second, 10*time.Millisecond)
+}
+
+func TestHub_InvalidMessage(t *testing.T) {
+	hub := presence.NewHub(nil)
+	server := setupHubServer(t, hub)
+	conn := dial(t, server, 1)
+
+	require.NoError(t, conn.WriteJSON(presence.ClientMessage{Type: presence.MessageSubscribe, ProductID: 1, Mode: "menari"}))
+	msg := readUntil(t, conn, func(m presence.ServerMessage) bool { return m.Type == presence.MessageError })
+	assert.Contains(t, msg.Error, "mode")
+	assert.Empty(t, hub.Viewers(1))
+}
+
+func TestHub_ClosesConnectionWhenCheckFails(t *testing.T) {
+	hub := presence.NewHub(nil)
+	hub.RecheckInterval = 10 * time.Millisecond
+
+	var revoked atomic.Bool
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		hub.ServeWithCheck(conn, presence.User{ID: 1, Email: "user1@example.com"}, func() error {
+			if revoked.Load() {
+				return errors.New("sesi sudah dicabut")
+			}
+			return nil
+		})
+	}))
+	t.Cleanup(server.Close)
+	conn := dial(t, server, 1)
+
+	// Selama check lolos, koneksi tetap dipakai seperti biasa
+	require.NoError(t, conn.WriteJSON(presence.ClientMessage{Type: presence.MessageSubscribe, ProductID: 3}))
+	readUntil(t, conn, func(m presence.ServerMessage) bool { return m.Type == presence.MessagePresence })
+	time.Sleep(30 * time.Millisecond)
+	assert.Len(t, hub.Viewers(3), 1)
+
+	// Setelah sesi dicabut, server menutup koneksi dengan close frame 1008
+	revoked.Store(true)
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var closeErr *websocket.CloseError
+	for {
+		var msg presence.ServerMessage
+		err := conn.ReadJSON(&msg)
+		if err == nil {
+			continue
+		}
+		require.ErrorAs(t, err, &closeErr)
+		break
+	}
+	assert.Equal(t, websocket.ClosePolicyViolation, closeErr.Code)
+	assert.Equal(t, "sesi sudah dicabut", closeErr.Text)
+	assert.Eventually(t, func() bool { return len(hub.Viewers(3)) == 0 }, time.Second, 10*time.Millisecond)
+}
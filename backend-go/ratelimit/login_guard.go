@@ -0,0 +1,110 @@
+package ratelimit
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Bawaan jeda progresif LoginGuard
+const (
+	DefaultDelayAfter = 2
+	DefaultBaseDelay  = 500 * time.Millisecond
+	DefaultMaxDelay   = 4 * time.Second
+)
+
+// LoginGuard melindungi satu akun (email) dari tebakan password yang datang dari banyak IP:
+//   - setiap percobaan mengambil token dari bucket per akun (PerAccount);
+//   - setelah DelayAfter kegagalan, response login gagal ditahan dengan jeda yang berlipat
+//     dua setiap kegagalan (BaseDelay, 2×BaseDelay, ... maksimal MaxDelay);
+//   - LockoutThreshold kegagalan dalam LockoutDuration mengunci akun selama LockoutDuration,
+//     termasuk untuk password yang benar.
+//
+// Login berhasil mengosongkan hitungan kegagalan. Email yang tidak terdaftar diperlakukan
+// sama agar respons tidak membocorkan akun mana yang ada.
+type LoginGuard struct {
+	Store            Store
+	PerAccount       Limit
+	LockoutThreshold int
+	LockoutDuration  time.Duration
+
+	DelayAfter int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+}
+
+// NewLoginGuard adalah konstruktor untuk LoginGuard dengan jeda progresif bawaan
+func NewLoginGuard(store Store, perAccount Limit, lockoutThreshold int, lockoutDuration time.Duration) *LoginGuard {
+	return &LoginGuard{
+		Store:            store,
+		PerAccount:       perAccount,
+		LockoutThreshold: lockoutThreshold,
+		LockoutDuration:  lockoutDuration,
+		DelayAfter:       DefaultDelayAfter,
+		BaseDelay:        DefaultBaseDelay,
+		MaxDelay:         DefaultMaxDelay,
+	}
+}
+
+// Allow memeriksa kunci akun dan bucket per akun sebelum password diverifikasi.
+// retryAfter lebih besar dari nol berarti percobaan ditolak.
+func (g *LoginGuard) Allow(ctx context.Context, account string) (time.Duration, error) {
+	account = normalizeAccount(account)
+	locked, err := g.Store.TTL(ctx, lockKey(account))
+	if err != nil || locked > 0 {
+		return locked, err
+	}
+
+	result, err := g.Store.Take(ctx, "login:account:"+account, g.PerAccount)
+	if err != nil {
+		return 0, err
+	}
+	return result.RetryAfter, nil
+}
+
+// Failed mencatat login gagal, mengunci akun jika ambang tercapai, dan mengembalikan
+// jeda yang harus diterapkan sebelum response dikirim
+func (g *LoginGuard) Failed(ctx context.Context, account string) (time.Duration, error) {
+	account = normalizeAccount(account)
+	failures, err := g.Store.Increment(ctx, failuresKey(account), g.LockoutDuration)
+	if err != nil {
+		return 0, err
+	}
+
+	if failures >= g.LockoutThreshold {
+		if _, err := g.Store.Increment(ctx, lockKey(account), g.LockoutDuration); err != nil {
+			return 0, err
+		}
+		// Hitungan mulai dari nol lagi setelah kunci berakhir
+		if err := g.Store.Delete(ctx, failuresKey(account)); err != nil {
+			return 0, err
+		}
+	}
+	return g.delay(failures), nil
+}
+
+// Succeeded mengosongkan hitungan kegagalan akun
+func (g *LoginGuard) Succeeded(ctx context.Context, account string) error {
+	return g.Store.Delete(ctx, failuresKey(normalizeAccount(account)))
+}
+
+// delay menghitung jeda progresif untuk kegagalan ke-n
+func (g *LoginGuard) delay(failures int) time.Duration {
+	if failures <= g.DelayAfter || g.BaseDelay <= 0 {
+		return 0
+	}
+	d := g.BaseDelay
+	for i := g.DelayAfter + 1; i < failures && d < g.MaxDelay; i++ {
+		d *= 2
+	}
+	return min(d, g.MaxDelay)
+}
+
+func failuresKey(account string) string { return "login:failures:" + account }
+func lockKey(account string) string     { return "login:lock:" + account }
+
+// normalizeAccount menyamakan variasi penulisan email agar tidak bisa dipakai
+// untuk melewati batas per akun
+func normalizeAccount(account string) string {
+	return strings.ToLower(strings.TrimSpace(account))
+}
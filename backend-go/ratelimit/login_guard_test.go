@@ -0,0 +1,97 @@
+package ratelimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"fullstack-crud-project-01/backend-go/ratelimit"
+)
+
+func TestLoginGuard_DelayAndLockout(t *testing.T) {
+	store, clock := newStore()
+	ctx := context.Background()
+	guard := ratelimit.NewLoginGuard(store, ratelimit.Limit{Burst: 100, Period: time.Minute}, 5, 15*time.Minute)
+
+	// Jeda mulai setelah DelayAfter (2) kegagalan dan berlipat dua
+	for _, want := range []time.Duration{0, 0, 500 * time.Millisecond, time.Second} {
+		retryAfter, err := guard.Allow(ctx, "Korban@Example.com")
+		require.NoError(t, err)
+		require.Zero(t, retryAfter)
+
+		delay, err := guard.Failed(ctx, "korban@example.com ")
+		require.NoError(t, err)
+		assert.Equal(t, want, delay)
+	}
+
+	// Kegagalan ke-5 mengunci akun, termasuk untuk variasi huruf besar/kecil
+	_, err := guard.Failed(ctx, "korban@example.com")
+	require.NoError(t, err)
+	retryAfter, err := guard.Allow(ctx, "KORBAN@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 15*time.Minute, retryAfter)
+
+	other, err := guard.Allow(ctx, "lain@example.com")
+	require.NoError(t, err)
+	assert.Zero(t, other, "Akun lain tidak ikut terkunci")
+
+	clock.Advance(15 * time.Minute)
+	retryAfter, err = guard.Allow(ctx, "korban@example.com")
+	require.NoError(t, err)
+	assert.Zero(t, retryAfter, "Kunci berakhir setelah LockoutDuration")
+
+	delay, err := guard.Failed(ctx, "korban@example.com")
+	require.NoError(t, err)
+	assert.Zero(t, delay, "Hitungan kegagalan dimulai dari awal setelah penguncian")
+}
+
+func TestLoginGuard_SuccessResetsFailures(t *testing.T) {
+	store, _ := newStore()
+	ctx := context.Background()
+	guard := ratelimit.NewLoginGuard(store, ratelimit.Limit{Burst: 100, Period: time.Minute}, 3, time.Minute)
+
+	for range 2 {
+		_, err := guard.Failed(ctx, "user@example.com")
+		require.NoError(t, err)
+	}
+	require.NoError(t, guard.Succeeded(ctx, "user@example.com"))
+	for range 2 {
+		_, err := guard.Failed(ctx, "user@example.com")
+		require.NoError(t, err)
+	}
+
+	retryAfter, err := guard.Allow(ctx, "user@example.com")
+	require.NoError(t, err)
+	assert.Zero(t, retryAfter, "Kegagalan sebelum login berhasil tidak dihitung")
+}
+
+func TestLoginGuard_MaxDelay(t *testing.T) {
+	store, _ := newStore()
+	guard := ratelimit.NewLoginGuard(store, ratelimit.Limit{Burst: 100, Period: time.Minute}, 100, time.Minute)
+
+	var delay time.Duration
+	for range 20 {
+		var err error
+		delay, err = guard.Failed(context.Background(), "user@example.com")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, ratelimit.DefaultMaxDelay, delay)
+}
+
+func TestLoginGuard_PerAccountLimit(t *testing.T) {
+	store, _ := newStore()
+	ctx := context.Background()
+	guard := ratelimit.NewLoginGuard(store, ratelimit.Limit{Burst: 2, Period: time.Minute}, 10, time.Minute)
+
+	for range 2 {
+		retryAfter, err := guard.Allow(ctx, "user@example.com")
+		require.NoError(t, err)
+		require.Zero(t, retryAfter)
+	}
+	retryAfter, err := guard.Allow(ctx, "user@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, retryAfter, "Token berikutnya tersedia setelah Period/Burst")
+}
@@ -0,0 +1,138 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval adalah jarak minimum antar pembersihan kunci kedaluwarsa
+const sweepInterval = time.Minute
+
+// MemoryStore adalah Store in-memory untuk satu instance. Batas tidak dibagi antar
+// replika dan hilang saat restart.
+type MemoryStore struct {
+	Now func() time.Time // Opsional: sumber waktu untuk tes, default time.Now
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	counters  map[string]*counter
+	nextSweep time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	expires time.Time // saat bucket penuh kembali dan boleh dibuang
+}
+
+type counter struct {
+	value   int
+	expires time.Time
+}
+
+// NewMemoryStore adalah konstruktor untuk MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:  make(map[string]*bucket),
+		counters: make(map[string]*counter),
+	}
+}
+
+// Take memenuhi Store dengan token bucket yang diisi ulang secara kontinu
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.sweep(now)
+
+	interval := limit.Interval()
+	capacity := float64(limit.Burst)
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, updated: now}
+		s.buckets[key] = b
+	}
+	b.tokens = math.Min(capacity, b.tokens+float64(now.Sub(b.updated))/float64(interval))
+	b.updated = now
+
+	result := Result{Limit: limit.Burst}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - b.tokens) * float64(interval))
+	}
+	result.Remaining = int(b.tokens)
+	result.Reset = time.Duration((capacity - b.tokens) * float64(interval))
+	b.expires = now.Add(result.Reset)
+	return result, nil
+}
+
+// Increment memenuhi Store
+func (s *MemoryStore) Increment(_ context.Context, key string, ttl time.Duration) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.sweep(now)
+
+	c, ok := s.counters[key]
+	if !ok || !now.Before(c.expires) {
+		c = &counter{expires: now.Add(ttl)}
+		s.counters[key] = c
+	}
+	c.value++
+	return c.value, nil
+}
+
+// TTL memenuhi Store
+func (s *MemoryStore) TTL(_ context.Context, key string) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if c, ok := s.counters[key]; ok {
+		if ttl := c.expires.Sub(s.now()); ttl > 0 {
+			return ttl, nil
+		}
+	}
+	return 0, nil
+}
+
+// Delete memenuhi Store
+func (s *MemoryStore) Delete(_ context.Context, keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range keys {
+		delete(s.buckets, key)
+		delete(s.counters, key)
+	}
+	return nil
+}
+
+// sweep membuang bucket yang sudah penuh kembali dan counter kedaluwarsa agar memori
+// tidak tumbuh terus oleh kunci (mis. IP) yang hanya muncul sekali
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Before(s.nextSweep) {
+		return
+	}
+	s.nextSweep = now.Add(sweepInterval)
+	for key, b := range s.buckets {
+		if !now.Before(b.expires) {
+			delete(s.buckets, key)
+		}
+	}
+	for key, c := range s.counters {
+		if !now.Before(c.expires) {
+			delete(s.counters, key)
+		}
+	}
+}
+
+func (s *MemoryStore) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,119 @@
+package ratelimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"fullstack-crud-project-01/backend-go/ratelimit"
+)
+
+// fakeClock adalah sumber waktu yang dimajukan manual oleh tes
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time          { return c.now }
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func newStore() (*ratelimit.MemoryStore, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	store := ratelimit.NewMemoryStore()
+	store.Now = clock.Now
+	return store, clock
+}
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    ratelimit.Limit
+		wantErr bool
+	}{
+		{"20/1m", ratelimit.Limit{Burst: 20, Period: time.Minute}, false},
+		{" 5 / 1h ", ratelimit.Limit{Burst: 5, Period: time.Hour}, false},
+		{"20", ratelimit.Limit{}, true},
+		{"0/1m", ratelimit.Limit{}, true},
+		{"x/1m", ratelimit.Limit{}, true},
+		{"20/sebentar", ratelimit.Limit{}, true},
+		{"20/-1m", ratelimit.Limit{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			limit, err := ratelimit.ParseLimit(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, limit)
+		})
+	}
+}
+
+func TestMemoryStore_Take(t *testing.T) {
+	store, clock := newStore()
+	ctx := context.Background()
+	limit := ratelimit.Limit{Burst: 3, Period: 3 * time.Second} // satu token per detik
+
+	for i := 2; i >= 0; i-- {
+		result, err := store.Take(ctx, "ip:1", limit)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 3, result.Limit)
+		assert.Equal(t, i, result.Remaining)
+	}
+
+	result, err := store.Take(ctx, "ip:1", limit)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed, "Burst habis")
+	assert.Equal(t, time.Second, result.RetryAfter)
+	assert.Equal(t, 3*time.Second, result.Reset)
+
+	other, err := store.Take(ctx, "ip:2", limit)
+	require.NoError(t, err)
+	assert.True(t, other.Allowed, "Setiap kunci punya bucket sendiri")
+
+	clock.Advance(1500 * time.Millisecond)
+	result, err = store.Take(ctx, "ip:1", limit)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed, "Satu token terisi setelah satu detik")
+	assert.Equal(t, 0, result.Remaining)
+
+	clock.Advance(time.Hour)
+	result, err = store.Take(ctx, "ip:1", limit)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Remaining, "Bucket tidak terisi melebihi kapasitas")
+}
+
+func TestMemoryStore_Counters(t *testing.T) {
+	store, clock := newStore()
+	ctx := context.Background()
+
+	for want := 1; want <= 3; want++ {
+		n, err := store.Increment(ctx, "gagal", time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, want, n)
+		clock.Advance(10 * time.Second)
+	}
+
+	ttl, err := store.TTL(ctx, "gagal")
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, ttl, "TTL tidak diperpanjang oleh increment berikutnya")
+
+	clock.Advance(30 * time.Second)
+	ttl, err = store.TTL(ctx, "gagal")
+	require.NoError(t, err)
+	assert.Zero(t, ttl, "Counter kedaluwarsa")
+	n, err := store.Increment(ctx, "gagal", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n, "Counter kedaluwarsa dimulai dari awal")
+
+	require.NoError(t, store.Delete(ctx, "gagal", "tidak-ada"))
+	ttl, err = store.TTL(ctx, "gagal")
+	require.NoError(t, err)
+	assert.Zero(t, ttl)
+}
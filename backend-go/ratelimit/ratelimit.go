@@ -0,0 +1,79 @@
+// Package ratelimit membatasi laju permintaan dengan token bucket dan melindungi login
+// dari tebakan password (jeda progresif dan penguncian akun sementara).
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit adalah token bucket berkapasitas Burst yang terisi penuh kembali dalam Period,
+// mis. 20/1m: maksimal 20 permintaan beruntun, lalu satu permintaan setiap 3 detik.
+type Limit struct {
+	Burst  int
+	Period time.Duration
+}
+
+// ParseLimit mem-parse format "<burst>/<period>", mis. "20/1m" atau "5/1h"
+func ParseLimit(value string) (Limit, error) {
+	burst, period, ok := strings.Cut(value, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("batas %q harus berformat <jumlah>/<durasi>, mis. 20/1m", value)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(burst))
+	if err != nil || n <= 0 {
+		return Limit{}, fmt.Errorf("jumlah pada batas %q harus bilangan bulat positif", value)
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(period))
+	if err != nil || d <= 0 {
+		return Limit{}, fmt.Errorf("durasi pada batas %q tidak valid", value)
+	}
+	return Limit{Burst: n, Period: d}, nil
+}
+
+// String mengembalikan format yang sama dengan ParseLimit
+func (l Limit) String() string {
+	return fmt.Sprintf("%d/%s", l.Burst, l.Period)
+}
+
+// Interval adalah waktu untuk mengisi satu token
+func (l Limit) Interval() time.Duration {
+	return l.Period / time.Duration(l.Burst)
+}
+
+// Result adalah hasil mengambil satu token dari bucket
+type Result struct {
+	Allowed    bool
+	Limit      int           // kapasitas bucket
+	Remaining  int           // token yang tersisa setelah permintaan ini
+	RetryAfter time.Duration // waktu tunggu sampai satu token tersedia; nol jika diizinkan
+	Reset      time.Duration // waktu sampai bucket penuh kembali
+}
+
+// Store menyimpan bucket dan counter. Setiap method harus atomik per kunci agar aman
+// dipakai beberapa instance sekaligus. MemoryStore cukup untuk satu instance; untuk
+// beberapa replika, implementasikan Store di atas Redis: Take sebagai skrip Lua
+// (HGET/HSET token dan waktu, PEXPIRE sebesar Reset), Increment sebagai INCR lalu
+// PEXPIRE ... NX, TTL sebagai PTTL dan Delete sebagai DEL.
+type Store interface {
+	// Take mengambil satu token dari bucket kunci tersebut
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+	// Increment menaikkan counter dan mengembalikan nilai barunya. TTL hanya
+	// ditetapkan saat counter dibuat sehingga jendela waktunya tetap.
+	Increment(ctx context.Context, key string, ttl time.Duration) (int, error)
+	// TTL mengembalikan sisa umur counter; nol jika counter tidak ada
+	TTL(ctx context.Context, key string) (time.Duration, error)
+	// Delete menghapus kunci; kunci yang tidak ada diabaikan
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// Seconds membulatkan durasi ke atas dalam detik untuk header Retry-After dan RateLimit-Reset
+func Seconds(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return int((d + time.Second - 1) / time.Second)
+}
@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/services"
+	"gorm.io/gorm"
+)
+
+// APIKeyRepositoryImpl adalah implementasi GORM dari services.APIKeyRepository
+type APIKeyRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+// NewAPIKeyRepository adalah konstruktor untuk APIKeyRepositoryImpl
+func NewAPIKeyRepository(db *gorm.DB) services.APIKeyRepository {
+	return &APIKeyRepositoryImpl{DB: db}
+}
+
+// Create menyimpan API key baru
+func (r *APIKeyRepositoryImpl) Create(ctx context.Context, key *models.APIKey) error {
+	return r.DB.WithContext(ctx).Create(key).Error
+}
+
+// FindByPrefix mencari kunci berdasarkan prefix yang terlihat (unik)
+func (r *APIKeyRepositoryImpl) FindByPrefix(ctx context.Context, prefix string) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.DB.WithContext(ctx).Where("prefix = ?", prefix).First(&key).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// FindForUser mencari kunci berdasarkan ID yang dimiliki pengguna tertentu
+func (r *APIKeyRepositoryImpl) FindForUser(ctx context.Context, userID, id uint) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.DB.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&key).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// ListByUser mengambil semua kunci milik pengguna, terbaru lebih dulu
+func (r *APIKeyRepositoryImpl) ListByUser(ctx context.Context, userID uint) ([]models.APIKey, error) {
+	var keys []models.APIKey
+	err := r.DB.WithContext(ctx).Where("user_id = ?", userID).Order("id DESC").Find(&keys).Error
+	return keys, err
+}
+
+// Delete menghapus kunci berdasarkan ID
+func (r *APIKeyRepositoryImpl) Delete(ctx context.Context, id uint) error {
+	return r.DB.WithContext(ctx).Delete(&models.APIKey{}, id).Error
+}
+
+// TouchLastUsed memperbarui waktu pemakaian terakhir tanpa menyentuh kolom lain
+func (r *APIKeyRepositoryImpl) TouchLastUsed(ctx context.Context, id uint, at time.Time) error {
+	return r.DB.WithContext(ctx).Model(&models.APIKey{}).Where("id = ?", id).Update("last_used_at", at).Error
+}
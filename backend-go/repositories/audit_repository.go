@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"context"
+
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/services"
+	"gorm.io/gorm"
+)
+
+// AuditRepositoryImpl adalah implementasi GORM dari services.AuditRepository
+type AuditRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+// NewAuditRepository adalah konstruktor untuk AuditRepositoryImpl
+func NewAuditRepository(db *gorm.DB) services.AuditRepository {
+	return &AuditRepositoryImpl{DB: db}
+}
+
+// Create menyimpan satu entri audit
+func (r *AuditRepositoryImpl) Create(ctx context.Context, entry *models.AuditLog) error {
+	return r.DB.WithContext(ctx).Create(entry).Error
+}
+
+// Find mengambil audit log sesuai filter, diurutkan dari yang terbaru
+func (r *AuditRepositoryImpl) Find(ctx context.Context, filter services.AuditFilter) ([]models.AuditLog, error) {
+	query := r.DB.WithContext(ctx).Model(&models.AuditLog{})
+	if filter.ActorID != nil {
+		query = query.Where("actor_id = ?", *filter.ActorID)
+	}
+	if filter.Resource != "" {
+		query = query.Where("resource = ?", filter.Resource)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at <= ?", *filter.To)
+	}
+
+	var logs []models.AuditLog
+	result := query.Order("created_at DESC, id DESC").Limit(filter.Limit).Find(&logs)
+	return logs, result.Error
+}
@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/services"
+	"gorm.io/gorm"
+)
+
+// MemoryProductRepository adalah implementasi services.ProductRepository yang menyimpan
+// produk di memori. Aman dipakai dari banyak goroutine dan meniru perilaku
+// ProductRepositoryImpl: ID auto-increment, timestamp otomatis, dan gorm.ErrRecordNotFound
+// untuk produk yang tidak ada. Dipakai untuk tes cepat dan mode demo tanpa database.
+// Event produk tidak ditulis ke outbox; gunakan ProductService.Events untuk publikasi.
+type MemoryProductRepository struct {
+	mu       sync.RWMutex
+	products map[uint]models.Product
+	nextID   uint
+	Now      func() time.Time // Dapat diganti di tes
+}
+
+// NewMemoryProductRepository adalah konstruktor untuk MemoryProductRepository
+func NewMemoryProductRepository() services.ProductRepository {
+	return &MemoryProductRepository{
+		products: make(map[uint]models.Product),
+		nextID:   1,
+		Now:      time.Now,
+	}
+}
+
+// Create menyimpan produk baru. ID diisi otomatis jika kosong; ID yang sudah dipakai
+// menghasilkan gorm.ErrDuplicatedKey.
+func (r *MemoryProductRepository) Create(ctx context.Context, product *models.Product) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.create(product)
+}
+
+// create menyimpan produk baru; pemanggil wajib memegang r.mu
+func (r *MemoryProductRepository) create(product *models.Product) error {
+	if product.ID == 0 {
+		product.ID = r.nextID
+	} else if _, exists := r.products[product.ID]; exists {
+		return gorm.ErrDuplicatedKey
+	}
+	if product.ID >= r.nextID {
+		r.nextID = product.ID + 1
+	}
+
+	// Sama seperti GORM: timestamp hanya diisi jika masih kosong
+	now := r.Now()
+	if product.CreatedAt.IsZero() {
+		product.CreatedAt = now
+	}
+	if product.UpdatedAt.IsZero() {
+		product.UpdatedAt = now
+	}
+	r.products[product.ID] = *product
+	return nil
+}
+
+// ReadAll mengembalikan semua produk terurut berdasarkan ID
+func (r *MemoryProductRepository) ReadAll(ctx context.Context) ([]models.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	products := make([]models.Product, 0, len(r.products))
+	for _, p := range r.products {
+		products = append(products, p)
+	}
+	sort.Slice(products, func(i, j int) bool { return products[i].ID < products[j].ID })
+	return products, nil
+}
+
+// ReadByID mendapatkan produk berdasarkan ID
+func (r *MemoryProductRepository) ReadByID(ctx context.Context, id uint) (*models.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return &models.Product{}, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	product, ok := r.products[id]
+	if !ok {
+		return &models.Product{}, gorm.ErrRecordNotFound
+	}
+	return &product, nil
+}
+
+// Update menyimpan seluruh field produk seperti gorm.DB.Save: UpdatedAt diperbarui,
+// dan produk yang belum ada akan dibuat.
+func (r *MemoryProductRepository) Update(ctx context.Context, product *models.Product) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.products[product.ID]; !exists || product.ID == 0 {
+		return r.create(product)
+	}
+
+	product.UpdatedAt = r.Now()
+	r.products[product.ID] = *product
+	return nil
+}
+
+// Delete menghapus produk berdasarkan ID. Menghapus produk yang tidak ada bukan error.
+func (r *MemoryProductRepository) Delete(ctx context.Context, id uint) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.products, id)
+	return nil
+}
@@ -0,0 +1,15 @@
+package repositories_test
+
+import (
+	"testing"
+
+	"fullstack-crud-project-01/backend-go/repositories"
+	"fullstack-crud-project-01/backend-go/repositories/repotest"
+	"fullstack-crud-project-01/backend-go/services"
+)
+
+func TestMemoryProductRepository_Contract(t *testing.T) {
+	repotest.RunProductRepositoryContract(t, func(t *testing.T) services.ProductRepository {
+		return repositories.NewMemoryProductRepository()
+	})
+}
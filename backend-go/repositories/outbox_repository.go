@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"time"
+
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/outbox"
+	"gorm.io/gorm"
+)
+
+// OutboxRepositoryImpl adalah implementasi GORM dari outbox.Store
+type OutboxRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+// NewOutboxRepository adalah konstruktor untuk OutboxRepositoryImpl
+func NewOutboxRepository(db *gorm.DB) outbox.Store {
+	return &OutboxRepositoryImpl{DB: db}
+}
+
+// Pending mengambil pesan yang belum dipublikasikan dan sudah jatuh tempo sesuai urutan
+// penulisan. Aggregate yang pesan sebelumnya masih menunggu jadwal retry dilewati seluruhnya,
+// sehingga pesan berikutnya tidak terkirim mendahuluinya dan tidak memenuhi batch.
+func (r *OutboxRepositoryImpl) Pending(now time.Time, limit int) ([]models.OutboxMessage, error) {
+	var messages []models.OutboxMessage
+	result := r.DB.
+		Where("published_at IS NULL AND dead_letter_at IS NULL").
+		Where("next_attempt_at IS NULL OR next_attempt_at <= ?", now).
+		Where(`NOT EXISTS (
+			SELECT 1 FROM outbox_messages waiting
+			WHERE waiting.aggregate_type = outbox_messages.aggregate_type
+				AND waiting.aggregate_id = outbox_messages.aggregate_id
+				AND waiting.id < outbox_messages.id
+				AND waiting.published_at IS NULL
+				AND waiting.dead_letter_at IS NULL
+				AND waiting.next_attempt_at > ?
+		)`, now).
+		Order("id").Limit(limit).Find(&messages)
+	return messages, result.Error
+}
+
+// MarkPublished menandai pesan sudah berhasil dipublikasikan
+func (r *OutboxRepositoryImpl) MarkPublished(id uint) error {
+	return r.DB.Model(&models.OutboxMessage{}).Where("id = ?", id).Update("published_at", time.Now()).Error
+}
+
+// MarkFailed mencatat percobaan publikasi yang gagal beserta jadwal percobaan berikutnya
+func (r *OutboxRepositoryImpl) MarkFailed(id uint, reason string, nextAttemptAt time.Time) error {
+	return r.DB.Model(&models.OutboxMessage{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"attempts":        gorm.Expr("attempts + 1"),
+		"last_error":      reason,
+		"next_attempt_at": nextAttemptAt,
+	}).Error
+}
+
+// MarkDeadLettered mencatat percobaan terakhir yang gagal dan menghentikan percobaan ulang
+func (r *OutboxRepositoryImpl) MarkDeadLettered(id uint, reason string) error {
+	return r.DB.Model(&models.OutboxMessage{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"attempts":        gorm.Expr("attempts + 1"),
+		"last_error":      reason,
+		"next_attempt_at": nil,
+		"dead_letter_at":  time.Now(),
+	}).Error
+}
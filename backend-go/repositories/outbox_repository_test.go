@@ -0,0 +1,55 @@
+package repositories_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"fullstack-crud-project-01/backend-go/events"
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/outbox"
+	"fullstack-crud-project-01/backend-go/repositories"
+)
+
+func pendingIDs(t *testing.T, store outbox.Store, now time.Time) []uint {
+	t.Helper()
+	messages, err := store.Pending(now, 100)
+	require.NoError(t, err)
+	ids := make([]uint, 0, len(messages))
+	for _, msg := range messages {
+		ids = append(ids, msg.AggregateID)
+	}
+	return ids
+}
+
+func TestOutboxRepository_PendingSkipsWaitingAggregates(t *testing.T) {
+	setupTest(t)
+	store := repositories.NewOutboxRepository(testDB)
+
+	// Produk 1 punya dua pesan, produk 2 satu pesan
+	for _, aggregateID := range []uint{1, 2, 1} {
+		require.NoError(t, outbox.Write(testDB, models.AggregateProduct, aggregateID, events.ProductUpdated, map[string]uint{"id": aggregateID}))
+	}
+	messages, err := store.Pending(time.Now(), 100)
+	require.NoError(t, err)
+	require.Len(t, messages, 3)
+	head := messages[0]
+
+	// Pesan pertama produk 1 gagal: seluruh produk 1 dilewati sampai jadwal retry tiba
+	now := time.Now()
+	require.NoError(t, store.MarkFailed(head.ID, "broker tidak tersedia", now.Add(time.Minute)))
+	assert.Equal(t, []uint{2}, pendingIDs(t, store, now))
+	assert.Equal(t, []uint{1, 2, 1}, pendingIDs(t, store, now.Add(2*time.Minute)))
+
+	// Dead letter tidak dicoba lagi dan tidak menahan pesan berikutnya
+	require.NoError(t, store.MarkDeadLettered(head.ID, "broker tidak tersedia"))
+	assert.Equal(t, []uint{2, 1}, pendingIDs(t, store, now))
+
+	var stored models.OutboxMessage
+	require.NoError(t, testDB.First(&stored, head.ID).Error)
+	assert.Equal(t, 2, stored.Attempts)
+	assert.NotNil(t, stored.DeadLetterAt)
+	assert.Nil(t, stored.NextAttemptAt)
+}
@@ -1,7 +1,11 @@
 package repositories
 
 import (
+	"context"
+
+	"fullstack-crud-project-01/backend-go/events"
 	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/outbox"
 	"fullstack-crud-project-01/backend-go/services" // Import paket services
 	"gorm.io/gorm"
 )
@@ -16,34 +20,49 @@ func NewProductRepository(db *gorm.DB) services.ProductRepository {
 	return &ProductRepositoryImpl{DB: db}
 }
 
-// Create menyimpan produk ke database menggunakan GORM
-func (r *ProductRepositoryImpl) Create(product *models.Product) error {
-	result := r.DB.Create(product)
-	return result.Error
+// Create menyimpan produk ke database menggunakan GORM.
+// Event product.created ditulis ke outbox dalam transaksi yang sama.
+func (r *ProductRepositoryImpl) Create(ctx context.Context, product *models.Product) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(product).Error; err != nil {
+			return err
+		}
+		return outbox.Write(tx, models.AggregateProduct, product.ID, events.ProductCreated, product)
+	})
 }
 
-func (r *ProductRepositoryImpl) ReadAll() ([]models.Product, error) {
+func (r *ProductRepositoryImpl) ReadAll(ctx context.Context) ([]models.Product, error) {
 	var products []models.Product
-	result := r.DB.Find(&products)
+	result := r.DB.WithContext(ctx).Find(&products)
 	return products, result.Error
 }
 
 // ReadByID mendapatkan produk berdasarkan ID
-func (r *ProductRepositoryImpl) ReadByID(id uint) (*models.Product, error) {
+func (r *ProductRepositoryImpl) ReadByID(ctx context.Context, id uint) (*models.Product, error) {
 	var product models.Product
-	result := r.DB.First(&product, id)
+	result := r.DB.WithContext(ctx).First(&product, id)
 	return &product, result.Error
 }
 
-// Update menyimpan perubahan produk
-func (r *ProductRepositoryImpl) Update(product *models.Product) error {
-	// Akan melakukan Update hanya pada field yang dimodifikasi, bukan mengganti semua field.
-	result := r.DB.Save(product)
-	return result.Error
+// Update menyimpan perubahan produk beserta event product.updated di outbox
+func (r *ProductRepositoryImpl) Update(ctx context.Context, product *models.Product) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Akan melakukan Update hanya pada field yang dimodifikasi, bukan mengganti semua field.
+		if err := tx.Save(product).Error; err != nil {
+			return err
+		}
+		return outbox.Write(tx, models.AggregateProduct, product.ID, events.ProductUpdated, product)
+	})
 }
 
-// Delete menghapus produk berdasarkan ID (soft delete karena GORM.Model)
-func (r *ProductRepositoryImpl) Delete(id uint) error {
-	result := r.DB.Delete(&models.Product{}, id)
-	return result.Error
-}
\ No newline at end of file
+// Delete menghapus produk berdasarkan ID (soft delete karena GORM.Model).
+// Event product.deleted hanya ditulis jika memang ada baris yang terhapus.
+func (r *ProductRepositoryImpl) Delete(ctx context.Context, id uint) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&models.Product{}, id)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return outbox.Write(tx, models.AggregateProduct, id, events.ProductDeleted, map[string]uint{"id": id})
+	})
+}
@@ -1,6 +1,7 @@
 package repositories_test
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"testing"
@@ -11,6 +12,8 @@ import (
 	"fullstack-crud-project-01/backend-go/config"     
 	"fullstack-crud-project-01/backend-go/models"    
 	"fullstack-crud-project-01/backend-go/repositories" 
+	"fullstack-crud-project-01/backend-go/repositories/repotest"
+	"fullstack-crud-project-01/backend-go/services"
 )
 
 var testDB *gorm.DB
@@ -30,7 +33,10 @@ func TestMain(m *testing.M) {
 	testDB = db
 
 	// Pastikan model sudah dimigrasi ke DB Test
-	testDB.AutoMigrate(&models.Product{}, &models.User{})
+	if err := config.RunMigrations(testDB); err != nil {
+		fmt.Printf("Gagal migrasi DB Test: %v\n", err)
+		os.Exit(1)
+	}
 
 	// --- Jalankan Semua Tes ---
 	code := m.Run()
@@ -43,16 +49,32 @@ func TestMain(m *testing.M) {
 
 // setupTest membersihkan tabel sebelum setiap tes
 func setupTest(t *testing.T) {
-	// Hapus semua data dari tabel Products
+	// Hapus semua data dari tabel Products dan outbox
 	testDB.Exec("DELETE FROM products")
+	testDB.Exec("DELETE FROM outbox_messages")
 	
-	// Reset Auto Increment (Opsional, tapi bagus)
-	testDB.Exec("ALTER TABLE products AUTO_INCREMENT = 1") 
+	// Reset Auto Increment (Opsional, tapi bagus). Sintaksnya berbeda per dialek.
+	switch testDB.Dialector.Name() {
+	case "mysql":
+		testDB.Exec("ALTER TABLE products AUTO_INCREMENT = 1")
+	case "postgres":
+		testDB.Exec("ALTER SEQUENCE products_id_seq RESTART WITH 1")
+	case "sqlite":
+		testDB.Exec("DELETE FROM sqlite_sequence WHERE name = 'products'")
+	}
+}
+
+func TestProductRepository_Contract(t *testing.T) {
+	repotest.RunProductRepositoryContract(t, func(t *testing.T) services.ProductRepository {
+		setupTest(t)
+		return repositories.NewProductRepository(testDB)
+	})
 }
 
 func TestProductRepository_CreateAndFindByID(t *testing.T) {
 	setupTest(t)
 	repo := repositories.NewProductRepository(testDB)
+	ctx := context.Background()
 	
 	product := models.Product{
 		Name:        "Test Laptop",
@@ -61,12 +83,12 @@ func TestProductRepository_CreateAndFindByID(t *testing.T) {
 	}
 
 	// 1. Test Create
-	err := repo.Create(&product)
+	err := repo.Create(ctx, &product)
 	assert.NoError(t, err)
 	assert.NotEqual(t, uint(0), product.ID)
 
 	// 2. Test FindByID
-	foundProduct, err := repo.ReadByID(product.ID)
+	foundProduct, err := repo.ReadByID(ctx, product.ID)
 	assert.NoError(t, err)
 	assert.Equal(t, product.Name, foundProduct.Name)
 }
@@ -74,21 +96,22 @@ func TestProductRepository_CreateAndFindByID(t *testing.T) {
 func TestProductRepository_Update(t *testing.T) {
 	setupTest(t)
 	repo := repositories.NewProductRepository(testDB)
+	ctx := context.Background()
 	
 	// Buat produk awal
 	product := models.Product{Name: "Old Name", Price: 100}
-	repo.Create(&product)
+	repo.Create(ctx, &product)
 
 	// Update data
 	product.Name = "New Name"
 	product.Price = 200
 
 	// 1. Test Update
-	err := repo.Update(&product)
+	err := repo.Update(ctx, &product)
 	assert.NoError(t, err)
 
 	// 2. Verifikasi
-	updatedProduct, _ := repo.ReadByID(product.ID)
+	updatedProduct, _ := repo.ReadByID(ctx, product.ID)
 	assert.Equal(t, "New Name", updatedProduct.Name)
 	assert.Equal(t, 200, updatedProduct.Price)
 }
@@ -96,17 +119,48 @@ func TestProductRepository_Update(t *testing.T) {
 func TestProductRepository_Delete(t *testing.T) {
 	setupTest(t)
 	repo := repositories.NewProductRepository(testDB)
+	ctx := context.Background()
 	
 	// Buat produk awal
 	product := models.Product{Name: "To Delete", Price: 10}
-	repo.Create(&product)
+	repo.Create(ctx, &product)
 
 	// 1. Test Delete
-	err := repo.Delete(product.ID)
+	err := repo.Delete(ctx, product.ID)
 	assert.NoError(t, err)
 
 	// 2. Verifikasi (seharusnya tidak ditemukan)
-	_, err = repo.ReadByID(product.ID)
+	_, err = repo.ReadByID(ctx, product.ID)
 	assert.Error(t, err) // GORM akan mengembalikan error jika record tidak ditemukan
 	assert.Contains(t, err.Error(), "record not found")
-}
\ No newline at end of file
+}
+
+func TestProductRepository_WritesOutboxInSameTransaction(t *testing.T) {
+	setupTest(t)
+	repo := repositories.NewProductRepository(testDB)
+	ctx := context.Background()
+
+	product := models.Product{Name: "Outbox Product", Price: 100}
+	assert.NoError(t, repo.Create(ctx, &product))
+	product.Price = 150
+	assert.NoError(t, repo.Update(ctx, &product))
+	assert.NoError(t, repo.Delete(ctx, product.ID))
+
+	// Setiap perubahan menghasilkan satu pesan outbox, berurutan
+	var messages []models.OutboxMessage
+	testDB.Where("aggregate_id = ?", product.ID).Order("id").Find(&messages)
+	assert.Len(t, messages, 3)
+	assert.Equal(t, []string{"product.created", "product.updated", "product.deleted"},
+		[]string{messages[0].EventType, messages[1].EventType, messages[2].EventType})
+
+	// Penulisan produk yang gagal tidak boleh meninggalkan pesan outbox
+	var before int64
+	testDB.Model(&models.OutboxMessage{}).Count(&before)
+	err := repo.Create(ctx, &models.Product{ID: product.ID + 1000, Name: "", Price: 1})
+	assert.NoError(t, err)
+	err = repo.Create(ctx, &models.Product{ID: product.ID + 1000, Name: "Duplikat", Price: 1})
+	assert.Error(t, err)
+	var after int64
+	testDB.Model(&models.OutboxMessage{}).Count(&after)
+	assert.Equal(t, before+1, after)
+}
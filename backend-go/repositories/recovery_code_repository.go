@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/services"
+	"gorm.io/gorm"
+)
+
+// RecoveryCodeRepositoryImpl adalah implementasi GORM dari services.RecoveryCodeRepository
+type RecoveryCodeRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+// NewRecoveryCodeRepository adalah konstruktor untuk RecoveryCodeRepositoryImpl
+func NewRecoveryCodeRepository(db *gorm.DB) services.RecoveryCodeRepository {
+	return &RecoveryCodeRepositoryImpl{DB: db}
+}
+
+// Replace menghapus kode lama dan menyimpan hash baru dalam satu transaksi
+func (r *RecoveryCodeRepositoryImpl) Replace(ctx context.Context, userID uint, hashes []string) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if len(hashes) == 0 {
+			return nil
+		}
+		now := time.Now()
+		codes := make([]models.RecoveryCode, len(hashes))
+		for i, hash := range hashes {
+			codes[i] = models.RecoveryCode{UserID: userID, CodeHash: hash, CreatedAt: now}
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// Use menandai kode terpakai dengan satu UPDATE bersyarat sehingga kode yang sama
+// tidak bisa dipakai dua kali meskipun request datang bersamaan
+func (r *RecoveryCodeRepositoryImpl) Use(ctx context.Context, userID uint, hash string, usedAt time.Time) (bool, error) {
+	result := r.DB.WithContext(ctx).Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).
+		Update("used_at", usedAt)
+	return result.RowsAffected == 1, result.Error
+}
+
+// CountUnused menghitung kode yang belum dipakai
+func (r *RecoveryCodeRepositoryImpl) CountUnused(ctx context.Context, userID uint) (int, error) {
+	var count int64
+	result := r.DB.WithContext(ctx).Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count)
+	return int(count), result.Error
+}
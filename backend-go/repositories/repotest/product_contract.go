@@ -0,0 +1,167 @@
+// Package repotest berisi test suite kontrak yang wajib dilalui setiap implementasi
+// repository, sehingga implementasi in-memory dan GORM dijamin berperilaku sama.
+package repotest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/services"
+)
+
+// RunProductRepositoryContract menjalankan kontrak services.ProductRepository.
+// newRepo dipanggil untuk setiap subtest dan harus mengembalikan repository yang kosong.
+func RunProductRepositoryContract(t *testing.T, newRepo func(t *testing.T) services.ProductRepository) {
+	t.Run("Create_AssignsIDAndTimestamps", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		first := models.Product{Name: "Pertama", Price: 100}
+		second := models.Product{Name: "Kedua", Price: 200}
+		require.NoError(t, repo.Create(ctx, &first))
+		require.NoError(t, repo.Create(ctx, &second))
+
+		assert.NotZero(t, first.ID)
+		assert.Greater(t, second.ID, first.ID, "ID harus bertambah")
+		assert.False(t, first.CreatedAt.IsZero())
+		assert.False(t, first.UpdatedAt.IsZero())
+	})
+
+	t.Run("Create_DuplicateIDFails", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		product := models.Product{Name: "Asli", Price: 100}
+		require.NoError(t, repo.Create(ctx, &product))
+
+		err := repo.Create(ctx, &models.Product{ID: product.ID, Name: "Duplikat", Price: 1})
+		assert.Error(t, err)
+
+		found, err := repo.ReadByID(ctx, product.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Asli", found.Name, "Produk asli tidak boleh tertimpa")
+	})
+
+	t.Run("ReadByID_ReturnsStoredProduct", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		product := models.Product{Name: "Laptop", Description: "Untuk tes", Price: 5000000}
+		require.NoError(t, repo.Create(ctx, &product))
+
+		found, err := repo.ReadByID(ctx, product.ID)
+		require.NoError(t, err)
+		assert.Equal(t, product.ID, found.ID)
+		assert.Equal(t, "Laptop", found.Name)
+		assert.Equal(t, "Untuk tes", found.Description)
+		assert.Equal(t, 5000000, found.Price)
+	})
+
+	t.Run("ReadByID_NotFound", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.ReadByID(context.Background(), 424242)
+		assert.True(t, errors.Is(err, gorm.ErrRecordNotFound), "Harus gorm.ErrRecordNotFound, didapat: %v", err)
+	})
+
+	t.Run("ReadAll", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		products, err := repo.ReadAll(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, products)
+
+		for _, name := range []string{"A", "B", "C"} {
+			require.NoError(t, repo.Create(ctx, &models.Product{Name: name, Price: 10}))
+		}
+		products, err = repo.ReadAll(ctx)
+		require.NoError(t, err)
+		require.Len(t, products, 3)
+		assert.Equal(t, []string{"A", "B", "C"}, []string{products[0].Name, products[1].Name, products[2].Name})
+	})
+
+	t.Run("Update_PersistsChanges", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		product := models.Product{Name: "Nama Lama", Price: 100}
+		require.NoError(t, repo.Create(ctx, &product))
+		stored, err := repo.ReadByID(ctx, product.ID)
+		require.NoError(t, err)
+
+		product.Name = "Nama Baru"
+		product.Price = 200
+		require.NoError(t, repo.Update(ctx, &product))
+
+		updated, err := repo.ReadByID(ctx, product.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Nama Baru", updated.Name)
+		assert.Equal(t, 200, updated.Price)
+		assert.False(t, updated.UpdatedAt.Before(stored.UpdatedAt), "UpdatedAt tidak boleh mundur")
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		product := models.Product{Name: "Akan Dihapus", Price: 10}
+		require.NoError(t, repo.Create(ctx, &product))
+		require.NoError(t, repo.Delete(ctx, product.ID))
+
+		_, err := repo.ReadByID(ctx, product.ID)
+		assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+
+		// Menghapus produk yang tidak ada bukan error
+		assert.NoError(t, repo.Delete(ctx, product.ID))
+	})
+
+	t.Run("CanceledContext", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		assert.Error(t, repo.Create(ctx, &models.Product{Name: "Batal", Price: 10}))
+		_, err := repo.ReadAll(ctx)
+		assert.Error(t, err)
+
+		products, err := repo.ReadAll(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, products, "Create dengan context batal tidak boleh menyimpan data")
+	})
+
+	t.Run("ConcurrentCreate", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		const n = 20
+		var wg sync.WaitGroup
+		ids := make(chan uint, n)
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				product := models.Product{Name: "Paralel", Price: 1}
+				if assert.NoError(t, repo.Create(ctx, &product)) {
+					ids <- product.ID
+				}
+			}()
+		}
+		wg.Wait()
+		close(ids)
+
+		seen := make(map[uint]bool)
+		for id := range ids {
+			assert.False(t, seen[id], "ID %d dipakai dua kali", id)
+			seen[id] = true
+		}
+		assert.Len(t, seen, n)
+	})
+}
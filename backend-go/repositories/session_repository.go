@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/services"
+	"gorm.io/gorm"
+)
+
+// SessionRepositoryImpl adalah implementasi GORM dari services.SessionRepository
+type SessionRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+// NewSessionRepository adalah konstruktor untuk SessionRepositoryImpl
+func NewSessionRepository(db *gorm.DB) services.SessionRepository {
+	return &SessionRepositoryImpl{DB: db}
+}
+
+// Create menyimpan sesi baru
+func (r *SessionRepositoryImpl) Create(ctx context.Context, session *models.Session) error {
+	return r.DB.WithContext(ctx).Create(session).Error
+}
+
+// FindByTokenID mencari sesi berdasarkan claim jti access token (unik)
+func (r *SessionRepositoryImpl) FindByTokenID(ctx context.Context, tokenID string) (*models.Session, error) {
+	var session models.Session
+	if err := r.DB.WithContext(ctx).Where("token_id = ?", tokenID).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// FindForUser mencari sesi berdasarkan ID yang dimiliki pengguna tertentu
+func (r *SessionRepositoryImpl) FindForUser(ctx context.Context, userID, id uint) (*models.Session, error) {
+	var session models.Session
+	if err := r.DB.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// ListActive mengambil sesi pengguna yang belum dicabut dan belum kedaluwarsa, terbaru lebih dulu
+func (r *SessionRepositoryImpl) ListActive(ctx context.Context, userID uint, now time.Time) ([]models.Session, error) {
+	var sessions []models.Session
+	err := r.DB.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("id DESC").Find(&sessions).Error
+	return sessions, err
+}
+
+// Revoke menandai sesi sebagai dicabut
+func (r *SessionRepositoryImpl) Revoke(ctx context.Context, id uint, at time.Time) error {
+	return r.DB.WithContext(ctx).Model(&models.Session{}).Where("id = ?", id).Update("revoked_at", at).Error
+}
+
+// TouchLastSeen memperbarui waktu aktivitas terakhir tanpa menyentuh kolom lain
+func (r *SessionRepositoryImpl) TouchLastSeen(ctx context.Context, id uint, at time.Time) error {
+	return r.DB.WithContext(ctx).Model(&models.Session{}).Where("id = ?", id).Update("last_seen_at", at).Error
+}
+
+// DeleteInactive menghapus sesi pengguna yang sudah dicabut atau kedaluwarsa
+func (r *SessionRepositoryImpl) DeleteInactive(ctx context.Context, userID uint, now time.Time) error {
+	return r.DB.WithContext(ctx).
+		Where("user_id = ? AND (revoked_at IS NOT NULL OR expires_at <= ?)", userID, now).
+		Delete(&models.Session{}).Error
+}
+
+// RevokeAllForUser mencabut semua sesi aktif pengguna kecuali sesi dengan TokenID exceptTokenID
+func (r *SessionRepositoryImpl) RevokeAllForUser(ctx context.Context, userID uint, exceptTokenID string, at time.Time) error {
+	return r.DB.WithContext(ctx).Model(&models.Session{}).
+		Where("user_id = ? AND token_id <> ? AND revoked_at IS NULL", userID, exceptTokenID).
+		Update("revoked_at", at).Error
+}
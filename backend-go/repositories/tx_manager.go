@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+
+	"fullstack-crud-project-01/backend-go/services"
+)
+
+// Pengaturan default retry transaksi yang gagal karena deadlock
+const (
+	defaultTxMaxRetries   = 3
+	defaultTxRetryBackoff = 50 * time.Millisecond
+)
+
+// txContextKey adalah kunci context untuk transaksi GORM yang sedang aktif
+type txContextKey struct{}
+
+// GormTxManager adalah implementasi GORM dari services.TxManager.
+type GormTxManager struct {
+	DB           *gorm.DB
+	MaxRetries   int
+	RetryBackoff time.Duration
+
+	savepointSeq atomic.Uint64
+}
+
+// NewTxManager adalah konstruktor untuk GormTxManager
+func NewTxManager(db *gorm.DB) *GormTxManager {
+	return &GormTxManager{DB: db, MaxRetries: defaultTxMaxRetries, RetryBackoff: defaultTxRetryBackoff}
+}
+
+// WithinTransaction menjalankan fn di dalam transaksi dengan repository yang terikat padanya.
+// Pemanggilan bersarang (ctx sudah membawa transaksi) memakai SAVEPOINT sehingga kegagalan
+// di dalamnya hanya me-rollback bagian tersebut. Transaksi terluar diulang jika terkena
+// deadlock atau lock wait timeout.
+func (m *GormTxManager) WithinTransaction(ctx context.Context, fn services.TxFunc) error {
+	if tx, ok := ctx.Value(txContextKey{}).(*gorm.DB); ok {
+		return m.withinSavepoint(ctx, tx, fn)
+	}
+
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = m.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return fn(context.WithValue(ctx, txContextKey{}, tx), m.repositories(tx))
+		})
+		if err == nil || attempt >= m.MaxRetries || !IsRetryableTxError(err) {
+			return err
+		}
+
+		// Backoff linear sebelum mencoba lagi; hormati pembatalan context
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(m.RetryBackoff * time.Duration(attempt+1)):
+		}
+	}
+}
+
+func (m *GormTxManager) withinSavepoint(ctx context.Context, tx *gorm.DB, fn services.TxFunc) (err error) {
+	name := fmt.Sprintf("sp_%d", m.savepointSeq.Add(1))
+	if err := tx.SavePoint(name).Error; err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.RollbackTo(name)
+			panic(p)
+		}
+	}()
+
+	if err = fn(ctx, m.repositories(tx)); err != nil {
+		if rbErr := tx.RollbackTo(name).Error; rbErr != nil {
+			return errors.Join(err, rbErr)
+		}
+		return err
+	}
+	return nil
+}
+
+func (m *GormTxManager) repositories(tx *gorm.DB) services.Repositories {
+	return services.Repositories{
+		Products:      NewProductRepository(tx),
+		Audit:         NewAuditRepository(tx),
+		Users:         NewUserRepository(tx),
+		RecoveryCodes: NewRecoveryCodeRepository(tx),
+	}
+}
+
+// IsRetryableTxError mengecek apakah error transaksi layak diulang:
+// deadlock/lock wait timeout MySQL (1213/1205), serialization failure/deadlock
+// PostgreSQL (40001/40P01) dan database terkunci pada SQLite.
+func IsRetryableTxError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == 1213 || mysqlErr.Number == 1205
+	}
+
+	var stateErr interface{ SQLState() string }
+	if errors.As(err, &stateErr) {
+		state := stateErr.SQLState()
+		return state == "40001" || state == "40P01"
+	}
+
+	msg := err.Error()
+	return strings.Contains(msg, "database is locked") || strings.Contains(msg, "SQLITE_BUSY")
+}
@@ -0,0 +1,122 @@
+package repositories_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/repositories"
+	"fullstack-crud-project-01/backend-go/services"
+)
+
+func countProducts() int64 {
+	var count int64
+	testDB.Model(&models.Product{}).Count(&count)
+	return count
+}
+
+func TestTxManager_RollbackOnError(t *testing.T) {
+	setupTest(t)
+	txManager := repositories.NewTxManager(testDB)
+
+	err := txManager.WithinTransaction(context.Background(), func(ctx context.Context, repos services.Repositories) error {
+		if err := repos.Products.Create(ctx, &models.Product{Name: "Rollback", Price: 10}); err != nil {
+			return err
+		}
+		return errors.New("langkah kedua gagal")
+	})
+
+	assert.Error(t, err)
+	assert.Equal(t, int64(0), countProducts(), "Produk harus ikut di-rollback")
+}
+
+func TestTxManager_NestedSavepoint(t *testing.T) {
+	setupTest(t)
+	txManager := repositories.NewTxManager(testDB)
+
+	err := txManager.WithinTransaction(context.Background(), func(ctx context.Context, repos services.Repositories) error {
+		if err := repos.Products.Create(ctx, &models.Product{Name: "Outer", Price: 10}); err != nil {
+			return err
+		}
+
+		// Kegagalan di dalam savepoint hanya me-rollback bagian dalam
+		innerErr := txManager.WithinTransaction(ctx, func(ctx context.Context, repos services.Repositories) error {
+			if err := repos.Products.Create(ctx, &models.Product{Name: "Inner", Price: 20}); err != nil {
+				return err
+			}
+			return errors.New("bagian dalam gagal")
+		})
+		assert.Error(t, innerErr)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	var products []models.Product
+	testDB.Find(&products)
+	assert.Len(t, products, 1)
+	assert.Equal(t, "Outer", products[0].Name)
+}
+
+func TestTxManager_RetryOnDeadlock(t *testing.T) {
+	setupTest(t)
+	txManager := repositories.NewTxManager(testDB)
+	txManager.RetryBackoff = 0
+
+	attempts := 0
+	err := txManager.WithinTransaction(context.Background(), func(ctx context.Context, repos services.Repositories) error {
+		attempts++
+		if err := repos.Products.Create(ctx, &models.Product{Name: "Retry", Price: 10}); err != nil {
+			return err
+		}
+		if attempts == 1 {
+			return &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"}
+		}
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, int64(1), countProducts(), "Percobaan pertama harus di-rollback sebelum diulang")
+}
+
+func TestIsRetryableTxError(t *testing.T) {
+	assert.True(t, repositories.IsRetryableTxError(&mysql.MySQLError{Number: 1213}))
+	assert.True(t, repositories.IsRetryableTxError(&mysql.MySQLError{Number: 1205}))
+	assert.False(t, repositories.IsRetryableTxError(&mysql.MySQLError{Number: 1062}))
+	assert.True(t, repositories.IsRetryableTxError(errors.New("database is locked (5) (SQLITE_BUSY)")))
+	assert.False(t, repositories.IsRetryableTxError(nil))
+}
+
+func TestTxManager_RollsBackUserAndRecoveryCodes(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+	user := &models.User{Email: "mfa-tx@test.com", Name: "MFA", IsActive: true, Role: models.RoleUser}
+	assert.NoError(t, repositories.NewUserRepository(testDB).Create(ctx, user))
+	t.Cleanup(func() {
+		testDB.Exec("DELETE FROM recovery_codes WHERE user_id = ?", user.ID)
+		testDB.Exec("DELETE FROM users WHERE id = ?", user.ID)
+	})
+
+	err := repositories.NewTxManager(testDB).WithinTransaction(ctx, func(ctx context.Context, repos services.Repositories) error {
+		user.TOTPEnabled = true
+		if err := repos.Users.Update(ctx, user); err != nil {
+			return err
+		}
+		if err := repos.RecoveryCodes.Replace(ctx, user.ID, []string{"hash-1", "hash-2"}); err != nil {
+			return err
+		}
+		return errors.New("langkah terakhir gagal")
+	})
+	assert.Error(t, err)
+
+	stored, err := repositories.NewUserRepository(testDB).FindByID(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.False(t, stored.TOTPEnabled, "Status TOTP harus ikut di-rollback")
+	unused, err := repositories.NewRecoveryCodeRepository(testDB).CountUnused(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, unused, "Kode pemulihan harus ikut di-rollback")
+}
@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/services"
+	"gorm.io/gorm"
+)
+
+// UserRepositoryImpl adalah implementasi GORM dari services.UserRepository
+type UserRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+// NewUserRepository adalah konstruktor untuk UserRepositoryImpl
+func NewUserRepository(db *gorm.DB) services.UserRepository {
+	return &UserRepositoryImpl{DB: db}
+}
+
+// FindByID mendapatkan pengguna berdasarkan ID
+func (r *UserRepositoryImpl) FindByID(ctx context.Context, id uint) (*models.User, error) {
+	var user models.User
+	result := r.DB.WithContext(ctx).First(&user, id)
+	return &user, result.Error
+}
+
+// FindByEmail mendapatkan pengguna berdasarkan email
+func (r *UserRepositoryImpl) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	var user models.User
+	result := r.DB.WithContext(ctx).Where("email = ?", email).First(&user)
+	return &user, result.Error
+}
+
+// FindByActivationToken mendapatkan pengguna berdasarkan token aktivasi
+func (r *UserRepositoryImpl) FindByActivationToken(ctx context.Context, token string) (*models.User, error) {
+	var user models.User
+	result := r.DB.WithContext(ctx).Where("activation_token = ?", token).First(&user)
+	return &user, result.Error
+}
+
+// FindByOIDCSubject mendapatkan pengguna yang terhubung ke identitas OIDC (iss + sub)
+func (r *UserRepositoryImpl) FindByOIDCSubject(ctx context.Context, issuer, subject string) (*models.User, error) {
+	var user models.User
+	result := r.DB.WithContext(ctx).Where("oidc_issuer = ? AND oidc_subject = ?", issuer, subject).First(&user)
+	return &user, result.Error
+}
+
+// FindByEmailChangeToken mendapatkan pengguna berdasarkan token konfirmasi email baru
+func (r *UserRepositoryImpl) FindByEmailChangeToken(ctx context.Context, token string) (*models.User, error) {
+	var user models.User
+	result := r.DB.WithContext(ctx).Where("email_change_token = ?", token).First(&user)
+	return &user, result.Error
+}
+
+// FindDueForDeletion mengambil pengguna yang masa tenggang penghapusannya sudah lewat
+func (r *UserRepositoryImpl) FindDueForDeletion(ctx context.Context, now time.Time, limit int) ([]models.User, error) {
+	var users []models.User
+	err := r.DB.WithContext(ctx).Where("deletion_scheduled_at <= ?", now).
+		Order("deletion_scheduled_at").Limit(limit).Find(&users).Error
+	return users, err
+}
+
+// Create menyimpan pengguna baru
+func (r *UserRepositoryImpl) Create(ctx context.Context, user *models.User) error {
+	return r.DB.WithContext(ctx).Create(user).Error
+}
+
+// Update menyimpan perubahan data pengguna
+func (r *UserRepositoryImpl) Update(ctx context.Context, user *models.User) error {
+	return r.DB.WithContext(ctx).Save(user).Error
+}
+
+// UpdateFields menyimpan hanya kolom dari field yang disebut, termasuk nilai kosong/nil
+func (r *UserRepositoryImpl) UpdateFields(ctx context.Context, user *models.User, fields ...string) error {
+	return r.DB.WithContext(ctx).Model(user).Select(fields).Updates(user).Error
+}
+
+// UseTOTPCounter mencatat counter TOTP dengan satu UPDATE bersyarat sehingga kode yang sama
+// tidak bisa dipakai dua kali meskipun request datang bersamaan
+func (r *UserRepositoryImpl) UseTOTPCounter(ctx context.Context, id uint, counter int64, at time.Time) (bool, error) {
+	result := r.DB.WithContext(ctx).Model(&models.User{}).
+		Where("id = ? AND totp_last_counter < ?", id, counter).
+		Updates(map[string]interface{}{"totp_last_counter": counter, "updated_at": at})
+	return result.RowsAffected == 1, result.Error
+}
+
+// DeleteDue menghapus pengguna secara permanen beserta sesi, API key dan kode pemulihannya,
+// jika penghapusannya masih terjadwal pada atau sebelum now. Audit log tetap disimpan sebagai riwayat.
+func (r *UserRepositoryImpl) DeleteDue(ctx context.Context, id uint, now time.Time) (bool, error) {
+	deleted := false
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Hapus baris pengguna lebih dulu dengan syarat jadwal: pembatalan yang terjadi bersamaan
+		// membuat baris tidak cocok dan data terkait tidak disentuh
+		result := tx.Where("id = ? AND deletion_scheduled_at <= ?", id, now).Delete(&models.User{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		deleted = true
+		for _, related := range []interface{}{&models.Session{}, &models.APIKey{}, &models.RecoveryCode{}} {
+			if err := tx.Where("user_id = ?", id).Delete(related).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return deleted && err == nil, err
+}
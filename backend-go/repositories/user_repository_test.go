@@ -0,0 +1,175 @@
+package repositories_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/repositories"
+	"fullstack-crud-project-01/backend-go/services"
+)
+
+// setupTOTPUser membuat pengguna dengan TOTP aktif dan MFAService dengan jam tetap
+func setupTOTPUser(t *testing.T) (*services.MFAService, uint, string) {
+	t.Helper()
+	key, err := totp.Generate(totp.GenerateOpts{Issuer: "Toko Test", AccountName: "totp@test.com"})
+	require.NoError(t, err)
+	secret := key.Secret()
+	user := &models.User{Email: "totp@test.com", IsActive: true, Role: models.RoleUser, TOTPEnabled: true, TOTPSecret: &secret}
+	require.NoError(t, repositories.NewUserRepository(testDB).Create(context.Background(), user))
+	t.Cleanup(func() { testDB.Exec("DELETE FROM users WHERE id = ?", user.ID) })
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc := services.NewMFAService(repositories.NewUserRepository(testDB), repositories.NewRecoveryCodeRepository(testDB), "Toko Test")
+	svc.Now = func() time.Time { return now }
+	code, err := totp.GenerateCode(secret, now)
+	require.NoError(t, err)
+	return svc, user.ID, code
+}
+
+func TestMFAService_TOTPReplayWithStaleCopy(t *testing.T) {
+	svc, userID, code := setupTOTPUser(t)
+	ctx := context.Background()
+	users := repositories.NewUserRepository(testDB)
+
+	// Dua request membaca pengguna sebelum salah satunya mencatat counter
+	first, err := users.FindByID(ctx, userID)
+	require.NoError(t, err)
+	second, err := users.FindByID(ctx, userID)
+	require.NoError(t, err)
+
+	assert.NoError(t, svc.Verify(ctx, first, code))
+	assert.ErrorIs(t, svc.Verify(ctx, second, code), models.ErrMFACodeInvalid, "Kode yang sama tidak boleh diterima dua kali")
+}
+
+func TestMFAService_TOTPConcurrentReplay(t *testing.T) {
+	svc, userID, code := setupTOTPUser(t)
+	users := repositories.NewUserRepository(testDB)
+
+	const attempts = 8
+	var accepted atomic.Int32
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			user, err := users.FindByID(context.Background(), userID)
+			if err != nil {
+				return
+			}
+			if svc.Verify(context.Background(), user, code) == nil {
+				accepted.Add(1)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), accepted.Load(), "Hanya satu dari request bersamaan yang boleh lolos")
+}
+
+// interleavedUserRepo menjalankan between sekali setelah pembacaan pertama, seolah request lain
+// mengubah data di antara pembacaan dan penyimpanan
+type interleavedUserRepo struct {
+	services.UserRepository
+	between func()
+	once    sync.Once
+}
+
+func (r *interleavedUserRepo) interleave() { r.once.Do(r.between) }
+
+func (r *interleavedUserRepo) FindByID(ctx context.Context, id uint) (*models.User, error) {
+	defer r.interleave()
+	return r.UserRepository.FindByID(ctx, id)
+}
+
+func (r *interleavedUserRepo) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	defer r.interleave()
+	return r.UserRepository.FindByEmail(ctx, email)
+}
+
+func (r *interleavedUserRepo) FindDueForDeletion(ctx context.Context, now time.Time, limit int) ([]models.User, error) {
+	defer r.interleave()
+	return r.UserRepository.FindDueForDeletion(ctx, now, limit)
+}
+
+// createAccountUser membuat pengguna aktif tanpa password (seperti akun OIDC)
+func createAccountUser(t *testing.T, email string) *models.User {
+	t.Helper()
+	user := &models.User{Email: email, IsActive: true, Role: models.RoleUser}
+	require.NoError(t, repositories.NewUserRepository(testDB).Create(context.Background(), user))
+	t.Cleanup(func() { testDB.Exec("DELETE FROM users WHERE id = ?", user.ID) })
+	return user
+}
+
+func TestAccountService_ConfirmEmailChangeRace(t *testing.T) {
+	ctx := context.Background()
+	user := createAccountUser(t, "lama@race.com")
+	svc := services.NewAccountService(repositories.NewUserRepository(testDB), time.Hour)
+	token, err := svc.RequestEmailChange(ctx, user.ID, "baru@race.com", "")
+	require.NoError(t, err)
+
+	// Email baru didaftarkan orang lain tepat setelah pemeriksaan ketersediaan
+	svc.Users = &interleavedUserRepo{UserRepository: svc.Users, between: func() {
+		createAccountUser(t, "baru@race.com")
+	}}
+	_, err = svc.ConfirmEmailChange(ctx, token)
+	assert.ErrorIs(t, err, models.ErrEmailAlreadyRegistered)
+
+	stored, err := repositories.NewUserRepository(testDB).FindByID(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "lama@race.com", stored.Email)
+}
+
+func TestAccountService_UpdatesOnlyItsOwnColumns(t *testing.T) {
+	ctx := context.Background()
+	user := createAccountUser(t, "kolom@race.com")
+	users := repositories.NewUserRepository(testDB)
+	scheduledAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	// Penghapusan dijadwalkan dan counter TOTP dicatat di antara pembacaan dan penyimpanan profil
+	svc := services.NewAccountService(&interleavedUserRepo{UserRepository: users, between: func() {
+		require.NoError(t, testDB.Model(&models.User{}).Where("id = ?", user.ID).Update("deletion_scheduled_at", scheduledAt).Error)
+		ok, err := users.UseTOTPCounter(ctx, user.ID, 42, time.Now())
+		require.NoError(t, err)
+		require.True(t, ok)
+	}}, time.Hour)
+	_, err := svc.UpdateProfile(ctx, user.ID, "Nama Baru")
+	require.NoError(t, err)
+
+	stored, err := users.FindByID(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Nama Baru", stored.Name)
+	require.NotNil(t, stored.DeletionScheduledAt, "Jadwal penghapusan tidak boleh tertimpa")
+	assert.True(t, scheduledAt.Equal(*stored.DeletionScheduledAt))
+	assert.Equal(t, int64(42), stored.TOTPLastCounter)
+}
+
+func TestAccountService_PurgeDueSkipsCancelledDeletion(t *testing.T) {
+	ctx := context.Background()
+	user := createAccountUser(t, "batal@race.com")
+	past := time.Now().Add(-time.Minute)
+	require.NoError(t, testDB.Model(&models.User{}).Where("id = ?", user.ID).Update("deletion_scheduled_at", past).Error)
+
+	users := repositories.NewUserRepository(testDB)
+	svc := services.NewAccountService(&interleavedUserRepo{UserRepository: users, between: func() {
+		// Pengguna membatalkan penghapusan setelah daftar akun jatuh tempo diambil
+		_, err := services.NewAccountService(users, time.Hour).CancelDeletion(ctx, user.ID)
+		require.NoError(t, err)
+	}}, time.Hour)
+
+	purged, err := svc.PurgeDue(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+	_, err = users.FindByID(ctx, user.ID)
+	assert.NoError(t, err, "Akun yang dibatalkan penghapusannya tidak boleh dihapus")
+}
@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"time"
+
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/services"
+	"gorm.io/gorm"
+)
+
+// WebhookRepositoryImpl adalah implementasi GORM dari services.WebhookRepository
+type WebhookRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+// NewWebhookRepository adalah konstruktor untuk WebhookRepositoryImpl
+func NewWebhookRepository(db *gorm.DB) services.WebhookRepository {
+	return &WebhookRepositoryImpl{DB: db}
+}
+
+// CreateSubscription menyimpan subscription baru
+func (r *WebhookRepositoryImpl) CreateSubscription(sub *models.WebhookSubscription) error {
+	return r.DB.Create(sub).Error
+}
+
+// ListSubscriptions mengambil semua subscription
+func (r *WebhookRepositoryImpl) ListSubscriptions() ([]models.WebhookSubscription, error) {
+	var subs []models.WebhookSubscription
+	result := r.DB.Order("id").Find(&subs)
+	return subs, result.Error
+}
+
+// FindSubscription mengambil subscription berdasarkan ID
+func (r *WebhookRepositoryImpl) FindSubscription(id uint) (*models.WebhookSubscription, error) {
+	var sub models.WebhookSubscription
+	result := r.DB.First(&sub, id)
+	return &sub, result.Error
+}
+
+// DeleteSubscription menghapus subscription beserta antrean pengirimannya
+func (r *WebhookRepositoryImpl) DeleteSubscription(id uint) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		deliveryIDs := tx.Model(&models.WebhookDelivery{}).Select("id").Where("subscription_id = ?", id)
+		if err := tx.Where("delivery_id IN (?)", deliveryIDs).Delete(&models.WebhookDeliveryLog{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("subscription_id = ?", id).Delete(&models.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.WebhookSubscription{}, id).Error
+	})
+}
+
+// ActiveSubscriptions mengambil subscription yang masih aktif
+func (r *WebhookRepositoryImpl) ActiveSubscriptions() ([]models.WebhookSubscription, error) {
+	var subs []models.WebhookSubscription
+	result := r.DB.Where("active = ?", true).Find(&subs)
+	return subs, result.Error
+}
+
+// CreateDelivery memasukkan pengiriman baru ke antrean
+func (r *WebhookRepositoryImpl) CreateDelivery(delivery *models.WebhookDelivery) error {
+	return r.DB.Create(delivery).Error
+}
+
+// FindDelivery mengambil pengiriman beserta log percobaannya
+func (r *WebhookRepositoryImpl) FindDelivery(id uint) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	result := r.DB.Preload("Logs").First(&delivery, id)
+	return &delivery, result.Error
+}
+
+// ListDeliveries mengambil pengiriman terbaru sebuah subscription beserta log percobaannya
+func (r *WebhookRepositoryImpl) ListDeliveries(subscriptionID uint, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	result := r.DB.Preload("Logs").
+		Where("subscription_id = ?", subscriptionID).
+		Order("id DESC").
+		Limit(limit).
+		Find(&deliveries)
+	return deliveries, result.Error
+}
+
+// DueDeliveries mengambil pengiriman pending yang jadwalnya sudah tiba, terlama lebih dulu
+func (r *WebhookRepositoryImpl) DueDeliveries(now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	result := r.DB.
+		Where("status = ? AND next_attempt_at <= ?", models.WebhookDeliveryPending, now).
+		Order("next_attempt_at, id").
+		Limit(limit).
+		Find(&deliveries)
+	return deliveries, result.Error
+}
+
+// UpdateDelivery menyimpan perubahan status pengiriman (tanpa menyentuh log)
+func (r *WebhookRepositoryImpl) UpdateDelivery(delivery *models.WebhookDelivery) error {
+	return r.DB.Omit("Logs").Save(delivery).Error
+}
+
+// CreateDeliveryLog menyimpan hasil satu percobaan pengiriman
+func (r *WebhookRepositoryImpl) CreateDeliveryLog(entry *models.WebhookDeliveryLog) error {
+	return r.DB.Create(entry).Error
+}
@@ -0,0 +1,69 @@
+package repositories_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"fullstack-crud-project-01/backend-go/database"
+	"fullstack-crud-project-01/backend-go/events"
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/repositories"
+	"fullstack-crud-project-01/backend-go/services"
+)
+
+func TestWebhookService_OnlyLeaderReplicaDelivers(t *testing.T) {
+	t.Cleanup(func() {
+		testDB.Exec("DELETE FROM webhook_delivery_logs")
+		testDB.Exec("DELETE FROM webhook_deliveries")
+		testDB.Exec("DELETE FROM webhook_subscriptions")
+	})
+
+	var received atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	// Dua replika berbagi database yang sama, masing-masing dengan lock leader sendiri
+	newReplica := func() *services.WebhookService {
+		svc := services.NewWebhookService(repositories.NewWebhookRepository(testDB))
+		svc.AllowPrivateTargets = true // Receiver httptest berjalan di 127.0.0.1
+		svc.Leader = database.NewLeaderLock(testDB, "webhook_worker")
+		return svc
+	}
+	first, second := newReplica(), newReplica()
+	require.NoError(t, first.CreateSubscription(&models.WebhookSubscription{
+		URL:    receiver.URL,
+		Secret: "rahasia",
+		Events: []string{events.ProductCreated},
+	}))
+	require.NoError(t, first.Enqueue(events.Event{Type: events.ProductCreated, AggregateID: 1, Data: json.RawMessage(`{"id":1}`)}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	for _, replica := range []*services.WebhookService{first, second} {
+		wg.Add(1)
+		go func(svc *services.WebhookService) {
+			defer wg.Done()
+			svc.Run(ctx, 10*time.Millisecond)
+		}(replica)
+	}
+
+	assert.Eventually(t, func() bool { return received.Load() >= 1 }, 2*time.Second, 10*time.Millisecond)
+	// Beri waktu replika lain untuk (keliru) mengirim ulang pengiriman yang sama
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	wg.Wait()
+
+	assert.Equal(t, int32(1), received.Load(), "Pengiriman tidak boleh dikirim oleh dua replika")
+}
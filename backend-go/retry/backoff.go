@@ -0,0 +1,18 @@
+// Package retry berisi perhitungan jadwal percobaan ulang yang dipakai bersama oleh relay
+// outbox dan antrean webhook.
+package retry
+
+import "time"
+
+// Backoff menghitung jeda eksponensial sebelum percobaan berikutnya: base * 2^(attempt-1),
+// dibatasi max.
+func Backoff(attempt int, base, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= max {
+			return max
+		}
+	}
+	return delay
+}
@@ -0,0 +1,26 @@
+package retry_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"fullstack-crud-project-01/backend-go/retry"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt  int
+		expected time.Duration
+	}{
+		{attempt: 1, expected: time.Second},
+		{attempt: 2, expected: 2 * time.Second},
+		{attempt: 4, expected: 8 * time.Second},
+		{attempt: 5, expected: 10 * time.Second},
+		{attempt: 100, expected: 10 * time.Second},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, retry.Backoff(tt.attempt, time.Second, 10*time.Second), "attempt %d", tt.attempt)
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"fullstack-crud-project-01/backend-go/config"
+)
+
+// newHTTPServer menyusun http.Server dengan batas waktu dari konfigurasi
+// sehingga klien lambat (slowloris) tidak bisa menahan koneksi selamanya
+func newHTTPServer(addr string, handler http.Handler, cfg config.ServerConfig) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+}
+
+// shutdownOptions mengatur urutan berhenti runHTTPServer
+type shutdownOptions struct {
+	// OnSignal dipanggil segera setelah sinyal berhenti diterima, sebelum Delay
+	// (mis. membuat /readyz membalas 503)
+	OnSignal func()
+	// Delay adalah jeda sebelum listener ditutup agar load balancer sempat melihat /readyz
+	// yang gagal dan berhenti mengirim request baru; request tetap dilayani selama jeda
+	Delay time.Duration
+	// Timeout adalah batas menunggu request yang sedang berjalan setelah listener ditutup
+	Timeout time.Duration
+}
+
+// runHTTPServer melayani request dari ln sampai ctx selesai (mis. SIGTERM). Setelah itu
+// OnSignal dipanggil, server tetap melayani selama Delay, lalu berhenti menerima koneksi baru
+// dan menunggu request yang sedang berjalan paling lama Timeout.
+// Error dikembalikan jika server gagal melayani atau request belum selesai saat batas habis.
+func runHTTPServer(ctx context.Context, srv *http.Server, ln net.Listener, opts shutdownOptions) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.Serve(ln)
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	if opts.OnSignal != nil {
+		opts.OnSignal()
+	}
+	if opts.Delay > 0 {
+		slog.Info("Sinyal berhenti diterima, menunggu load balancer berhenti mengirim request", "delay", opts.Delay.String())
+		select {
+		case err := <-serveErr:
+			return err
+		case <-time.After(opts.Delay):
+		}
+	}
+
+	slog.Info("Berhenti menerima koneksi, menunggu request berjalan selesai", "timeout", opts.Timeout.String())
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		// Batas habis: putus paksa koneksi yang tersisa
+		srv.Close()
+		return err
+	}
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// workerGroup menjalankan worker latar belakang dan menghentikannya dengan urutan
+// terbalik dari urutan start (worker yang bergantung pada worker lain berhenti lebih dulu)
+type workerGroup struct {
+	workers []*worker
+}
+
+type worker struct {
+	name   string
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Go menjalankan fn di goroutine baru dengan context yang dibatalkan saat Stop
+func (g *workerGroup) Go(name string, fn func(ctx context.Context)) {
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &worker{name: name, cancel: cancel, done: make(chan struct{})}
+	g.workers = append(g.workers, w)
+	go func() {
+		defer close(w.done)
+		fn(ctx)
+	}()
+}
+
+// Stop menghentikan worker satu per satu (terakhir dimulai, pertama dihentikan) dan
+// menunggu setiap worker selesai, paling lama sampai ctx selesai
+func (g *workerGroup) Stop(ctx context.Context) error {
+	for i := len(g.workers) - 1; i >= 0; i-- {
+		w := g.workers[i]
+		w.cancel()
+		select {
+		case <-w.done:
+		case <-ctx.Done():
+			return fmt.Errorf("worker %s tidak berhenti sebelum batas waktu shutdown", w.name)
+		}
+	}
+	return nil
+}
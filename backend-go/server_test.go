@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"fullstack-crud-project-01/backend-go/handlers"
+	"fullstack-crud-project-01/backend-go/services"
+)
+
+// startServer menjalankan runHTTPServer di port acak dan mengembalikan URL serta hasilnya
+func startServer(t *testing.T, ctx context.Context, handler http.Handler, opts shutdownOptions) (string, <-chan error) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	srv := &http.Server{Handler: handler}
+	result := make(chan error, 1)
+	go func() {
+		result <- runHTTPServer(ctx, srv, ln, opts)
+	}()
+	return "http://" + ln.Addr().String(), result
+}
+
+func TestRunHTTPServer_DrainsInFlightRequests(t *testing.T) {
+	started := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		io.WriteString(w, "selesai")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	url, result := startServer(t, ctx, handler, shutdownOptions{Timeout: 5 * time.Second})
+
+	type response struct {
+		body string
+		err  error
+	}
+	responses := make(chan response, 1)
+	go func() {
+		resp, err := http.Get(url)
+		if err != nil {
+			responses <- response{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		responses <- response{body: string(body), err: err}
+	}()
+
+	<-started
+	cancel() // Sinyal shutdown saat request masih berjalan
+
+	res := <-responses
+	require.NoError(t, res.err)
+	assert.Equal(t, "selesai", res.body, "Request yang sedang berjalan harus diselesaikan")
+	assert.NoError(t, <-result)
+
+	_, err := http.Get(url)
+	assert.Error(t, err, "Koneksi baru harus ditolak setelah shutdown")
+}
+
+func TestRunHTTPServer_ShutdownDeadline(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	url, result := startServer(t, ctx, handler, shutdownOptions{Timeout: 50 * time.Millisecond})
+	go http.Get(url)
+
+	<-started
+	cancel()
+	assert.ErrorIs(t, <-result, context.DeadlineExceeded)
+}
+
+func TestRunHTTPServer_NotReadyDuringShutdownDelay(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	healthService := services.NewHealthService("test")
+	r := gin.New()
+	r.GET("/readyz", handlers.NewHealthHandler(healthService).ReadinessHandler)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	url, result := startServer(t, ctx, r, shutdownOptions{
+		OnSignal: healthService.SetShuttingDown,
+		Delay:    300 * time.Millisecond,
+		Timeout:  time.Second,
+	})
+
+	// Koneksi baru untuk setiap probe, seperti load balancer
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	probe := func() (int, error) {
+		resp, err := client.Get(url + "/readyz")
+		if err != nil {
+			return 0, err
+		}
+		resp.Body.Close()
+		return resp.StatusCode, nil
+	}
+
+	status, err := probe()
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+
+	cancel()
+	// Selama jeda, listener masih terbuka dan /readyz melaporkan shutting down
+	assert.Eventually(t, func() bool {
+		status, err := probe()
+		return err == nil && status == http.StatusServiceUnavailable
+	}, 200*time.Millisecond, 10*time.Millisecond)
+
+	assert.NoError(t, <-result)
+	_, err = probe()
+	assert.Error(t, err, "Koneksi baru ditolak setelah jeda selesai")
+}
+
+func TestWorkerGroup_StopsInReverseOrder(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		stopped []string
+	)
+	var workers workerGroup
+	for _, name := range []string{"hub", "webhook", "relay"} {
+		workers.Go(name, func(ctx context.Context) {
+			<-ctx.Done()
+			mu.Lock()
+			stopped = append(stopped, name)
+			mu.Unlock()
+		})
+	}
+
+	require.NoError(t, workers.Stop(context.Background()))
+	assert.Equal(t, []string{"relay", "webhook", "hub"}, stopped)
+}
+
+func TestWorkerGroup_StopTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	var workers workerGroup
+	workers.Go("macet", func(ctx context.Context) { <-release })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := workers.Stop(ctx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "macet")
+}
@@ -0,0 +1,320 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/utils"
+)
+
+const (
+	// EmailChangeTokenTTL adalah masa berlaku link konfirmasi email baru
+	EmailChangeTokenTTL = 24 * time.Hour
+	// accountPurgeBatch membatasi jumlah akun yang dihapus per putaran PurgeDue
+	accountPurgeBatch = 100
+)
+
+// AccountService menyediakan layanan mandiri pengguna atas akunnya sendiri: profil,
+// password, email dan penghapusan akun.
+type AccountService struct {
+	Users               UserRepository
+	Sessions            *SessionService  // Opsional: nil berarti sesi lain tidak dicabut saat password diganti
+	Audit               *AuditService    // Opsional: nil berarti perubahan akun tidak diaudit
+	DeletionGracePeriod time.Duration    // Jeda sebelum akun yang diminta dihapus benar-benar dihapus
+	Now                 func() time.Time // Dapat diganti saat pengujian
+}
+
+// NewAccountService adalah konstruktor untuk AccountService.
+func NewAccountService(users UserRepository, deletionGracePeriod time.Duration) *AccountService {
+	return &AccountService{Users: users, DeletionGracePeriod: deletionGracePeriod, Now: time.Now}
+}
+
+// Profile mengambil data pengguna yang sedang login.
+func (s *AccountService) Profile(ctx context.Context, userID uint) (*models.User, error) {
+	return s.Users.FindByID(ctx, userID)
+}
+
+// UpdateProfile mengubah nama tampilan pengguna.
+func (s *AccountService) UpdateProfile(ctx context.Context, userID uint, name string) (*models.User, error) {
+	user, err := s.Users.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := map[string]string{"name": user.Name}
+	user.Name = strings.TrimSpace(name)
+	user.UpdatedAt = s.Now()
+	if err := s.update(ctx, user, "Name", "UpdatedAt"); err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, NewAuditEntry(models.AuditActionProfileUpdate, models.AuditResourceUser,
+		formatResourceID(user.ID), before, map[string]string{"name": user.Name}))
+	return user, nil
+}
+
+// ChangePassword mengganti password setelah password saat ini diverifikasi. Semua sesi lain
+// dicabut; sesi dengan TokenID keepTokenID (sesi yang meminta perubahan) tetap berlaku.
+func (s *AccountService) ChangePassword(ctx context.Context, userID uint, currentPassword, newPassword, keepTokenID string) error {
+	if len(newPassword) < MinPasswordLength {
+		return models.ErrPasswordTooShort
+	}
+	user, err := s.Users.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := verifyCurrentPassword(user, currentPassword); err != nil {
+		return err
+	}
+
+	passwordHash, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	user.PasswordHash = passwordHash
+	user.ResetToken = nil
+	user.ResetTokenExpiry = nil
+	user.UpdatedAt = s.Now()
+	if err := s.update(ctx, user, "PasswordHash", "ResetToken", "ResetTokenExpiry", "UpdatedAt"); err != nil {
+		return err
+	}
+
+	s.revokeSessions(ctx, user.ID, keepTokenID)
+	s.audit(ctx, NewAuditEntry(models.AuditActionPasswordChange, models.AuditResourceUser,
+		formatResourceID(user.ID), nil, nil))
+	return nil
+}
+
+// RequestEmailChange menyimpan email baru sebagai pending dan mengembalikan token konfirmasi
+// yang dikirim ke alamat tersebut. Email akun baru berubah setelah ConfirmEmailChange.
+func (s *AccountService) RequestEmailChange(ctx context.Context, userID uint, newEmail, currentPassword string) (string, error) {
+	user, err := s.Users.FindByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if err := verifyCurrentPassword(user, currentPassword); err != nil {
+		return "", err
+	}
+	if strings.EqualFold(newEmail, user.Email) {
+		return "", models.ErrEmailUnchanged
+	}
+	if err := s.checkEmailAvailable(ctx, newEmail); err != nil {
+		return "", err
+	}
+
+	// Permintaan baru menggantikan permintaan sebelumnya yang belum dikonfirmasi
+	token := uuid.NewString()
+	expiry := s.Now().Add(EmailChangeTokenTTL)
+	user.PendingEmail = &newEmail
+	user.EmailChangeToken = &token
+	user.EmailChangeExpiry = &expiry
+	user.UpdatedAt = s.Now()
+	if err := s.update(ctx, user, "PendingEmail", "EmailChangeToken", "EmailChangeExpiry", "UpdatedAt"); err != nil {
+		return "", err
+	}
+
+	s.audit(ctx, NewAuditEntry(models.AuditActionEmailChangeRequest, models.AuditResourceUser,
+		formatResourceID(user.ID), nil, map[string]string{"pending_email": newEmail}))
+	return token, nil
+}
+
+// ConfirmEmailChange mengganti email akun dengan email pending berdasarkan token dari link
+// konfirmasi. Token hanya berlaku sekali. Token JWT yang sudah terbit tetap membawa email
+// lama sampai kedaluwarsa.
+func (s *AccountService) ConfirmEmailChange(ctx context.Context, token string) (*models.User, error) {
+	if token == "" {
+		return nil, models.ErrEmailChangeTokenInvalid
+	}
+	user, err := s.Users.FindByEmailChangeToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrEmailChangeTokenInvalid
+		}
+		return nil, err
+	}
+	now := s.Now()
+	if user.PendingEmail == nil || user.EmailChangeExpiry == nil || !now.Before(*user.EmailChangeExpiry) {
+		return nil, models.ErrEmailChangeTokenInvalid
+	}
+	// Email bisa saja didaftarkan orang lain selama menunggu konfirmasi
+	if err := s.checkEmailAvailable(ctx, *user.PendingEmail); err != nil {
+		return nil, err
+	}
+
+	before := map[string]string{"email": user.Email}
+	user.Email = *user.PendingEmail
+	user.PendingEmail = nil
+	user.EmailChangeToken = nil
+	user.EmailChangeExpiry = nil
+	user.UpdatedAt = now
+	if err := s.update(ctx, user, "Email", "PendingEmail", "EmailChangeToken", "EmailChangeExpiry", "UpdatedAt"); err != nil {
+		return nil, err
+	}
+
+	entry := NewAuditEntry(models.AuditActionEmailChange, models.AuditResourceUser,
+		formatResourceID(user.ID), before, map[string]string{"email": user.Email})
+	// Link konfirmasi dibuka tanpa login; pelakunya adalah pemilik akun
+	if actor, ok := AuditActorFrom(ctx); !ok || actor.UserID == nil {
+		entry.ActorID = &user.ID
+		entry.ActorEmail = user.Email
+	}
+	s.audit(ctx, entry)
+	return user, nil
+}
+
+// ScheduleDeletion menjadwalkan penghapusan akun setelah DeletionGracePeriod dan mencabut
+// semua sesinya. Selama masa tenggang pengguna masih bisa login untuk membatalkannya.
+// Meminta ulang tidak memundurkan jadwal yang sudah ada.
+func (s *AccountService) ScheduleDeletion(ctx context.Context, userID uint, currentPassword string) (*models.User, error) {
+	user, err := s.Users.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyCurrentPassword(user, currentPassword); err != nil {
+		return nil, err
+	}
+	if user.DeletionScheduledAt != nil {
+		return user, nil
+	}
+
+	now := s.Now()
+	scheduledAt := now.Add(s.DeletionGracePeriod)
+	user.DeletionScheduledAt = &scheduledAt
+	user.UpdatedAt = now
+	if err := s.update(ctx, user, "DeletionScheduledAt", "UpdatedAt"); err != nil {
+		return nil, err
+	}
+
+	s.revokeSessions(ctx, user.ID, "")
+	s.audit(ctx, NewAuditEntry(models.AuditActionDeletionSchedule, models.AuditResourceUser,
+		formatResourceID(user.ID), nil, map[string]time.Time{"deletion_scheduled_at": scheduledAt}))
+	return user, nil
+}
+
+// CancelDeletion membatalkan penghapusan akun yang masih dalam masa tenggang.
+func (s *AccountService) CancelDeletion(ctx context.Context, userID uint) (*models.User, error) {
+	user, err := s.Users.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.DeletionScheduledAt == nil {
+		return nil, models.ErrDeletionNotScheduled
+	}
+
+	before := map[string]time.Time{"deletion_scheduled_at": *user.DeletionScheduledAt}
+	user.DeletionScheduledAt = nil
+	user.UpdatedAt = s.Now()
+	if err := s.update(ctx, user, "DeletionScheduledAt", "UpdatedAt"); err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, NewAuditEntry(models.AuditActionDeletionCancel, models.AuditResourceUser,
+		formatResourceID(user.ID), before, nil))
+	return user, nil
+}
+
+// PurgeDue menghapus permanen akun yang masa tenggangnya sudah lewat dan mengembalikan
+// jumlah akun yang dihapus.
+func (s *AccountService) PurgeDue(ctx context.Context) (int, error) {
+	now := s.Now()
+	users, err := s.Users.FindDueForDeletion(ctx, now, accountPurgeBatch)
+	if err != nil {
+		return 0, err
+	}
+	purged := 0
+	for _, user := range users {
+		// Pengguna bisa membatalkan penghapusan setelah daftar diambil
+		deleted, err := s.Users.DeleteDue(ctx, user.ID, now)
+		if err != nil {
+			return purged, err
+		}
+		if !deleted {
+			continue
+		}
+		purged++
+		// Tanpa snapshot: data pribadi tidak boleh tertinggal di audit log setelah akun dihapus
+		s.audit(ctx, NewAuditEntry(models.AuditActionUserDelete, models.AuditResourceUser,
+			formatResourceID(user.ID), nil, nil))
+	}
+	return purged, nil
+}
+
+// Run menjalankan PurgeDue secara berkala sampai ctx dibatalkan.
+func (s *AccountService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := s.PurgeDue(ctx)
+			if err != nil && ctx.Err() == nil {
+				slog.ErrorContext(ctx, "Gagal menghapus akun yang dijadwalkan", "error", err)
+			}
+			if purged > 0 {
+				slog.InfoContext(ctx, "Akun yang dijadwalkan telah dihapus", "count", purged)
+			}
+		}
+	}
+}
+
+// update menyimpan hanya kolom fields. Email adalah satu-satunya kolom unik yang bisa diubah
+// pengguna, jadi pelanggaran unique constraint (email dipakai akun lain di antara pemeriksaan
+// dan penyimpanan) dilaporkan sebagai ErrEmailAlreadyRegistered.
+func (s *AccountService) update(ctx context.Context, user *models.User, fields ...string) error {
+	err := s.Users.UpdateFields(ctx, user, fields...)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return models.ErrEmailAlreadyRegistered
+	}
+	return err
+}
+
+// checkEmailAvailable memastikan email belum dipakai akun lain
+func (s *AccountService) checkEmailAvailable(ctx context.Context, email string) error {
+	_, err := s.Users.FindByEmail(ctx, email)
+	if err == nil {
+		return models.ErrEmailAlreadyRegistered
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return nil
+}
+
+// revokeSessions mencabut sesi pengguna; kegagalan hanya di-log karena perubahan akun sudah tersimpan
+func (s *AccountService) revokeSessions(ctx context.Context, userID uint, keepTokenID string) {
+	if s.Sessions == nil {
+		return
+	}
+	if err := s.Sessions.RevokeAll(ctx, userID, keepTokenID); err != nil {
+		slog.WarnContext(ctx, "Gagal mencabut sesi pengguna", "user_id", userID, "error", err)
+	}
+}
+
+// audit mencatat perubahan akun; kegagalan hanya di-log
+func (s *AccountService) audit(ctx context.Context, entry *models.AuditLog) {
+	if err := s.Audit.Record(ctx, entry); err != nil {
+		logAuditFailure(ctx, entry, err)
+	}
+}
+
+// verifyCurrentPassword memeriksa password saat ini sebelum perubahan sensitif. Akun yang
+// dibuat lewat OIDC tidak punya password; untuk akun tersebut sesi login sudah cukup.
+func verifyCurrentPassword(user *models.User, password string) error {
+	if user.PasswordHash == "" {
+		return nil
+	}
+	if err := utils.CheckPasswordHash(user.PasswordHash, password); err != nil {
+		return models.ErrInvalidCredentials
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/services"
+	"fullstack-crud-project-01/backend-go/utils"
+)
+
+// newAccountFixture membuat AccountService dengan satu pengguna aktif berpassword "password123"
+func newAccountFixture(t *testing.T) (*services.AccountService, *MockUserRepo, *models.User) {
+	t.Helper()
+	hash, err := utils.HashPassword("password123")
+	require.NoError(t, err)
+	users := &MockUserRepo{}
+	user := &models.User{Email: "saya@test.com", PasswordHash: hash, Name: "Lama", IsActive: true, Role: models.RoleUser}
+	require.NoError(t, users.Create(context.Background(), user))
+	return services.NewAccountService(users, 30*24*time.Hour), users, user
+}
+
+func TestAccountService_UpdateProfile(t *testing.T) {
+	svc, _, user := newAccountFixture(t)
+	audit := &MockAuditRepo{}
+	svc.Audit = services.NewAuditService(audit)
+
+	updated, err := svc.UpdateProfile(context.Background(), user.ID, "  Nama Baru ")
+	require.NoError(t, err)
+	assert.Equal(t, "Nama Baru", updated.Name)
+
+	require.Len(t, audit.Created, 1)
+	assert.Equal(t, models.AuditActionProfileUpdate, audit.Created[0].Action)
+	assert.JSONEq(t, `{"name":"Lama"}`, string(audit.Created[0].Before))
+}
+
+func TestAccountService_ChangePassword(t *testing.T) {
+	svc, _, user := newAccountFixture(t)
+	sessions := &MockSessionRepo{}
+	svc.Sessions = services.NewSessionService(sessions)
+	current, err := svc.Sessions.Start(context.Background(), user)
+	require.NoError(t, err)
+	other, err := svc.Sessions.Start(context.Background(), user)
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, svc.ChangePassword(context.Background(), user.ID, "salah", "passwordbaru", current.TokenID), models.ErrInvalidCredentials)
+	assert.ErrorIs(t, svc.ChangePassword(context.Background(), user.ID, "password123", "pendek", current.TokenID), models.ErrPasswordTooShort)
+
+	require.NoError(t, svc.ChangePassword(context.Background(), user.ID, "password123", "passwordbaru", current.TokenID))
+	assert.NoError(t, utils.CheckPasswordHash(user.PasswordHash, "passwordbaru"))
+
+	// Sesi yang meminta perubahan tetap berlaku, sesi lain diakhiri
+	assert.NoError(t, svc.Sessions.Validate(context.Background(), sessionClaims(user.ID, current.TokenID)))
+	assert.ErrorIs(t, svc.Sessions.Validate(context.Background(), sessionClaims(user.ID, other.TokenID)), models.ErrSessionRevoked)
+}
+
+func TestAccountService_ChangePassword_OIDCAccountWithoutPassword(t *testing.T) {
+	users := &MockUserRepo{}
+	user := &models.User{Email: "sso@test.com", IsActive: true}
+	require.NoError(t, users.Create(context.Background(), user))
+	svc := services.NewAccountService(users, time.Hour)
+
+	require.NoError(t, svc.ChangePassword(context.Background(), user.ID, "", "passwordpertama", ""))
+	assert.ErrorIs(t, svc.ChangePassword(context.Background(), user.ID, "", "passwordkedua", ""), models.ErrInvalidCredentials,
+		"Setelah punya password, password saat ini wajib")
+}
+
+func TestAccountService_EmailChange(t *testing.T) {
+	svc, users, user := newAccountFixture(t)
+	now := time.Now()
+	svc.Now = func() time.Time { return now }
+	require.NoError(t, users.Create(context.Background(), &models.User{Email: "terpakai@test.com"}))
+
+	_, err := svc.RequestEmailChange(context.Background(), user.ID, "baru@test.com", "salah")
+	assert.ErrorIs(t, err, models.ErrInvalidCredentials)
+	_, err = svc.RequestEmailChange(context.Background(), user.ID, "SAYA@test.com", "password123")
+	assert.ErrorIs(t, err, models.ErrEmailUnchanged)
+	_, err = svc.RequestEmailChange(context.Background(), user.ID, "terpakai@test.com", "password123")
+	assert.ErrorIs(t, err, models.ErrEmailAlreadyRegistered)
+
+	token, err := svc.RequestEmailChange(context.Background(), user.ID, "baru@test.com", "password123")
+	require.NoError(t, err)
+	assert.Equal(t, "saya@test.com", user.Email, "Email lama berlaku sampai dikonfirmasi")
+	assert.Equal(t, "baru@test.com", *user.PendingEmail)
+
+	_, err = svc.ConfirmEmailChange(context.Background(), "token-salah")
+	assert.ErrorIs(t, err, models.ErrEmailChangeTokenInvalid)
+
+	confirmed, err := svc.ConfirmEmailChange(context.Background(), token)
+	require.NoError(t, err)
+	assert.Equal(t, "baru@test.com", confirmed.Email)
+	assert.Nil(t, confirmed.PendingEmail)
+	assert.Nil(t, confirmed.EmailChangeToken)
+
+	// Token hanya berlaku sekali
+	_, err = svc.ConfirmEmailChange(context.Background(), token)
+	assert.ErrorIs(t, err, models.ErrEmailChangeTokenInvalid)
+}
+
+func TestAccountService_EmailChange_Expired(t *testing.T) {
+	svc, _, user := newAccountFixture(t)
+	now := time.Now()
+	svc.Now = func() time.Time { return now }
+
+	token, err := svc.RequestEmailChange(context.Background(), user.ID, "baru@test.com", "password123")
+	require.NoError(t, err)
+
+	now = now.Add(services.EmailChangeTokenTTL)
+	_, err = svc.ConfirmEmailChange(context.Background(), token)
+	assert.ErrorIs(t, err, models.ErrEmailChangeTokenInvalid)
+	assert.Equal(t, "saya@test.com", user.Email)
+}
+
+func TestAccountService_DeletionGracePeriod(t *testing.T) {
+	svc, users, user := newAccountFixture(t)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc.Now = func() time.Time { return now }
+	sessions := &MockSessionRepo{}
+	svc.Sessions = services.NewSessionService(sessions)
+	svc.Sessions.Now = svc.Now
+	session, err := svc.Sessions.Start(context.Background(), user)
+	require.NoError(t, err)
+	audit := &MockAuditRepo{}
+	svc.Audit = services.NewAuditService(audit)
+
+	_, err = svc.CancelDeletion(context.Background(), user.ID)
+	assert.ErrorIs(t, err, models.ErrDeletionNotScheduled)
+	_, err = svc.ScheduleDeletion(context.Background(), user.ID, "salah")
+	assert.ErrorIs(t, err, models.ErrInvalidCredentials)
+
+	scheduled, err := svc.ScheduleDeletion(context.Background(), user.ID, "password123")
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(30*24*time.Hour), *scheduled.DeletionScheduledAt)
+	assert.ErrorIs(t, svc.Sessions.Validate(context.Background(), sessionClaims(user.ID, session.TokenID)), models.ErrSessionRevoked,
+		"Semua sesi diakhiri")
+
+	// Meminta ulang tidak memundurkan jadwal
+	now = now.Add(24 * time.Hour)
+	again, err := svc.ScheduleDeletion(context.Background(), user.ID, "password123")
+	require.NoError(t, err)
+	assert.Equal(t, scheduled.DeletionScheduledAt, again.DeletionScheduledAt)
+
+	// Dibatalkan selama masa tenggang
+	restored, err := svc.CancelDeletion(context.Background(), user.ID)
+	require.NoError(t, err)
+	assert.Nil(t, restored.DeletionScheduledAt)
+
+	// Dijadwalkan lagi lalu masa tenggang lewat: akun dihapus permanen
+	_, err = svc.ScheduleDeletion(context.Background(), user.ID, "password123")
+	require.NoError(t, err)
+	purged, err := svc.PurgeDue(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, purged, "Masih dalam masa tenggang")
+
+	now = now.Add(30 * 24 * time.Hour)
+	purged, err = svc.PurgeDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+	assert.Empty(t, users.Users)
+
+	actions := make([]string, 0, len(audit.Created))
+	for _, entry := range audit.Created {
+		actions = append(actions, entry.Action)
+	}
+	assert.Equal(t, []string{
+		models.AuditActionDeletionSchedule, models.AuditActionDeletionCancel,
+		models.AuditActionDeletionSchedule, models.AuditActionUserDelete,
+	}, actions)
+	last := audit.Created[len(audit.Created)-1]
+	assert.Empty(t, last.Before, "Data pribadi tidak disimpan di audit setelah akun dihapus")
+}
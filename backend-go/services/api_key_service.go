@@ -0,0 +1,223 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"fullstack-crud-project-01/backend-go/models"
+)
+
+// Format API key: bk_<prefix 8 karakter>_<secret 32 karakter>, base32 huruf kecil.
+// Bagian bk_<prefix> disimpan apa adanya untuk pencarian dan ditampilkan di daftar kunci.
+const (
+	apiKeyTag           = "bk_"
+	apiKeyPrefixBytes   = 5  // 8 karakter base32
+	apiKeySecretBytes   = 20 // 32 karakter base32 (160 bit entropi)
+	apiKeyTouchInterval = time.Minute
+)
+
+var apiKeyEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// APIKeyRepository mendefinisikan operasi penyimpanan API key ("port").
+// Method Find* mengembalikan gorm.ErrRecordNotFound jika kunci tidak ada.
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *models.APIKey) error
+	FindByPrefix(ctx context.Context, prefix string) (*models.APIKey, error)
+	// FindForUser hanya menemukan kunci milik userID
+	FindForUser(ctx context.Context, userID, id uint) (*models.APIKey, error)
+	ListByUser(ctx context.Context, userID uint) ([]models.APIKey, error)
+	Delete(ctx context.Context, id uint) error
+	TouchLastUsed(ctx context.Context, id uint, at time.Time) error
+}
+
+// APIKeyService mengelola API key pribadi untuk akses mesin-ke-mesin.
+type APIKeyService struct {
+	Keys  APIKeyRepository
+	Users UserRepository
+	Audit *AuditService    // Opsional: nil berarti pembuatan & pencabutan kunci tidak diaudit
+	Now   func() time.Time // Dapat diganti saat pengujian
+}
+
+// NewAPIKeyService adalah konstruktor untuk APIKeyService.
+func NewAPIKeyService(keys APIKeyRepository, users UserRepository) *APIKeyService {
+	return &APIKeyService{Keys: keys, Users: users, Now: time.Now}
+}
+
+// Create membuat API key baru untuk pengguna dan mengembalikan kunci aslinya.
+// Kunci asli hanya tersedia di sini; yang disimpan hanya hash-nya.
+func (s *APIKeyService) Create(ctx context.Context, userID uint, name string, scopes []string, expiresAt *time.Time) (*models.APIKey, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, "", models.ErrAPIKeyNameRequired
+	}
+	scopes, err := normalizeAPIKeyScopes(scopes)
+	if err != nil {
+		return nil, "", err
+	}
+	now := s.Now()
+	if expiresAt != nil && !expiresAt.After(now) {
+		return nil, "", models.ErrAPIKeyExpiryInvalid
+	}
+
+	prefix, err := randomAPIKeyPart(apiKeyPrefixBytes)
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := randomAPIKeyPart(apiKeySecretBytes)
+	if err != nil {
+		return nil, "", err
+	}
+	raw := apiKeyTag + prefix + "_" + secret
+
+	key := &models.APIKey{
+		UserID:    userID,
+		Name:      name,
+		Prefix:    apiKeyTag + prefix,
+		KeyHash:   hashAPIKey(raw),
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+	}
+	if err := s.Keys.Create(ctx, key); err != nil {
+		return nil, "", err
+	}
+
+	s.audit(ctx, models.AuditActionAPIKeyCreate, key, nil, key)
+	return key, raw, nil
+}
+
+// List mengambil semua API key milik pengguna (tanpa kunci asli).
+func (s *APIKeyService) List(ctx context.Context, userID uint) ([]models.APIKey, error) {
+	return s.Keys.ListByUser(ctx, userID)
+}
+
+// Revoke menghapus API key milik pengguna; kunci milik pengguna lain dianggap tidak ada.
+func (s *APIKeyService) Revoke(ctx context.Context, userID, id uint) error {
+	key, err := s.Keys.FindForUser(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.Keys.Delete(ctx, key.ID); err != nil {
+		return err
+	}
+
+	s.audit(ctx, models.AuditActionAPIKeyRevoke, key, key, nil)
+	return nil
+}
+
+// Authenticate memverifikasi kunci asli dan mengembalikan kunci beserta pemiliknya.
+// Kunci yang salah, kedaluwarsa, atau milik akun nonaktif ditolak dengan ErrAPIKeyInvalid.
+func (s *APIKeyService) Authenticate(ctx context.Context, raw string) (*models.APIKey, *models.User, error) {
+	prefix, ok := parseAPIKey(raw)
+	if !ok {
+		return nil, nil, models.ErrAPIKeyInvalid
+	}
+	key, err := s.Keys.FindByPrefix(ctx, prefix)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, models.ErrAPIKeyInvalid
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	now := s.Now()
+	if subtle.ConstantTimeCompare([]byte(key.KeyHash), []byte(hashAPIKey(raw))) != 1 || key.Expired(now) {
+		return nil, nil, models.ErrAPIKeyInvalid
+	}
+
+	user, err := s.Users.FindByID(ctx, key.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, models.ErrAPIKeyInvalid
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	// Akun yang dijadwalkan untuk dihapus tidak bisa dipakai lewat API key
+	if !user.IsActive || user.DeletionScheduledAt != nil {
+		return nil, nil, models.ErrAPIKeyInvalid
+	}
+
+	// last_used_at cukup akurat per menit; tidak perlu menulis ke database di setiap request
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchInterval {
+		if err := s.Keys.TouchLastUsed(ctx, key.ID, now); err != nil {
+			slog.WarnContext(ctx, "Gagal memperbarui last_used_at API key", "api_key_id", key.ID, "error", err)
+		} else {
+			key.LastUsedAt = &now
+		}
+	}
+	return key, user, nil
+}
+
+// audit mencatat pembuatan/pencabutan kunci; kegagalan hanya di-log
+func (s *APIKeyService) audit(ctx context.Context, action string, key *models.APIKey, before, after interface{}) {
+	entry := NewAuditEntry(action, models.AuditResourceAPIKey, formatResourceID(key.ID), before, after)
+	if err := s.Audit.Record(ctx, entry); err != nil {
+		logAuditFailure(ctx, entry, err)
+	}
+}
+
+// normalizeAPIKeyScopes memvalidasi scope dan mengurutkannya sesuai models.APIKeyScopes tanpa duplikat
+func normalizeAPIKeyScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return nil, models.ErrAPIKeyScopesRequired
+	}
+	requested := make(map[string]bool, len(scopes))
+	for _, scope := range scopes {
+		if !isAPIKeyScope(scope) {
+			return nil, fmt.Errorf("%w: %s", models.ErrAPIKeyScopeInvalid, scope)
+		}
+		requested[scope] = true
+	}
+	normalized := make([]string, 0, len(requested))
+	for _, scope := range models.APIKeyScopes {
+		if requested[scope] {
+			normalized = append(normalized, scope)
+		}
+	}
+	return normalized, nil
+}
+
+func isAPIKeyScope(scope string) bool {
+	for _, s := range models.APIKeyScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// parseAPIKey memeriksa format kunci dan mengembalikan bagian bk_<prefix>
+func parseAPIKey(raw string) (string, bool) {
+	prefixLength := len(apiKeyTag) + apiKeyEncoding.EncodedLen(apiKeyPrefixBytes)
+	if len(raw) != prefixLength+1+apiKeyEncoding.EncodedLen(apiKeySecretBytes) ||
+		!strings.HasPrefix(raw, apiKeyTag) || raw[prefixLength] != '_' {
+		return "", false
+	}
+	return raw[:prefixLength], true
+}
+
+// randomAPIKeyPart membuat n byte acak dalam base32 huruf kecil
+func randomAPIKeyPart(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return strings.ToLower(apiKeyEncoding.EncodeToString(buf)), nil
+}
+
+// hashAPIKey meng-hash kunci asli. Seperti kode pemulihan, SHA-256 cukup karena kunci
+// acak berentropi tinggi, dan hash perlu dihitung murah di setiap request.
+func hashAPIKey(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,191 @@
+package services_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/services"
+)
+
+// MockAPIKeyRepo adalah implementasi in-memory dari services.APIKeyRepository
+type MockAPIKeyRepo struct {
+	Keys    []*models.APIKey
+	Touches int
+}
+
+func (m *MockAPIKeyRepo) Create(ctx context.Context, key *models.APIKey) error {
+	key.ID = uint(len(m.Keys) + 1)
+	m.Keys = append(m.Keys, key)
+	return nil
+}
+
+func (m *MockAPIKeyRepo) FindByPrefix(ctx context.Context, prefix string) (*models.APIKey, error) {
+	for _, key := range m.Keys {
+		if key.Prefix == prefix {
+			return key, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockAPIKeyRepo) FindForUser(ctx context.Context, userID, id uint) (*models.APIKey, error) {
+	for _, key := range m.Keys {
+		if key.ID == id && key.UserID == userID {
+			return key, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockAPIKeyRepo) ListByUser(ctx context.Context, userID uint) ([]models.APIKey, error) {
+	var keys []models.APIKey
+	for _, key := range m.Keys {
+		if key.UserID == userID {
+			keys = append(keys, *key)
+		}
+	}
+	return keys, nil
+}
+
+func (m *MockAPIKeyRepo) Delete(ctx context.Context, id uint) error {
+	for i, key := range m.Keys {
+		if key.ID == id {
+			m.Keys = append(m.Keys[:i], m.Keys[i+1:]...)
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (m *MockAPIKeyRepo) TouchLastUsed(ctx context.Context, id uint, at time.Time) error {
+	for _, key := range m.Keys {
+		if key.ID == id {
+			key.LastUsedAt = &at
+			m.Touches++
+		}
+	}
+	return nil
+}
+
+func TestAPIKeyService_CreateAndAuthenticate(t *testing.T) {
+	users := &MockUserRepo{}
+	owner := &models.User{Email: "bot@test.com", IsActive: true, Role: models.RoleUser}
+	require.NoError(t, users.Create(context.Background(), owner))
+
+	keys := &MockAPIKeyRepo{}
+	audit := &MockAuditRepo{}
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc := services.NewAPIKeyService(keys, users)
+	svc.Audit = services.NewAuditService(audit)
+	svc.Now = func() time.Time { return now }
+	ctx := context.Background()
+
+	expiresAt := now.Add(24 * time.Hour)
+	key, raw, err := svc.Create(ctx, owner.ID, " CI ", []string{models.ScopeProductsWrite, models.ScopeProductsRead, models.ScopeProductsRead}, &expiresAt)
+	require.NoError(t, err)
+	assert.Regexp(t, `^bk_[a-z2-7]{8}_[a-z2-7]{32}$`, raw)
+	assert.True(t, strings.HasPrefix(raw, key.Prefix+"_"), "Prefix yang terlihat adalah awal kunci")
+	assert.NotContains(t, key.KeyHash, raw[len(key.Prefix)+1:], "Secret tidak disimpan apa adanya")
+	assert.Equal(t, "CI", key.Name)
+	assert.Equal(t, []string{models.ScopeProductsRead, models.ScopeProductsWrite}, key.Scopes)
+
+	found, user, err := svc.Authenticate(ctx, raw)
+	require.NoError(t, err)
+	assert.Equal(t, key.ID, found.ID)
+	assert.Equal(t, owner.ID, user.ID)
+	assert.Equal(t, now, *found.LastUsedAt)
+
+	// last_used_at hanya ditulis ulang setelah apiKeyTouchInterval
+	now = now.Add(10 * time.Second)
+	_, _, err = svc.Authenticate(ctx, raw)
+	require.NoError(t, err)
+	assert.Equal(t, 1, keys.Touches)
+
+	// Secret salah dengan prefix yang benar, format salah, dan kunci kedaluwarsa ditolak
+	tampered := raw[:len(raw)-1] + "a"
+	if tampered == raw {
+		tampered = raw[:len(raw)-1] + "b"
+	}
+	for _, candidate := range []string{tampered, "bk_short", ""} {
+		_, _, err = svc.Authenticate(ctx, candidate)
+		assert.ErrorIs(t, err, models.ErrAPIKeyInvalid, candidate)
+	}
+	now = expiresAt
+	_, _, err = svc.Authenticate(ctx, raw)
+	assert.ErrorIs(t, err, models.ErrAPIKeyInvalid, "Kunci kedaluwarsa")
+
+	// Kunci hanya bisa dicabut oleh pemiliknya
+	assert.ErrorIs(t, svc.Revoke(ctx, owner.ID+1, key.ID), gorm.ErrRecordNotFound)
+	require.NoError(t, svc.Revoke(ctx, owner.ID, key.ID))
+	_, _, err = svc.Authenticate(ctx, raw)
+	assert.ErrorIs(t, err, models.ErrAPIKeyInvalid, "Kunci yang dicabut")
+
+	actions := make([]string, 0, len(audit.Created))
+	for _, entry := range audit.Created {
+		actions = append(actions, entry.Action)
+	}
+	assert.Equal(t, []string{models.AuditActionAPIKeyCreate, models.AuditActionAPIKeyRevoke}, actions)
+	assert.NotContains(t, string(audit.Created[0].After), key.KeyHash, "Hash kunci tidak masuk audit log")
+}
+
+func TestAPIKeyService_Create_Validation(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Minute)
+	svc := services.NewAPIKeyService(&MockAPIKeyRepo{}, &MockUserRepo{})
+	svc.Now = func() time.Time { return now }
+
+	tests := []struct {
+		name      string
+		keyName   string
+		scopes    []string
+		expiresAt *time.Time
+		want      error
+	}{
+		{"NamaKosong", "  ", []string{models.ScopeProductsRead}, nil, models.ErrAPIKeyNameRequired},
+		{"TanpaScope", "CI", nil, nil, models.ErrAPIKeyScopesRequired},
+		{"ScopeTidakDikenal", "CI", []string{"users:write"}, nil, models.ErrAPIKeyScopeInvalid},
+		{"KedaluwarsaDiMasaLalu", "CI", []string{models.ScopeProductsRead}, &past, models.ErrAPIKeyExpiryInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := svc.Create(context.Background(), 1, tt.keyName, tt.scopes, tt.expiresAt)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+func TestAPIKeyService_Authenticate_InactiveOwner(t *testing.T) {
+	users := &MockUserRepo{}
+	owner := &models.User{Email: "bot@test.com", IsActive: true}
+	require.NoError(t, users.Create(context.Background(), owner))
+	svc := services.NewAPIKeyService(&MockAPIKeyRepo{}, users)
+
+	_, raw, err := svc.Create(context.Background(), owner.ID, "CI", []string{models.ScopeProductsRead}, nil)
+	require.NoError(t, err)
+	owner.IsActive = false
+
+	_, _, err = svc.Authenticate(context.Background(), raw)
+	assert.ErrorIs(t, err, models.ErrAPIKeyInvalid)
+}
+
+func TestAPIKeyService_Authenticate_OwnerScheduledForDeletion(t *testing.T) {
+	users := &MockUserRepo{}
+	owner := &models.User{Email: "bot@test.com", IsActive: true}
+	require.NoError(t, users.Create(context.Background(), owner))
+	svc := services.NewAPIKeyService(&MockAPIKeyRepo{}, users)
+
+	_, raw, err := svc.Create(context.Background(), owner.ID, "CI", []string{models.ScopeProductsRead}, nil)
+	require.NoError(t, err)
+	scheduledAt := time.Now().Add(time.Hour)
+	owner.DeletionScheduledAt = &scheduledAt
+
+	_, _, err = svc.Authenticate(context.Background(), raw)
+	assert.ErrorIs(t, err, models.ErrAPIKeyInvalid)
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"fullstack-crud-project-01/backend-go/models"
+)
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+// AuditFilter berisi kriteria pencarian audit log. Field kosong/nil diabaikan.
+type AuditFilter struct {
+	ActorID  *uint
+	Resource string
+	From     *time.Time
+	To       *time.Time
+	Limit    int
+}
+
+// AuditRepository mendefinisikan operasi penyimpanan audit log ("port").
+type AuditRepository interface {
+	Create(ctx context.Context, entry *models.AuditLog) error
+	Find(ctx context.Context, filter AuditFilter) ([]models.AuditLog, error)
+}
+
+// AuditActor adalah identitas pelaku dan asal request yang dibawa melalui context
+// sehingga service bisa mencatat audit tanpa bergantung pada gin.Context.
+type AuditActor struct {
+	UserID    *uint
+	Email     string
+	IP        string
+	UserAgent string
+}
+
+type auditActorKey struct{}
+
+// WithAuditActor menyimpan AuditActor ke dalam context.
+func WithAuditActor(ctx context.Context, actor AuditActor) context.Context {
+	return context.WithValue(ctx, auditActorKey{}, actor)
+}
+
+// AuditActorFrom mengambil AuditActor dari context.
+func AuditActorFrom(ctx context.Context) (AuditActor, bool) {
+	actor, ok := ctx.Value(auditActorKey{}).(AuditActor)
+	return actor, ok
+}
+
+// NewAuditEntry membuat entri audit dengan snapshot before/after.
+func NewAuditEntry(action, resource, resourceID string, before, after interface{}) *models.AuditLog {
+	return &models.AuditLog{
+		Action:     action,
+		Resource:   resource,
+		ResourceID: resourceID,
+		Before:     AuditSnapshot(before),
+		After:      AuditSnapshot(after),
+	}
+}
+
+// WriteAudit melengkapi entri dengan aktor dari context lalu menyimpannya melalui repo.
+// Dipakai langsung oleh service lain agar baris audit ikut dalam transaksi yang sama.
+func WriteAudit(ctx context.Context, repo AuditRepository, entry *models.AuditLog) error {
+	if repo == nil {
+		return nil
+	}
+	if actor, ok := AuditActorFrom(ctx); ok {
+		if entry.ActorID == nil {
+			entry.ActorID = actor.UserID
+		}
+		if entry.ActorEmail == "" {
+			entry.ActorEmail = actor.Email
+		}
+		if entry.IP == "" {
+			entry.IP = actor.IP
+		}
+		if entry.UserAgent == "" {
+			entry.UserAgent = actor.UserAgent
+		}
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	return repo.Create(ctx, entry)
+}
+
+// AuditService menyediakan logika bisnis untuk pencatatan dan pencarian audit log.
+type AuditService struct {
+	Repo AuditRepository
+}
+
+// NewAuditService adalah konstruktor untuk AuditService.
+func NewAuditService(repo AuditRepository) *AuditService {
+	return &AuditService{Repo: repo}
+}
+
+// Record menyimpan satu entri audit; aktor, IP dan user agent diambil dari context jika kosong.
+// Aman dipanggil pada service nil sehingga audit bersifat opsional bagi pemanggil.
+func (s *AuditService) Record(ctx context.Context, entry *models.AuditLog) error {
+	if s == nil {
+		return nil
+	}
+	return WriteAudit(ctx, s.Repo, entry)
+}
+
+// Query mencari audit log sesuai filter, terbaru lebih dulu.
+func (s *AuditService) Query(ctx context.Context, filter AuditFilter) ([]models.AuditLog, error) {
+	if filter.From != nil && filter.To != nil && filter.From.After(*filter.To) {
+		return nil, models.ErrAuditInvalidTimeRange
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAuditLimit
+	}
+	if filter.Limit > maxAuditLimit {
+		filter.Limit = maxAuditLimit
+	}
+	return s.Repo.Find(ctx, filter)
+}
+
+// AuditSnapshot mengubah sebuah nilai menjadi payload JSON untuk kolom before/after.
+// Mengembalikan nil jika nilai nil atau gagal di-marshal.
+func AuditSnapshot(v interface{}) json.RawMessage {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// formatResourceID mengubah ID numerik menjadi nilai kolom resource_id
+func formatResourceID(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+// logAuditFailure mencatat kegagalan penyimpanan audit yang tidak boleh menggagalkan operasi utama
+func logAuditFailure(ctx context.Context, entry *models.AuditLog, err error) {
+	slog.ErrorContext(ctx, "Gagal mencatat audit log", "action", entry.Action, "error", err)
+}
@@ -0,0 +1,108 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/services"
+)
+
+// MockAuditRepo adalah implementasi palsu dari services.AuditRepository
+type MockAuditRepo struct {
+	Created    []models.AuditLog
+	LastFilter services.AuditFilter
+	Fail       bool
+}
+
+func (m *MockAuditRepo) Create(ctx context.Context, entry *models.AuditLog) error {
+	if m.Fail {
+		return errors.New("audit storage unavailable")
+	}
+	m.Created = append(m.Created, *entry)
+	return nil
+}
+
+func (m *MockAuditRepo) Find(ctx context.Context, filter services.AuditFilter) ([]models.AuditLog, error) {
+	m.LastFilter = filter
+	return m.Created, nil
+}
+
+func TestAuditService_Record(t *testing.T) {
+	repo := &MockAuditRepo{}
+	auditService := services.NewAuditService(repo)
+
+	actorID := uint(42)
+	ctx := services.WithAuditActor(context.Background(), services.AuditActor{
+		UserID: &actorID, Email: "admin@example.com", IP: "10.0.0.1", UserAgent: "curl/8.0",
+	})
+
+	err := auditService.Record(ctx, &models.AuditLog{
+		Action:   models.AuditActionProductCreate,
+		Resource: models.AuditResourceProduct,
+		After:    services.AuditSnapshot(models.Product{Name: "Laptop", Price: 100}),
+	})
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if len(repo.Created) != 1 {
+		t.Fatalf("Expected 1 audit entry, got %d", len(repo.Created))
+	}
+	if repo.Created[0].CreatedAt.IsZero() {
+		t.Errorf("Expected CreatedAt to be set")
+	}
+	if repo.Created[0].ActorID == nil || *repo.Created[0].ActorID != actorID || repo.Created[0].IP != "10.0.0.1" {
+		t.Errorf("Expected actor and IP from context, got: %+v", repo.Created[0])
+	}
+
+	// Service nil tidak boleh panic (audit bersifat opsional)
+	var nilService *services.AuditService
+	if err := nilService.Record(ctx, &models.AuditLog{}); err != nil {
+		t.Errorf("Expected nil error from nil service, got: %v", err)
+	}
+}
+
+func TestAuditService_Query(t *testing.T) {
+	now := time.Now()
+	earlier := now.Add(-time.Hour)
+
+	tests := []struct {
+		name          string
+		filter        services.AuditFilter
+		expectedErr   error
+		expectedLimit int
+	}{
+		{
+			name:          "Success_DefaultLimit",
+			filter:        services.AuditFilter{Resource: models.AuditResourceProduct},
+			expectedLimit: 100,
+		},
+		{
+			name:          "Success_LimitCapped",
+			filter:        services.AuditFilter{Limit: 5000},
+			expectedLimit: 1000,
+		},
+		{
+			name:        "Failure_InvalidTimeRange",
+			filter:      services.AuditFilter{From: &now, To: &earlier},
+			expectedErr: models.ErrAuditInvalidTimeRange,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MockAuditRepo{}
+			auditService := services.NewAuditService(repo)
+
+			_, err := auditService.Query(context.Background(), tt.filter)
+			if err != tt.expectedErr {
+				t.Fatalf("Expected error: %v, got: %v", tt.expectedErr, err)
+			}
+			if tt.expectedErr == nil && repo.LastFilter.Limit != tt.expectedLimit {
+				t.Errorf("Expected limit %d, got %d", tt.expectedLimit, repo.LastFilter.Limit)
+			}
+		})
+	}
+}
@@ -0,0 +1,503 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/utils"
+)
+
+// UserRepository mendefinisikan operasi penyimpanan pengguna ("port").
+// Method Find* mengembalikan gorm.ErrRecordNotFound jika pengguna tidak ada.
+type UserRepository interface {
+	FindByID(ctx context.Context, id uint) (*models.User, error)
+	FindByEmail(ctx context.Context, email string) (*models.User, error)
+	FindByActivationToken(ctx context.Context, token string) (*models.User, error)
+	FindByOIDCSubject(ctx context.Context, issuer, subject string) (*models.User, error)
+	FindByEmailChangeToken(ctx context.Context, token string) (*models.User, error)
+	// FindDueForDeletion mengambil maksimal limit pengguna dengan deletion_scheduled_at <= now
+	FindDueForDeletion(ctx context.Context, now time.Time, limit int) ([]models.User, error)
+	Create(ctx context.Context, user *models.User) error
+	Update(ctx context.Context, user *models.User) error
+	// UpdateFields hanya menyimpan kolom dari field struct yang disebut, sehingga perubahan
+	// lain yang terjadi bersamaan (counter TOTP, tautan OIDC, jadwal penghapusan) tidak tertimpa
+	UpdateFields(ctx context.Context, user *models.User, fields ...string) error
+	// UseTOTPCounter mencatat langkah waktu kode TOTP yang dipakai hanya jika lebih baru dari
+	// yang tersimpan; false berarti kode sudah pernah dipakai (mis. oleh request bersamaan)
+	UseTOTPCounter(ctx context.Context, id uint, counter int64, at time.Time) (bool, error)
+	// DeleteDue menghapus pengguna secara permanen beserta data yang terikat padanya, hanya jika
+	// deletion_scheduled_at masih <= now; false berarti penghapusan sudah dibatalkan
+	DeleteDue(ctx context.Context, id uint, now time.Time) (bool, error)
+}
+
+// LoginLimiter membatasi percobaan login per akun ("port"), mis. ratelimit.LoginGuard.
+type LoginLimiter interface {
+	// Allow dipanggil sebelum password diverifikasi; retryAfter > 0 berarti ditolak
+	Allow(ctx context.Context, account string) (retryAfter time.Duration, err error)
+	// Failed mencatat kredensial salah dan mengembalikan jeda sebelum response dikirim
+	Failed(ctx context.Context, account string) (delay time.Duration, err error)
+	// Succeeded mengosongkan hitungan kegagalan setelah login berhasil
+	Succeeded(ctx context.Context, account string) error
+}
+
+// LoginThrottledError dikembalikan Login jika akun sedang dikunci atau melewati batas
+// percobaan. errors.Is(err, models.ErrTooManyLoginAttempts) bernilai true.
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return models.ErrTooManyLoginAttempts.Error()
+}
+
+func (e *LoginThrottledError) Is(target error) bool {
+	return target == models.ErrTooManyLoginAttempts
+}
+
+// MinPasswordLength adalah panjang minimum password, sama dengan validasi dto.RegisterRequest.
+const MinPasswordLength = 8
+
+// OIDCIdentity adalah identitas pengguna dari ID token yang sudah diverifikasi.
+type OIDCIdentity struct {
+	Issuer  string // Claim iss
+	Subject string // Claim sub, stabil per pengguna di penyedia identitas
+	Email   string // Sudah diverifikasi penyedia (email_verified) dan dinormalisasi huruf kecil
+	Name    string
+}
+
+// LoginResult adalah hasil login yang berhasil. Untuk pengguna dengan TOTP aktif,
+// langkah password hanya menghasilkan MFAToken; Token baru terbit setelah LoginMFA.
+type LoginResult struct {
+	Token    string
+	MFAToken string
+	User     *models.User
+}
+
+// AuthService menyediakan aturan bisnis registrasi, aktivasi dan login.
+type AuthService struct {
+	Users    UserRepository
+	Audit    *AuditService   // Opsional: nil berarti registrasi & login tidak diaudit
+	Limiter  LoginLimiter    // Opsional: nil berarti percobaan login tidak dibatasi
+	MFA      *MFAService     // Wajib jika ada pengguna dengan TOTP aktif
+	Sessions *SessionService // Opsional: nil berarti token tidak terikat sesi dan tidak bisa dicabut
+}
+
+// NewAuthService adalah konstruktor untuk AuthService.
+func NewAuthService(users UserRepository) *AuthService {
+	return &AuthService{Users: users}
+}
+
+// Register mendaftarkan pengguna baru dalam keadaan belum aktif beserta token aktivasinya.
+func (s *AuthService) Register(ctx context.Context, email, password, name string) (*models.User, error) {
+	if len(password) < MinPasswordLength {
+		return nil, models.ErrPasswordTooShort
+	}
+
+	// 1. Cek Duplikasi Email
+	_, err := s.Users.FindByEmail(ctx, email)
+	if err == nil {
+		return nil, models.ErrEmailAlreadyRegistered
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	// 2. Hash Password
+	passwordHash, err := utils.HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Buat Token Aktivasi
+	activationToken := uuid.NewString()
+
+	now := time.Now()
+	user := &models.User{
+		Email:           email,
+		PasswordHash:    passwordHash,
+		Name:            name,
+		IsActive:        false, // Wajib FALSE sampai email diaktivasi
+		Role:            models.RoleUser,
+		ActivationToken: &activationToken,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	// 4. Simpan User
+	if err := s.Users.Create(ctx, user); err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, &models.AuditLog{
+		ActorID:    &user.ID,
+		ActorEmail: user.Email,
+		Action:     models.AuditActionUserRegister,
+		Resource:   models.AuditResourceUser,
+		ResourceID: formatResourceID(user.ID),
+		After:      AuditSnapshot(user),
+	})
+	return user, nil
+}
+
+// Activate mengaktifkan akun berdasarkan token aktivasi dari email. Token hanya berlaku sekali.
+func (s *AuthService) Activate(ctx context.Context, token string) (*models.User, error) {
+	if token == "" {
+		return nil, models.ErrActivationTokenInvalid
+	}
+
+	user, err := s.Users.FindByActivationToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrActivationTokenInvalid
+		}
+		return nil, err
+	}
+
+	return s.activate(ctx, user)
+}
+
+// ActivateByEmail mengaktifkan akun tanpa token, untuk administrasi (mis. CLI).
+func (s *AuthService) ActivateByEmail(ctx context.Context, email string) (*models.User, error) {
+	user, err := s.Users.FindByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	return s.activate(ctx, user)
+}
+
+// activate menandai akun aktif dan menghapus token aktivasinya
+func (s *AuthService) activate(ctx context.Context, user *models.User) (*models.User, error) {
+	user.IsActive = true
+	user.ActivationToken = nil
+	user.UpdatedAt = time.Now()
+	if err := s.Users.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	entry := &models.AuditLog{
+		Action:     models.AuditActionUserActivate,
+		Resource:   models.AuditResourceUser,
+		ResourceID: formatResourceID(user.ID),
+	}
+	// Aktivasi lewat link email dilakukan oleh pemilik akun sendiri
+	if actor, ok := AuditActorFrom(ctx); !ok || (actor.UserID == nil && actor.Email == "") {
+		entry.ActorID = &user.ID
+		entry.ActorEmail = user.Email
+	}
+	s.audit(ctx, entry)
+	return user, nil
+}
+
+// SetRole mengubah peran pengguna. Token yang sudah terbit tetap membawa peran lama sampai kedaluwarsa.
+func (s *AuthService) SetRole(ctx context.Context, email, role string) (*models.User, error) {
+	if !models.IsValidRole(role) {
+		return nil, models.ErrInvalidRole
+	}
+	user, err := s.Users.FindByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	before := map[string]string{"role": user.Role}
+	user.Role = role
+	user.UpdatedAt = time.Now()
+	if err := s.Users.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, NewAuditEntry(models.AuditActionUserSetRole, models.AuditResourceUser,
+		formatResourceID(user.ID), before, map[string]string{"role": role}))
+	return user, nil
+}
+
+// ResetPassword mengganti password pengguna dan membatalkan token reset yang masih berlaku.
+func (s *AuthService) ResetPassword(ctx context.Context, email, password string) (*models.User, error) {
+	if len(password) < MinPasswordLength {
+		return nil, models.ErrPasswordTooShort
+	}
+	user, err := s.Users.FindByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	passwordHash, err := utils.HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+	user.PasswordHash = passwordHash
+	user.ResetToken = nil
+	user.ResetTokenExpiry = nil
+	user.UpdatedAt = time.Now()
+	if err := s.Users.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, NewAuditEntry(models.AuditActionPasswordReset, models.AuditResourceUser,
+		formatResourceID(user.ID), nil, nil))
+	return user, nil
+}
+
+// Login memverifikasi kredensial dan menerbitkan JWT.
+// Email tidak terdaftar dan password salah sama-sama menghasilkan ErrInvalidCredentials
+// agar penyerang tidak bisa menebak email yang terdaftar. Jika Limiter diatur, akun yang
+// dikunci atau terlalu sering dicoba menghasilkan *LoginThrottledError.
+func (s *AuthService) Login(ctx context.Context, email, password string) (*LoginResult, error) {
+	// 0. Batas percobaan per akun (berlaku juga untuk email yang tidak terdaftar)
+	if err := s.checkLoginLimit(ctx, email); err != nil {
+		return nil, err
+	}
+
+	// 1. Cari User berdasarkan Email
+	user, err := s.Users.FindByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.auditLogin(ctx, models.AuditActionLoginFailure, nil, email, "user_not_found")
+			return nil, s.loginFailed(ctx, email, models.ErrInvalidCredentials)
+		}
+		return nil, err
+	}
+
+	// 2. Verifikasi Password
+	if err := utils.CheckPasswordHash(user.PasswordHash, password); err != nil {
+		s.auditLogin(ctx, models.AuditActionLoginFailure, user, email, "invalid_password")
+		return nil, s.loginFailed(ctx, email, models.ErrInvalidCredentials)
+	}
+
+	// 3. Cek Status Aktif (Penting!)
+	if !user.IsActive {
+		s.auditLogin(ctx, models.AuditActionLoginFailure, user, email, "inactive_account")
+		return nil, models.ErrAccountNotActive
+	}
+
+	// 4. Pengguna dengan TOTP aktif harus menyelesaikan langkah kedua (LoginMFA)
+	return s.finishLogin(ctx, user, email)
+}
+
+// LoginOIDC login dengan identitas terverifikasi dari penyedia OIDC. Pengguna dicari
+// berdasarkan iss+sub, lalu berdasarkan email (identitas dihubungkan ke akun tersebut),
+// atau dibuat baru jika provision true. Pengguna dengan TOTP aktif tetap harus
+// menyelesaikan LoginMFA.
+func (s *AuthService) LoginOIDC(ctx context.Context, identity OIDCIdentity, provision bool) (*LoginResult, error) {
+	user, err := s.Users.FindByOIDCSubject(ctx, identity.Issuer, identity.Subject)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		user, err = s.linkOIDCIdentity(ctx, identity, provision)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.IsActive {
+		s.auditLogin(ctx, models.AuditActionLoginFailure, user, user.Email, "inactive_account")
+		return nil, models.ErrAccountNotActive
+	}
+	return s.finishLogin(ctx, user, user.Email)
+}
+
+// linkOIDCIdentity menghubungkan identitas OIDC ke akun dengan email yang sama, atau
+// membuat akun baru (aktif, tanpa password) jika belum ada dan provision true
+func (s *AuthService) linkOIDCIdentity(ctx context.Context, identity OIDCIdentity, provision bool) (*models.User, error) {
+	issuer, subject := identity.Issuer, identity.Subject
+	now := time.Now()
+
+	user, err := s.Users.FindByEmail(ctx, identity.Email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if !provision {
+			s.auditLogin(ctx, models.AuditActionLoginFailure, nil, identity.Email, "oidc_account_not_found")
+			return nil, models.ErrOIDCAccountNotFound
+		}
+		// Password kosong tidak pernah cocok dengan bcrypt, jadi akun ini hanya bisa login lewat OIDC
+		user = &models.User{
+			Email:       identity.Email,
+			Name:        identity.Name,
+			IsActive:    true, // Email sudah diverifikasi oleh penyedia identitas
+			Role:        models.RoleUser,
+			OIDCIssuer:  &issuer,
+			OIDCSubject: &subject,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		if err := s.Users.Create(ctx, user); err != nil {
+			return nil, err
+		}
+		s.audit(ctx, &models.AuditLog{
+			ActorID:    &user.ID,
+			ActorEmail: user.Email,
+			Action:     models.AuditActionUserRegister,
+			Resource:   models.AuditResourceUser,
+			ResourceID: formatResourceID(user.ID),
+			After:      AuditSnapshot(user),
+		})
+		return user, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Akun yang belum diaktifkan bisa saja didaftarkan orang lain dengan email korban;
+	// identitas OIDC tidak boleh terhubung ke akun tersebut
+	if !user.IsActive {
+		s.auditLogin(ctx, models.AuditActionLoginFailure, user, user.Email, "inactive_account")
+		return nil, models.ErrAccountNotActive
+	}
+	if user.OIDCSubject != nil {
+		s.auditLogin(ctx, models.AuditActionLoginFailure, user, user.Email, "oidc_identity_conflict")
+		return nil, models.ErrOIDCIdentityConflict
+	}
+	user.OIDCIssuer = &issuer
+	user.OIDCSubject = &subject
+	user.UpdatedAt = now
+	if err := s.Users.UpdateFields(ctx, user, "OIDCIssuer", "OIDCSubject", "UpdatedAt"); err != nil {
+		return nil, err
+	}
+	s.audit(ctx, &models.AuditLog{
+		ActorID:    &user.ID,
+		ActorEmail: user.Email,
+		Action:     models.AuditActionOIDCLink,
+		Resource:   models.AuditResourceUser,
+		ResourceID: formatResourceID(user.ID),
+		After:      AuditSnapshot(map[string]string{"oidc_issuer": issuer, "oidc_subject": subject}),
+	})
+	return user, nil
+}
+
+// LoginMFA adalah langkah kedua login: menukar token tantangan dari Login dan kode TOTP
+// (atau kode pemulihan) dengan access token. Kode salah dihitung oleh Limiter seperti
+// password salah.
+func (s *AuthService) LoginMFA(ctx context.Context, mfaToken, code string) (*LoginResult, error) {
+	claims, err := utils.ValidateMFAToken(mfaToken)
+	if err != nil {
+		return nil, models.ErrMFATokenInvalid
+	}
+	user, err := s.Users.FindByID(ctx, claims.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrMFATokenInvalid
+		}
+		return nil, err
+	}
+	// TOTP dimatikan setelah token terbit: token tantangan sudah tidak relevan
+	if s.MFA == nil || !user.TOTPEnabled {
+		return nil, models.ErrMFATokenInvalid
+	}
+
+	if err := s.checkLoginLimit(ctx, user.Email); err != nil {
+		return nil, err
+	}
+	if err := s.MFA.Verify(ctx, user, code); err != nil {
+		if errors.Is(err, models.ErrMFACodeInvalid) {
+			s.auditLogin(ctx, models.AuditActionLoginFailure, user, user.Email, "invalid_mfa_code")
+			return nil, s.loginFailed(ctx, user.Email, models.ErrMFACodeInvalid)
+		}
+		return nil, err
+	}
+
+	return s.completeLogin(ctx, user, user.Email)
+}
+
+// finishLogin menerbitkan access token, atau token tantangan MFA jika TOTP aktif
+func (s *AuthService) finishLogin(ctx context.Context, user *models.User, email string) (*LoginResult, error) {
+	if user.TOTPEnabled {
+		mfaToken, err := utils.GenerateMFAToken(user.ID, user.Email)
+		if err != nil {
+			return nil, err
+		}
+		return &LoginResult{MFAToken: mfaToken, User: user}, nil
+	}
+	return s.completeLogin(ctx, user, email)
+}
+
+// completeLogin menerbitkan access token setelah semua faktor terverifikasi
+func (s *AuthService) completeLogin(ctx context.Context, user *models.User, email string) (*LoginResult, error) {
+	sessionID := ""
+	if s.Sessions != nil {
+		session, err := s.Sessions.Start(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+		sessionID = session.TokenID
+	}
+	token, err := utils.GenerateSessionToken(user.ID, user.Email, user.Role, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Limiter != nil {
+		if err := s.Limiter.Succeeded(ctx, email); err != nil {
+			slog.WarnContext(ctx, "Gagal mereset hitungan login gagal", "error", err)
+		}
+	}
+
+	s.auditLogin(ctx, models.AuditActionLoginSuccess, user, email, "")
+	return &LoginResult{Token: token, User: user}, nil
+}
+
+// checkLoginLimit menolak login jika akun dikunci atau melewati batas percobaan.
+// Kegagalan limiter tidak memblokir login (fail open) agar gangguan store tidak
+// membuat semua pengguna terkunci.
+func (s *AuthService) checkLoginLimit(ctx context.Context, email string) error {
+	if s.Limiter == nil {
+		return nil
+	}
+	retryAfter, err := s.Limiter.Allow(ctx, email)
+	if err != nil {
+		slog.WarnContext(ctx, "Batas login tidak dapat diperiksa", "error", err)
+		return nil
+	}
+	if retryAfter > 0 {
+		s.auditLogin(ctx, models.AuditActionLoginFailure, nil, email, "throttled")
+		return &LoginThrottledError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// loginFailed mencatat kredensial salah ke Limiter dan menahan response sesuai jeda
+// progresif. Selalu mengembalikan failure.
+func (s *AuthService) loginFailed(ctx context.Context, email string, failure error) error {
+	if s.Limiter == nil {
+		return failure
+	}
+	delay, err := s.Limiter.Failed(ctx, email)
+	if err != nil {
+		slog.WarnContext(ctx, "Gagal mencatat login gagal", "error", err)
+	}
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+		}
+	}
+	return failure
+}
+
+// auditLogin mencatat satu percobaan login. Pada login gagal, aktor diisi dari
+// email yang dicoba (dan ID user jika email tersebut terdaftar).
+func (s *AuthService) auditLogin(ctx context.Context, action string, user *models.User, email, reason string) {
+	entry := &models.AuditLog{
+		ActorEmail: email,
+		Action:     action,
+		Resource:   models.AuditResourceUser,
+	}
+	if user != nil {
+		entry.ActorID = &user.ID
+		entry.ResourceID = formatResourceID(user.ID)
+	}
+	if reason != "" {
+		entry.After = AuditSnapshot(map[string]string{"reason": reason})
+	}
+	s.audit(ctx, entry)
+}
+
+// audit mencatat entri audit; kegagalan hanya di-log agar tidak menggagalkan autentikasi.
+func (s *AuthService) audit(ctx context.Context, entry *models.AuditLog) {
+	if err := s.Audit.Record(ctx, entry); err != nil {
+		logAuditFailure(ctx, entry, err)
+	}
+}
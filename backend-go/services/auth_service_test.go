@@ -0,0 +1,324 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/ratelimit"
+	"fullstack-crud-project-01/backend-go/services"
+	"fullstack-crud-project-01/backend-go/utils"
+)
+
+// MockUserRepo adalah implementasi in-memory dari services.UserRepository
+type MockUserRepo struct {
+	Users  []*models.User
+	nextID uint
+}
+
+func (m *MockUserRepo) FindByID(ctx context.Context, id uint) (*models.User, error) {
+	for _, u := range m.Users {
+		if u.ID == id {
+			return u, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockUserRepo) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	for _, u := range m.Users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockUserRepo) FindByActivationToken(ctx context.Context, token string) (*models.User, error) {
+	for _, u := range m.Users {
+		if u.ActivationToken != nil && *u.ActivationToken == token {
+			return u, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockUserRepo) FindByOIDCSubject(ctx context.Context, issuer, subject string) (*models.User, error) {
+	for _, u := range m.Users {
+		if u.OIDCIssuer != nil && u.OIDCSubject != nil && *u.OIDCIssuer == issuer && *u.OIDCSubject == subject {
+			return u, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockUserRepo) FindByEmailChangeToken(ctx context.Context, token string) (*models.User, error) {
+	for _, u := range m.Users {
+		if u.EmailChangeToken != nil && *u.EmailChangeToken == token {
+			return u, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockUserRepo) FindDueForDeletion(ctx context.Context, now time.Time, limit int) ([]models.User, error) {
+	var users []models.User
+	for _, u := range m.Users {
+		if u.DeletionScheduledAt != nil && !u.DeletionScheduledAt.After(now) && len(users) < limit {
+			users = append(users, *u)
+		}
+	}
+	return users, nil
+}
+
+func (m *MockUserRepo) Create(ctx context.Context, user *models.User) error {
+	m.nextID++
+	user.ID = m.nextID
+	m.Users = append(m.Users, user)
+	return nil
+}
+
+func (m *MockUserRepo) Update(ctx context.Context, user *models.User) error { return nil }
+
+func (m *MockUserRepo) UpdateFields(ctx context.Context, user *models.User, fields ...string) error {
+	return nil
+}
+
+func (m *MockUserRepo) UseTOTPCounter(ctx context.Context, id uint, counter int64, at time.Time) (bool, error) {
+	user, err := m.FindByID(ctx, id)
+	if err != nil || user.TOTPLastCounter >= counter {
+		return false, nil
+	}
+	user.TOTPLastCounter = counter
+	return true, nil
+}
+
+func (m *MockUserRepo) DeleteDue(ctx context.Context, id uint, now time.Time) (bool, error) {
+	for i, u := range m.Users {
+		if u.ID == id {
+			if u.DeletionScheduledAt == nil || u.DeletionScheduledAt.After(now) {
+				return false, nil
+			}
+			m.Users = append(m.Users[:i], m.Users[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestAuthService_Register(t *testing.T) {
+	repo := &MockUserRepo{}
+	audit := &MockAuditRepo{}
+	authService := services.NewAuthService(repo)
+	authService.Audit = services.NewAuditService(audit)
+
+	user, err := authService.Register(context.Background(), "baru@test.com", "password123", "Baru")
+	require.NoError(t, err)
+	assert.False(t, user.IsActive, "User baru harus belum aktif")
+	assert.Equal(t, models.RoleUser, user.Role)
+	assert.NotNil(t, user.ActivationToken)
+	assert.NotEqual(t, "password123", user.PasswordHash)
+
+	_, err = authService.Register(context.Background(), "baru@test.com", "lainnya123", "Duplikat")
+	assert.ErrorIs(t, err, models.ErrEmailAlreadyRegistered)
+
+	require.Len(t, audit.Created, 1)
+	assert.Equal(t, models.AuditActionUserRegister, audit.Created[0].Action)
+}
+
+func TestAuthService_Activate(t *testing.T) {
+	repo := &MockUserRepo{}
+	authService := services.NewAuthService(repo)
+
+	user, err := authService.Register(context.Background(), "aktivasi@test.com", "password123", "Aktivasi")
+	require.NoError(t, err)
+	token := *user.ActivationToken
+
+	activated, err := authService.Activate(context.Background(), token)
+	require.NoError(t, err)
+	assert.True(t, activated.IsActive)
+	assert.Nil(t, activated.ActivationToken)
+
+	// Token hanya berlaku sekali
+	_, err = authService.Activate(context.Background(), token)
+	assert.ErrorIs(t, err, models.ErrActivationTokenInvalid)
+
+	_, err = authService.Activate(context.Background(), "")
+	assert.ErrorIs(t, err, models.ErrActivationTokenInvalid)
+}
+
+func TestAuthService_Login(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", "test-secret")
+
+	hash, err := utils.HashPassword("password123")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		email         string
+		password      string
+		expectedErr   error
+		expectedAudit string
+	}{
+		{"Success", "aktif@test.com", "password123", nil, models.AuditActionLoginSuccess},
+		{"Failure_WrongPassword", "aktif@test.com", "salah", models.ErrInvalidCredentials, models.AuditActionLoginFailure},
+		{"Failure_UnknownEmail", "tidakada@test.com", "password123", models.ErrInvalidCredentials, models.AuditActionLoginFailure},
+		{"Failure_Inactive", "pasif@test.com", "password123", models.ErrAccountNotActive, models.AuditActionLoginFailure},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MockUserRepo{}
+			repo.Create(context.Background(), &models.User{Email: "aktif@test.com", PasswordHash: hash, IsActive: true, Role: models.RoleAdmin})
+			repo.Create(context.Background(), &models.User{Email: "pasif@test.com", PasswordHash: hash, IsActive: false})
+			audit := &MockAuditRepo{}
+			authService := services.NewAuthService(repo)
+			authService.Audit = services.NewAuditService(audit)
+
+			result, err := authService.Login(context.Background(), tt.email, tt.password)
+			if tt.expectedErr != nil {
+				assert.True(t, errors.Is(err, tt.expectedErr), "Error tidak sesuai: %v", err)
+				assert.Nil(t, result)
+			} else {
+				require.NoError(t, err)
+				claims, err := utils.ValidateToken(result.Token)
+				require.NoError(t, err)
+				assert.Equal(t, models.RoleAdmin, claims.Role)
+			}
+
+			require.Len(t, audit.Created, 1)
+			assert.Equal(t, tt.expectedAudit, audit.Created[0].Action)
+			assert.Equal(t, tt.email, audit.Created[0].ActorEmail)
+		})
+	}
+}
+
+func TestAuthService_LoginLockout(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", "test-secret")
+
+	hash, err := utils.HashPassword("password123")
+	require.NoError(t, err)
+	repo := &MockUserRepo{}
+	repo.Create(context.Background(), &models.User{Email: "aktif@test.com", PasswordHash: hash, IsActive: true})
+	audit := &MockAuditRepo{}
+	authService := services.NewAuthService(repo)
+	authService.Audit = services.NewAuditService(audit)
+	guard := ratelimit.NewLoginGuard(ratelimit.NewMemoryStore(), ratelimit.Limit{Burst: 100, Period: time.Minute}, 3, time.Minute)
+	guard.BaseDelay = time.Millisecond
+	authService.Limiter = guard
+	ctx := context.Background()
+
+	// Login berhasil mengosongkan hitungan kegagalan sebelumnya
+	for _, password := range []string{"salah", "salah", "password123", "salah", "salah"} {
+		_, err := authService.Login(ctx, "aktif@test.com", password)
+		if password == "password123" {
+			require.NoError(t, err)
+		} else {
+			require.ErrorIs(t, err, models.ErrInvalidCredentials)
+		}
+	}
+
+	// Kegagalan ketiga berturut-turut mengunci akun, password benar pun ditolak
+	_, err = authService.Login(ctx, "aktif@test.com", "salah")
+	require.ErrorIs(t, err, models.ErrInvalidCredentials)
+	result, err := authService.Login(ctx, "aktif@test.com", "password123")
+	assert.Nil(t, result)
+	require.ErrorIs(t, err, models.ErrTooManyLoginAttempts)
+	var throttled *services.LoginThrottledError
+	require.ErrorAs(t, err, &throttled)
+	assert.Equal(t, time.Minute, throttled.RetryAfter.Round(time.Second))
+
+	last := audit.Created[len(audit.Created)-1]
+	assert.Equal(t, models.AuditActionLoginFailure, last.Action)
+	assert.Contains(t, string(last.After), "throttled")
+}
+
+func TestAuthService_LoginWithTOTP(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", "test-secret")
+
+	hash, err := utils.HashPassword("password123")
+	require.NoError(t, err)
+	repo := &MockUserRepo{}
+	user := &models.User{Email: "admin@test.com", PasswordHash: hash, IsActive: true, Role: models.RoleAdmin}
+	require.NoError(t, repo.Create(context.Background(), user))
+	audit := &MockAuditRepo{}
+	authService := services.NewAuthService(repo)
+	authService.Audit = services.NewAuditService(audit)
+	authService.MFA = services.NewMFAService(repo, &MockRecoveryCodeRepo{}, "Toko Test")
+	ctx := context.Background()
+
+	setup, err := authService.MFA.SetupTOTP(ctx, user.ID)
+	require.NoError(t, err)
+	code, err := totp.GenerateCode(setup.Secret, time.Now().Add(-30*time.Second))
+	require.NoError(t, err)
+	_, err = authService.MFA.EnableTOTP(ctx, user.ID, code)
+	require.NoError(t, err)
+
+	// Langkah 1: password benar hanya menghasilkan token tantangan
+	result, err := authService.Login(ctx, "admin@test.com", "password123")
+	require.NoError(t, err)
+	assert.Empty(t, result.Token)
+	require.NotEmpty(t, result.MFAToken)
+	_, err = utils.ValidateToken(result.MFAToken)
+	assert.Error(t, err, "Token tantangan tidak boleh dipakai sebagai access token")
+
+	// Langkah 2: kode salah, token asal-asalan, lalu kode benar
+	_, err = authService.LoginMFA(ctx, result.MFAToken, "000000")
+	assert.ErrorIs(t, err, models.ErrMFACodeInvalid)
+	_, err = authService.LoginMFA(ctx, "bukan-token", "000000")
+	assert.ErrorIs(t, err, models.ErrMFATokenInvalid)
+
+	code, err = totp.GenerateCode(setup.Secret, time.Now())
+	require.NoError(t, err)
+	final, err := authService.LoginMFA(ctx, result.MFAToken, code)
+	require.NoError(t, err)
+	claims, err := utils.ValidateToken(final.Token)
+	require.NoError(t, err)
+	assert.Equal(t, models.RoleAdmin, claims.Role)
+
+	actions := make([]string, 0, len(audit.Created))
+	for _, entry := range audit.Created {
+		actions = append(actions, entry.Action)
+	}
+	assert.Equal(t, []string{models.AuditActionLoginFailure, models.AuditActionLoginSuccess}, actions,
+		"Login baru dicatat berhasil setelah faktor kedua")
+}
+
+func TestAuthService_SetRoleAndResetPassword(t *testing.T) {
+	repo := &MockUserRepo{}
+	audit := &MockAuditRepo{}
+	authService := services.NewAuthService(repo)
+	authService.Audit = services.NewAuditService(audit)
+	ctx := context.Background()
+
+	_, err := authService.Register(ctx, "admin@test.com", "password123", "Admin")
+	require.NoError(t, err)
+
+	_, err = authService.SetRole(ctx, "admin@test.com", "superuser")
+	assert.ErrorIs(t, err, models.ErrInvalidRole)
+	user, err := authService.SetRole(ctx, "admin@test.com", models.RoleAdmin)
+	require.NoError(t, err)
+	assert.Equal(t, models.RoleAdmin, user.Role)
+
+	_, err = authService.ResetPassword(ctx, "admin@test.com", "pendek")
+	assert.ErrorIs(t, err, models.ErrPasswordTooShort)
+	user, err = authService.ResetPassword(ctx, "admin@test.com", "passwordbaru123")
+	require.NoError(t, err)
+	assert.NoError(t, utils.CheckPasswordHash(user.PasswordHash, "passwordbaru123"))
+
+	_, err = authService.SetRole(ctx, "tidakada@test.com", models.RoleAdmin)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	actions := make([]string, 0, len(audit.Created))
+	for _, entry := range audit.Created {
+		actions = append(actions, entry.Action)
+	}
+	assert.Equal(t, []string{models.AuditActionUserRegister, models.AuditActionUserSetRole, models.AuditActionPasswordReset}, actions)
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultHealthCheckTimeout membatasi durasi satu pemeriksaan agar probe tidak menggantung
+const defaultHealthCheckTimeout = 2 * time.Second
+
+// Status hasil pemeriksaan kesehatan
+const (
+	HealthStatusOK           = "ok"
+	HealthStatusFail         = "fail"
+	HealthStatusShuttingDown = "shutting_down"
+)
+
+// HealthCheck adalah satu dependensi yang harus sehat agar instance siap menerima trafik
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// CheckResult adalah hasil satu HealthCheck beserta latensinya
+type CheckResult struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// ReadinessReport adalah hasil /readyz
+type ReadinessReport struct {
+	Status string        `json:"status"`
+	Checks []CheckResult `json:"checks"`
+}
+
+// Ready bernilai true jika instance boleh menerima trafik
+func (r ReadinessReport) Ready() bool {
+	return r.Status == HealthStatusOK
+}
+
+// DBPoolStats adalah ringkasan sql.DBStats untuk endpoint status
+type DBPoolStats struct {
+	MaxOpenConnections int     `json:"max_open_connections"`
+	OpenConnections    int     `json:"open_connections"`
+	InUse              int     `json:"in_use"`
+	Idle               int     `json:"idle"`
+	WaitCount          int64   `json:"wait_count"`
+	WaitDurationMs     float64 `json:"wait_duration_ms"`
+	MaxIdleClosed      int64   `json:"max_idle_closed"`
+	MaxLifetimeClosed  int64   `json:"max_lifetime_closed"`
+}
+
+// SystemStatus adalah laporan lengkap untuk admin
+type SystemStatus struct {
+	ReadinessReport
+	Version   string       `json:"version"`
+	StartedAt time.Time    `json:"started_at"`
+	Uptime    string       `json:"uptime"`
+	DBPool    *DBPoolStats `json:"db_pool,omitempty"`
+}
+
+// HealthService menjalankan pemeriksaan liveness/readiness dan menyusun status sistem
+type HealthService struct {
+	Checks    []HealthCheck
+	Version   string
+	StartedAt time.Time
+	Timeout   time.Duration      // batas per pemeriksaan, default 2s
+	PoolStats func() sql.DBStats // opsional: statistik pool database untuk Status
+	Now       func() time.Time
+
+	shuttingDown atomic.Bool
+}
+
+// NewHealthService adalah konstruktor untuk HealthService
+func NewHealthService(version string, checks ...HealthCheck) *HealthService {
+	return &HealthService{
+		Checks:    checks,
+		Version:   version,
+		StartedAt: time.Now(),
+		Timeout:   defaultHealthCheckTimeout,
+		Now:       time.Now,
+	}
+}
+
+// SetShuttingDown menandai instance sedang graceful shutdown sehingga readiness menjadi
+// false dan load balancer berhenti mengirim trafik baru.
+func (s *HealthService) SetShuttingDown() {
+	s.shuttingDown.Store(true)
+}
+
+// Readiness menjalankan semua pemeriksaan secara paralel. Instance tidak siap jika
+// ada pemeriksaan yang gagal atau sedang shutdown.
+func (s *HealthService) Readiness(ctx context.Context) ReadinessReport {
+	results := make([]CheckResult, len(s.Checks))
+	var wg sync.WaitGroup
+	for i, check := range s.Checks {
+		wg.Add(1)
+		go func(i int, check HealthCheck) {
+			defer wg.Done()
+			results[i] = s.run(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	report := ReadinessReport{Status: HealthStatusOK, Checks: results}
+	for _, result := range results {
+		if result.Status != HealthStatusOK {
+			report.Status = HealthStatusFail
+		}
+	}
+	if s.shuttingDown.Load() {
+		report.Status = HealthStatusShuttingDown
+	}
+	return report
+}
+
+// Status menyusun laporan lengkap: readiness, versi build, uptime dan statistik pool database
+func (s *HealthService) Status(ctx context.Context) SystemStatus {
+	status := SystemStatus{
+		ReadinessReport: s.Readiness(ctx),
+		Version:         s.Version,
+		StartedAt:       s.StartedAt,
+		Uptime:          s.Now().Sub(s.StartedAt).Round(time.Second).String(),
+	}
+	if s.PoolStats != nil {
+		stats := s.PoolStats()
+		status.DBPool = &DBPoolStats{
+			MaxOpenConnections: stats.MaxOpenConnections,
+			OpenConnections:    stats.OpenConnections,
+			InUse:              stats.InUse,
+			Idle:               stats.Idle,
+			WaitCount:          stats.WaitCount,
+			WaitDurationMs:     milliseconds(stats.WaitDuration),
+			MaxIdleClosed:      stats.MaxIdleClosed,
+			MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+		}
+	}
+	return status
+}
+
+// run menjalankan satu pemeriksaan dengan batas waktu dan mengukur latensinya
+func (s *HealthService) run(ctx context.Context, check HealthCheck) CheckResult {
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = defaultHealthCheckTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.Check(ctx)
+	result := CheckResult{Name: check.Name, Status: HealthStatusOK, LatencyMs: milliseconds(time.Since(start))}
+	if err != nil {
+		result.Status = HealthStatusFail
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// milliseconds mengubah durasi menjadi milidetik pecahan untuk JSON
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,96 @@
+package services_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"fullstack-crud-project-01/backend-go/services"
+)
+
+func okCheck(name string) services.HealthCheck {
+	return services.HealthCheck{Name: name, Check: func(ctx context.Context) error { return nil }}
+}
+
+func TestHealthService_Readiness(t *testing.T) {
+	tests := []struct {
+		name         string
+		checks       []services.HealthCheck
+		shuttingDown bool
+		wantStatus   string
+		wantErrors   map[string]string
+	}{
+		{
+			name:       "Semua dependensi sehat",
+			checks:     []services.HealthCheck{okCheck("database"), okCheck("migrations")},
+			wantStatus: services.HealthStatusOK,
+		},
+		{
+			name: "Satu dependensi gagal",
+			checks: []services.HealthCheck{
+				okCheck("database"),
+				{Name: "migrations", Check: func(ctx context.Context) error { return errors.New("1 migrasi belum diterapkan") }},
+			},
+			wantStatus: services.HealthStatusFail,
+			wantErrors: map[string]string{"migrations": "1 migrasi belum diterapkan"},
+		},
+		{
+			name: "Pemeriksaan melewati batas waktu",
+			checks: []services.HealthCheck{
+				{Name: "database", Check: func(ctx context.Context) error {
+					<-ctx.Done()
+					return ctx.Err()
+				}},
+			},
+			wantStatus: services.HealthStatusFail,
+			wantErrors: map[string]string{"database": context.DeadlineExceeded.Error()},
+		},
+		{
+			name:         "Sedang shutdown",
+			checks:       []services.HealthCheck{okCheck("database")},
+			shuttingDown: true,
+			wantStatus:   services.HealthStatusShuttingDown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := services.NewHealthService("test", tt.checks...)
+			svc.Timeout = 20 * time.Millisecond
+			if tt.shuttingDown {
+				svc.SetShuttingDown()
+			}
+
+			report := svc.Readiness(context.Background())
+			assert.Equal(t, tt.wantStatus, report.Status)
+			assert.Equal(t, tt.wantStatus == services.HealthStatusOK, report.Ready())
+			require.Len(t, report.Checks, len(tt.checks))
+			for i, result := range report.Checks {
+				assert.Equal(t, tt.checks[i].Name, result.Name, "Urutan hasil mengikuti urutan pemeriksaan")
+				assert.Equal(t, tt.wantErrors[result.Name], result.Error)
+			}
+		})
+	}
+}
+
+func TestHealthService_Status(t *testing.T) {
+	svc := services.NewHealthService("v1.2.3", okCheck("database"))
+	svc.StartedAt = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	svc.Now = func() time.Time { return svc.StartedAt.Add(90 * time.Minute) }
+	svc.PoolStats = func() sql.DBStats {
+		return sql.DBStats{MaxOpenConnections: 10, OpenConnections: 3, InUse: 1, Idle: 2, WaitDuration: 1500 * time.Microsecond}
+	}
+
+	status := svc.Status(context.Background())
+	assert.Equal(t, "v1.2.3", status.Version)
+	assert.Equal(t, "1h30m0s", status.Uptime)
+	assert.True(t, status.Ready())
+	require.NotNil(t, status.DBPool)
+	assert.Equal(t, 3, status.DBPool.OpenConnections)
+	assert.Equal(t, 1.5, status.DBPool.WaitDurationMs)
+}
@@ -0,0 +1,11 @@
+package services
+
+import "context"
+
+// Leader memastikan pekerjaan latar hanya berjalan di satu instance walaupun aplikasi
+// berjalan di beberapa replika. *database.LeaderLock memenuhi interface ini.
+type Leader interface {
+	// Acquire mengembalikan true selama pemanggil adalah leader; dipanggil setiap putaran.
+	Acquire(ctx context.Context) (bool, error)
+	Release(ctx context.Context) error
+}
@@ -0,0 +1,347 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/hex"
+	"image/png"
+	"strings"
+	"time"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+
+	"fullstack-crud-project-01/backend-go/models"
+)
+
+// Pengaturan TOTP (RFC 6238) dan kode pemulihan
+const (
+	totpPeriod         = 30 // detik per kode, sama dengan aplikasi authenticator umumnya
+	totpSkew           = 1  // toleransi satu periode sebelum/sesudah untuk selisih jam
+	totpQRSize         = 256
+	recoveryCodeCount  = 10
+	recoveryCodeLength = 10 // karakter base32, ditampilkan sebagai xxxxx-xxxxx
+)
+
+// RecoveryCodeRepository mendefinisikan operasi penyimpanan kode pemulihan ("port").
+// Kode hanya disimpan sebagai hash.
+type RecoveryCodeRepository interface {
+	// Replace menghapus semua kode pengguna lalu menyimpan hash baru (nil = hapus saja)
+	Replace(ctx context.Context, userID uint, hashes []string) error
+	// Use menandai kode terpakai; false jika kode tidak ada atau sudah pernah dipakai
+	Use(ctx context.Context, userID uint, hash string, usedAt time.Time) (bool, error)
+	// CountUnused menghitung kode yang belum dipakai
+	CountUnused(ctx context.Context, userID uint) (int, error)
+}
+
+// TOTPSetup adalah data pendaftaran aplikasi authenticator
+type TOTPSetup struct {
+	Secret string // Secret base32 untuk dimasukkan manual
+	URL    string // URI otpauth://totp/...
+	QRCode []byte // PNG berisi URL
+}
+
+// MFAStatus adalah ringkasan autentikasi dua faktor seorang pengguna
+type MFAStatus struct {
+	TOTPEnabled            bool
+	RecoveryCodesRemaining int
+}
+
+// MFAService mengelola TOTP (pendaftaran, aktivasi, verifikasi) dan kode pemulihan.
+type MFAService struct {
+	Users         UserRepository
+	RecoveryCodes RecoveryCodeRepository
+	Issuer        string           // Nama layanan yang tampil di aplikasi authenticator
+	Audit         *AuditService    // Opsional: nil berarti perubahan MFA tidak diaudit
+	Tx            TxManager        // Opsional: nil berarti status TOTP dan kode pemulihan disimpan tanpa transaksi
+	Now           func() time.Time // Dapat diganti saat pengujian
+}
+
+// NewMFAService adalah konstruktor untuk MFAService.
+func NewMFAService(users UserRepository, codes RecoveryCodeRepository, issuer string) *MFAService {
+	return &MFAService{Users: users, RecoveryCodes: codes, Issuer: issuer, Now: time.Now}
+}
+
+// SetupTOTP membuat secret baru untuk pengguna yang belum mengaktifkan TOTP. Secret baru
+// berlaku setelah dikonfirmasi lewat EnableTOTP; memanggil ulang menggantikan secret lama.
+func (s *MFAService) SetupTOTP(ctx context.Context, userID uint) (*TOTPSetup, error) {
+	user, err := s.Users.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		return nil, models.ErrMFAAlreadyEnabled
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      s.Issuer,
+		AccountName: user.Email,
+		Period:      totpPeriod,
+	})
+	if err != nil {
+		return nil, err
+	}
+	img, err := key.Image(totpQRSize, totpQRSize)
+	if err != nil {
+		return nil, err
+	}
+	var qr bytes.Buffer
+	if err := png.Encode(&qr, img); err != nil {
+		return nil, err
+	}
+
+	secret := key.Secret()
+	user.TOTPSecret = &secret
+	user.TOTPLastCounter = 0
+	user.UpdatedAt = s.Now()
+	if err := s.Users.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	return &TOTPSetup{Secret: secret, URL: key.URL(), QRCode: qr.Bytes()}, nil
+}
+
+// EnableTOTP mengaktifkan TOTP setelah kode pertama dari authenticator terverifikasi dan
+// mengembalikan kode pemulihan baru. Kode pemulihan hanya ditampilkan sekali.
+func (s *MFAService) EnableTOTP(ctx context.Context, userID uint, code string) ([]string, error) {
+	user, err := s.Users.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		return nil, models.ErrMFAAlreadyEnabled
+	}
+	if user.TOTPSecret == nil {
+		return nil, models.ErrMFANotSetUp
+	}
+	if err := s.useTOTP(ctx, user, normalizeMFACode(code)); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	// TOTP hanya aktif jika kode pemulihannya ikut tersimpan
+	user.TOTPEnabled = true
+	user.UpdatedAt = s.Now()
+	if err := s.saveMFA(ctx, user, hashes); err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, user, models.AuditActionMFAEnable)
+	return codes, nil
+}
+
+// DisableTOTP mematikan TOTP dan menghapus kode pemulihan. Butuh kode TOTP atau kode
+// pemulihan yang valid agar token yang dicuri saja tidak cukup untuk mematikannya.
+func (s *MFAService) DisableTOTP(ctx context.Context, userID uint, code string) error {
+	user, err := s.enabledUser(ctx, userID, code)
+	if err != nil {
+		return err
+	}
+
+	user.TOTPEnabled = false
+	user.TOTPSecret = nil
+	user.TOTPLastCounter = 0
+	user.UpdatedAt = s.Now()
+	if err := s.saveMFA(ctx, user, nil); err != nil {
+		return err
+	}
+
+	s.audit(ctx, user, models.AuditActionMFADisable)
+	return nil
+}
+
+// RegenerateRecoveryCodes mengganti semua kode pemulihan; kode lama tidak berlaku lagi.
+func (s *MFAService) RegenerateRecoveryCodes(ctx context.Context, userID uint, code string) ([]string, error) {
+	user, err := s.enabledUser(ctx, userID, code)
+	if err != nil {
+		return nil, err
+	}
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.RecoveryCodes.Replace(ctx, user.ID, hashes); err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, user, models.AuditActionMFARecovery)
+	return codes, nil
+}
+
+// Status mengembalikan status TOTP dan sisa kode pemulihan pengguna.
+func (s *MFAService) Status(ctx context.Context, userID uint) (*MFAStatus, error) {
+	user, err := s.Users.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	status := &MFAStatus{TOTPEnabled: user.TOTPEnabled}
+	if user.TOTPEnabled {
+		if status.RecoveryCodesRemaining, err = s.RecoveryCodes.CountUnused(ctx, user.ID); err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+// Verify memeriksa kode TOTP (6 digit) atau kode pemulihan milik pengguna dengan TOTP aktif.
+// Kode TOTP yang sudah dipakai dan kode pemulihan bekas ditolak dengan ErrMFACodeInvalid.
+func (s *MFAService) Verify(ctx context.Context, user *models.User, code string) error {
+	if !user.TOTPEnabled || user.TOTPSecret == nil {
+		return models.ErrMFANotEnabled
+	}
+
+	code = normalizeMFACode(code)
+	if isTOTPCode(code) {
+		return s.useTOTP(ctx, user, code)
+	}
+
+	used, err := s.RecoveryCodes.Use(ctx, user.ID, hashRecoveryCode(code), s.Now())
+	if err != nil {
+		return err
+	}
+	if !used {
+		return models.ErrMFACodeInvalid
+	}
+	return nil
+}
+
+// enabledUser mengambil pengguna dengan TOTP aktif dan memverifikasi kodenya
+func (s *MFAService) enabledUser(ctx context.Context, userID uint, code string) (*models.User, error) {
+	user, err := s.Users.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Verify(ctx, user, code); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// useTOTP memverifikasi kode TOTP lalu mencatat periodenya secara atomik. Dua request
+// bersamaan dengan kode yang sama hanya diterima sekali.
+func (s *MFAService) useTOTP(ctx context.Context, user *models.User, code string) error {
+	counter, ok := s.checkTOTP(user, code)
+	if !ok {
+		return models.ErrMFACodeInvalid
+	}
+	now := s.Now()
+	used, err := s.Users.UseTOTPCounter(ctx, user.ID, counter, now)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return models.ErrMFACodeInvalid
+	}
+	user.TOTPLastCounter = counter
+	user.UpdatedAt = now
+	return nil
+}
+
+// checkTOTP membandingkan kode dengan periode saat ini dan ±totpSkew periode, dan
+// mengembalikan periode yang cocok. Periode yang tidak lebih baru dari user.TOTPLastCounter
+// dilewati sehingga kode yang sudah dipakai ditolak.
+func (s *MFAService) checkTOTP(user *models.User, code string) (int64, bool) {
+	if user.TOTPSecret == nil || !isTOTPCode(code) {
+		return 0, false
+	}
+	now := s.Now()
+	for offset := -totpSkew; offset <= totpSkew; offset++ {
+		at := now.Add(time.Duration(offset*totpPeriod) * time.Second)
+		counter := at.Unix() / totpPeriod
+		if counter <= user.TOTPLastCounter {
+			continue
+		}
+		expected, err := totp.GenerateCodeCustom(*user.TOTPSecret, at, totp.ValidateOpts{
+			Period:    totpPeriod,
+			Digits:    otp.DigitsSix,
+			Algorithm: otp.AlgorithmSHA1,
+		})
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return counter, true
+		}
+	}
+	return 0, false
+}
+
+// saveMFA menyimpan status TOTP pengguna dan mengganti kode pemulihannya dalam satu
+// transaksi melalui TxManager jika tersedia
+func (s *MFAService) saveMFA(ctx context.Context, user *models.User, hashes []string) error {
+	save := func(ctx context.Context, users UserRepository, codes RecoveryCodeRepository) error {
+		if err := users.Update(ctx, user); err != nil {
+			return err
+		}
+		return codes.Replace(ctx, user.ID, hashes)
+	}
+	if s.Tx == nil {
+		return save(ctx, s.Users, s.RecoveryCodes)
+	}
+	return s.Tx.WithinTransaction(ctx, func(ctx context.Context, repos Repositories) error {
+		return save(ctx, repos.Users, repos.RecoveryCodes)
+	})
+}
+
+// audit mencatat perubahan MFA; kegagalan hanya di-log
+func (s *MFAService) audit(ctx context.Context, user *models.User, action string) {
+	entry := NewAuditEntry(action, models.AuditResourceUser, formatResourceID(user.ID), nil, nil)
+	if err := s.Audit.Record(ctx, entry); err != nil {
+		logAuditFailure(ctx, entry, err)
+	}
+}
+
+// newRecoveryCodes membuat kode pemulihan baru beserta hash-nya untuk disimpan
+func newRecoveryCodes() (codes, hashes []string, err error) {
+	codes = make([]string, recoveryCodeCount)
+	hashes = make([]string, recoveryCodeCount)
+	for i := range codes {
+		code, err := newRecoveryCode()
+		if err != nil {
+			return nil, nil, err
+		}
+		codes[i] = code[:recoveryCodeLength/2] + "-" + code[recoveryCodeLength/2:]
+		hashes[i] = hashRecoveryCode(code)
+	}
+	return codes, hashes, nil
+}
+
+// newRecoveryCode membuat kode acak base32 huruf kecil (50 bit entropi)
+func newRecoveryCode() (string, error) {
+	buf := make([]byte, 10)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return strings.ToLower(base32.StdEncoding.EncodeToString(buf))[:recoveryCodeLength], nil
+}
+
+// hashRecoveryCode meng-hash kode pemulihan. SHA-256 cukup (tanpa bcrypt) karena kode
+// acak berentropi tinggi, dan memungkinkan pencarian langsung berdasarkan hash.
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeMFACode membuang spasi dan tanda hubung yang biasa diketik pengguna
+func normalizeMFACode(code string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "-", "").Replace(code))
+}
+
+// isTOTPCode memeriksa apakah kode berupa 6 digit
+func isTOTPCode(code string) bool {
+	if len(code) != 6 {
+		return false
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
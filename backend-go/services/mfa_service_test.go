@@ -0,0 +1,175 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/services"
+)
+
+// MockRecoveryCodeRepo adalah implementasi in-memory dari services.RecoveryCodeRepository
+type MockRecoveryCodeRepo struct {
+	Codes map[uint]map[string]bool // userID -> hash -> sudah dipakai
+}
+
+func (m *MockRecoveryCodeRepo) Replace(ctx context.Context, userID uint, hashes []string) error {
+	if m.Codes == nil {
+		m.Codes = map[uint]map[string]bool{}
+	}
+	m.Codes[userID] = map[string]bool{}
+	for _, hash := range hashes {
+		m.Codes[userID][hash] = false
+	}
+	return nil
+}
+
+func (m *MockRecoveryCodeRepo) Use(ctx context.Context, userID uint, hash string, usedAt time.Time) (bool, error) {
+	used, ok := m.Codes[userID][hash]
+	if !ok || used {
+		return false, nil
+	}
+	m.Codes[userID][hash] = true
+	return true, nil
+}
+
+func (m *MockRecoveryCodeRepo) CountUnused(ctx context.Context, userID uint) (int, error) {
+	count := 0
+	for _, used := range m.Codes[userID] {
+		if !used {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// FailingRecoveryCodeRepo menggagalkan Replace untuk menguji rollback
+type FailingRecoveryCodeRepo struct {
+	MockRecoveryCodeRepo
+}
+
+func (m *FailingRecoveryCodeRepo) Replace(ctx context.Context, userID uint, hashes []string) error {
+	return errors.New("gagal menyimpan kode pemulihan")
+}
+
+// newMFAFixture menyiapkan MFAService dengan jam tetap dan satu pengguna aktif
+func newMFAFixture(t *testing.T) (*services.MFAService, *models.User, *time.Time, *MockAuditRepo) {
+	t.Helper()
+	repo := &MockUserRepo{}
+	user := &models.User{Email: "admin@test.com", IsActive: true, Role: models.RoleAdmin}
+	require.NoError(t, repo.Create(context.Background(), user))
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	audit := &MockAuditRepo{}
+	svc := services.NewMFAService(repo, &MockRecoveryCodeRepo{}, "Toko Test")
+	svc.Audit = services.NewAuditService(audit)
+	svc.Now = func() time.Time { return now }
+	return svc, user, &now, audit
+}
+
+func TestMFAService_EnrollAndVerify(t *testing.T) {
+	svc, user, now, audit := newMFAFixture(t)
+	ctx := context.Background()
+
+	setup, err := svc.SetupTOTP(ctx, user.ID)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(setup.URL, "otpauth://totp/Toko%20Test:admin@test.com?"), setup.URL)
+	assert.Contains(t, setup.URL, "secret="+setup.Secret)
+	_, err = png.Decode(bytes.NewReader(setup.QRCode))
+	require.NoError(t, err, "QR code harus berupa PNG")
+	assert.False(t, user.TOTPEnabled, "TOTP belum aktif sebelum dikonfirmasi")
+
+	_, err = svc.EnableTOTP(ctx, user.ID, "000000")
+	assert.ErrorIs(t, err, models.ErrMFACodeInvalid)
+
+	code, err := totp.GenerateCode(setup.Secret, *now)
+	require.NoError(t, err)
+	recoveryCodes, err := svc.EnableTOTP(ctx, user.ID, code)
+	require.NoError(t, err)
+	assert.True(t, user.TOTPEnabled)
+	assert.Len(t, recoveryCodes, 10)
+	assert.Regexp(t, `^[a-z2-7]{5}-[a-z2-7]{5}$`, recoveryCodes[0])
+
+	_, err = svc.SetupTOTP(ctx, user.ID)
+	assert.ErrorIs(t, err, models.ErrMFAAlreadyEnabled)
+
+	// Kode yang sama tidak bisa dipakai ulang dalam periode yang sama
+	assert.ErrorIs(t, svc.Verify(ctx, user, code), models.ErrMFACodeInvalid)
+
+	// Kode periode berikutnya diterima, juga dengan selisih jam satu periode
+	*now = now.Add(30 * time.Second)
+	next, err := totp.GenerateCode(setup.Secret, now.Add(30*time.Second))
+	require.NoError(t, err)
+	assert.NoError(t, svc.Verify(ctx, user, next))
+
+	// Kode pemulihan hanya berlaku sekali; huruf besar dan tanpa tanda hubung tetap diterima
+	recovery := strings.ToUpper(strings.ReplaceAll(recoveryCodes[0], "-", ""))
+	assert.NoError(t, svc.Verify(ctx, user, recovery))
+	assert.ErrorIs(t, svc.Verify(ctx, user, recovery), models.ErrMFACodeInvalid)
+
+	status, err := svc.Status(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, &services.MFAStatus{TOTPEnabled: true, RecoveryCodesRemaining: 9}, status)
+
+	require.NoError(t, svc.DisableTOTP(ctx, user.ID, recoveryCodes[1]))
+	assert.False(t, user.TOTPEnabled)
+	assert.Nil(t, user.TOTPSecret)
+	assert.ErrorIs(t, svc.DisableTOTP(ctx, user.ID, recoveryCodes[2]), models.ErrMFANotEnabled)
+
+	actions := make([]string, 0, len(audit.Created))
+	for _, entry := range audit.Created {
+		actions = append(actions, entry.Action)
+	}
+	assert.Equal(t, []string{models.AuditActionMFAEnable, models.AuditActionMFADisable}, actions)
+}
+
+func TestMFAService_RegenerateRecoveryCodes(t *testing.T) {
+	svc, user, now, _ := newMFAFixture(t)
+	ctx := context.Background()
+
+	_, err := svc.EnableTOTP(ctx, user.ID, "123456")
+	assert.ErrorIs(t, err, models.ErrMFANotSetUp)
+
+	setup, err := svc.SetupTOTP(ctx, user.ID)
+	require.NoError(t, err)
+	code, err := totp.GenerateCode(setup.Secret, *now)
+	require.NoError(t, err)
+	oldCodes, err := svc.EnableTOTP(ctx, user.ID, code)
+	require.NoError(t, err)
+
+	newCodes, err := svc.RegenerateRecoveryCodes(ctx, user.ID, oldCodes[0])
+	require.NoError(t, err)
+	assert.Len(t, newCodes, 10)
+	assert.ErrorIs(t, svc.Verify(ctx, user, oldCodes[1]), models.ErrMFACodeInvalid, "Kode lama tidak berlaku lagi")
+	assert.NoError(t, svc.Verify(ctx, user, newCodes[0]))
+}
+
+func TestMFAService_EnableTOTPRollsBackWhenRecoveryCodesFail(t *testing.T) {
+	svc, user, now, audit := newMFAFixture(t)
+	ctx := context.Background()
+
+	setup, err := svc.SetupTOTP(ctx, user.ID)
+	require.NoError(t, err)
+
+	// Status TOTP dan kode pemulihan harus ditulis lewat repository transaksi
+	tx := &MockTxManager{Repos: services.Repositories{Users: svc.Users, RecoveryCodes: &FailingRecoveryCodeRepo{}}}
+	svc.Tx = tx
+	code, err := totp.GenerateCode(setup.Secret, *now)
+	require.NoError(t, err)
+
+	codes, err := svc.EnableTOTP(ctx, user.ID, code)
+	assert.Error(t, err)
+	assert.Nil(t, codes, "Kode pemulihan yang tidak tersimpan tidak boleh ditampilkan")
+	assert.Equal(t, 1, tx.RolledBack)
+	assert.Equal(t, 0, tx.Committed)
+	assert.Empty(t, audit.Created, "Aktivasi yang gagal tidak diaudit")
+}
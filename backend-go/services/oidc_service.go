@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/utils"
+)
+
+// OIDCAuthRequest adalah awal alur login OIDC: browser diarahkan ke URL, sedangkan
+// FlowToken (state, nonce, PKCE verifier yang ditandatangani) disimpan di cookie.
+type OIDCAuthRequest struct {
+	URL       string
+	FlowToken string
+}
+
+// OIDCService menjalankan login OpenID Connect (authorization code + PKCE) terhadap satu
+// penyedia identitas. Endpoint penyedia dibaca lewat discovery saat pertama dipakai, ID
+// token diverifikasi terhadap JWKS penyedia, lalu identitasnya diserahkan ke
+// AuthService.LoginOIDC yang menerbitkan JWT milik aplikasi ini.
+type OIDCService struct {
+	Auth           *AuthService
+	IssuerURL      string
+	ClientID       string
+	ClientSecret   string   // Kosong untuk public client (hanya PKCE)
+	RedirectURL    string   // URL callback yang terdaftar di penyedia identitas
+	AllowedDomains []string // Opsional: kosong berarti semua domain email diterima
+	AutoProvision  bool     // Buat akun baru untuk email yang belum terdaftar
+	HTTPClient     *http.Client
+
+	mu       sync.Mutex
+	provider *oidc.Provider
+}
+
+// NewOIDCService adalah konstruktor untuk OIDCService. AutoProvision aktif secara default.
+func NewOIDCService(auth *AuthService, issuerURL, clientID, clientSecret, redirectURL string) *OIDCService {
+	return &OIDCService{
+		Auth:          auth,
+		IssuerURL:     issuerURL,
+		ClientID:      clientID,
+		ClientSecret:  clientSecret,
+		RedirectURL:   redirectURL,
+		AutoProvision: true,
+		HTTPClient:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Begin memulai alur login dengan state, nonce dan PKCE verifier baru.
+func (s *OIDCService) Begin(ctx context.Context) (*OIDCAuthRequest, error) {
+	provider, err := s.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := randomOIDCValue()
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := randomOIDCValue()
+	if err != nil {
+		return nil, err
+	}
+	verifier := oauth2.GenerateVerifier()
+	flowToken, err := utils.GenerateOIDCFlowToken(state, nonce, verifier)
+	if err != nil {
+		return nil, err
+	}
+
+	authURL := s.oauth2Config(provider).AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier))
+	return &OIDCAuthRequest{URL: authURL, FlowToken: flowToken}, nil
+}
+
+// Complete menyelesaikan alur login di callback: state dicocokkan dengan flowToken dari
+// cookie, code ditukar (dengan PKCE verifier), dan ID token diverifikasi sebelum login.
+func (s *OIDCService) Complete(ctx context.Context, flowToken, state, code string) (*LoginResult, error) {
+	flow, err := utils.ValidateOIDCFlowToken(flowToken)
+	if err != nil || state == "" || subtle.ConstantTimeCompare([]byte(state), []byte(flow.State)) != 1 {
+		return nil, models.ErrOIDCStateInvalid
+	}
+
+	identity, err := s.exchange(ctx, flow, code)
+	if err != nil {
+		return nil, err
+	}
+	if !s.domainAllowed(identity.Email) {
+		s.Auth.auditLogin(ctx, models.AuditActionLoginFailure, nil, identity.Email, "oidc_domain_not_allowed")
+		return nil, models.ErrOIDCDomainNotAllowed
+	}
+	return s.Auth.LoginOIDC(ctx, *identity, s.AutoProvision)
+}
+
+// exchange menukar authorization code dan memverifikasi ID token (signature lewat JWKS,
+// issuer, audience, masa berlaku dan nonce)
+func (s *OIDCService) exchange(ctx context.Context, flow *utils.OIDCFlowClaims, code string) (*OIDCIdentity, error) {
+	provider, err := s.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ctx = oidc.ClientContext(ctx, s.HTTPClient)
+
+	token, err := s.oauth2Config(provider).Exchange(ctx, code, oauth2.VerifierOption(flow.Verifier))
+	if err != nil {
+		slog.WarnContext(ctx, "Penukaran authorization code OIDC gagal", "error", err)
+		return nil, fmt.Errorf("%w: %v", models.ErrOIDCLoginFailed, err)
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: response token tanpa id_token", models.ErrOIDCLoginFailed)
+	}
+
+	idToken, err := provider.Verifier(&oidc.Config{ClientID: s.ClientID}).Verify(ctx, rawIDToken)
+	if err != nil {
+		slog.WarnContext(ctx, "ID token OIDC ditolak", "error", err)
+		return nil, fmt.Errorf("%w: %v", models.ErrOIDCLoginFailed, err)
+	}
+	if subtle.ConstantTimeCompare([]byte(idToken.Nonce), []byte(flow.Nonce)) != 1 {
+		return nil, fmt.Errorf("%w: nonce tidak cocok", models.ErrOIDCLoginFailed)
+	}
+
+	var claims struct {
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		Name          string `json:"name"`
+	}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrOIDCLoginFailed, err)
+	}
+	if claims.Email == "" || !claims.EmailVerified {
+		return nil, models.ErrOIDCEmailNotVerified
+	}
+
+	return &OIDCIdentity{
+		Issuer:  idToken.Issuer,
+		Subject: idToken.Subject,
+		Email:   strings.ToLower(strings.TrimSpace(claims.Email)),
+		Name:    claims.Name,
+	}, nil
+}
+
+// discover membaca konfigurasi penyedia identitas sekali lalu menyimpannya. Kegagalan
+// tidak disimpan sehingga penyedia yang sempat tidak tersedia dicoba lagi di request berikutnya.
+func (s *OIDCService) discover(ctx context.Context) (*oidc.Provider, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.provider != nil {
+		return s.provider, nil
+	}
+
+	provider, err := oidc.NewProvider(oidc.ClientContext(ctx, s.HTTPClient), s.IssuerURL)
+	if err != nil {
+		return nil, fmt.Errorf("discovery OIDC %s gagal: %w", s.IssuerURL, err)
+	}
+	s.provider = provider
+	return provider, nil
+}
+
+func (s *OIDCService) oauth2Config(provider *oidc.Provider) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     s.ClientID,
+		ClientSecret: s.ClientSecret,
+		RedirectURL:  s.RedirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       []string{oidc.ScopeOpenID, "email", "profile"},
+	}
+}
+
+// domainAllowed mengecek domain email terhadap AllowedDomains
+func (s *OIDCService) domainAllowed(email string) bool {
+	if len(s.AllowedDomains) == 0 {
+		return true
+	}
+	_, domain, ok := strings.Cut(email, "@")
+	if !ok {
+		return false
+	}
+	for _, allowed := range s.AllowedDomains {
+		if strings.EqualFold(domain, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// randomOIDCValue membuat nilai acak untuk state dan nonce (256 bit, base64url)
+func randomOIDCValue() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,165 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/services"
+	"fullstack-crud-project-01/backend-go/services/oidctest"
+	"fullstack-crud-project-01/backend-go/utils"
+)
+
+const oidcRedirectURL = "http://app.test/api/v1/auth/oidc/callback"
+
+func newOIDCTestService(t *testing.T) (*services.OIDCService, *oidctest.Provider, *MockUserRepo, *MockAuditRepo) {
+	t.Setenv("JWT_SECRET_KEY", "test-secret")
+	provider := oidctest.NewProvider(t, "backend", "rahasia-client")
+	users := &MockUserRepo{}
+	audit := &MockAuditRepo{}
+	auth := services.NewAuthService(users)
+	auth.Audit = services.NewAuditService(audit)
+	svc := services.NewOIDCService(auth, provider.Issuer(), "backend", "rahasia-client", oidcRedirectURL)
+	return svc, provider, users, audit
+}
+
+// loginOIDC menjalankan satu alur lengkap: Begin, persetujuan di penyedia, lalu Complete
+func loginOIDC(t *testing.T, svc *services.OIDCService, provider *oidctest.Provider) (*services.LoginResult, error) {
+	t.Helper()
+	req, err := svc.Begin(context.Background())
+	require.NoError(t, err)
+	callback := provider.Authorize(t, req.URL)
+	assert.Equal(t, "app.test", callback.Host)
+	return svc.Complete(context.Background(), req.FlowToken, callback.Query().Get("state"), callback.Query().Get("code"))
+}
+
+func TestOIDCService_ProvisionsThenRecognisesUser(t *testing.T) {
+	svc, provider, users, audit := newOIDCTestService(t)
+	provider.SetIdentity(oidctest.Identity{Subject: "abc-123", Email: "Baru@Example.com", EmailVerified: true, Name: "Baru"})
+
+	result, err := loginOIDC(t, svc, provider)
+	require.NoError(t, err)
+	require.Len(t, users.Users, 1)
+	user := users.Users[0]
+	assert.Equal(t, "baru@example.com", user.Email)
+	assert.Equal(t, "Baru", user.Name)
+	assert.True(t, user.IsActive)
+	assert.Equal(t, models.RoleUser, user.Role)
+	assert.Equal(t, provider.Issuer(), *user.OIDCIssuer)
+	assert.Equal(t, "abc-123", *user.OIDCSubject)
+
+	claims, err := utils.ValidateToken(result.Token)
+	require.NoError(t, err, "JWT milik aplikasi diterbitkan")
+	assert.Equal(t, user.ID, claims.UserID)
+
+	// Login berikutnya dikenali lewat iss+sub walaupun email di penyedia berubah
+	provider.SetIdentity(oidctest.Identity{Subject: "abc-123", Email: "ganti@example.com", EmailVerified: true})
+	_, err = loginOIDC(t, svc, provider)
+	require.NoError(t, err)
+	assert.Len(t, users.Users, 1)
+	assert.Equal(t, models.AuditActionUserRegister, audit.Created[0].Action)
+}
+
+func TestOIDCService_LinksExistingAccountByEmail(t *testing.T) {
+	svc, provider, users, audit := newOIDCTestService(t)
+	existing := &models.User{Email: "lama@example.com", IsActive: true, Role: models.RoleAdmin}
+	require.NoError(t, users.Create(context.Background(), existing))
+	provider.SetIdentity(oidctest.Identity{Subject: "sub-lama", Email: "lama@example.com", EmailVerified: true})
+
+	result, err := loginOIDC(t, svc, provider)
+	require.NoError(t, err)
+	assert.Equal(t, existing.ID, result.User.ID)
+	assert.Equal(t, "sub-lama", *existing.OIDCSubject)
+	assert.Equal(t, models.AuditActionOIDCLink, audit.Created[0].Action)
+
+	// Identitas lain dengan email yang sama tidak boleh mengambil alih akun
+	provider.SetIdentity(oidctest.Identity{Subject: "sub-lain", Email: "lama@example.com", EmailVerified: true})
+	_, err = loginOIDC(t, svc, provider)
+	assert.ErrorIs(t, err, models.ErrOIDCIdentityConflict)
+}
+
+func TestOIDCService_LinkedUserWithTOTPNeedsSecondStep(t *testing.T) {
+	svc, provider, users, _ := newOIDCTestService(t)
+	require.NoError(t, users.Create(context.Background(), &models.User{Email: "user@example.com", IsActive: true, TOTPEnabled: true}))
+
+	result, err := loginOIDC(t, svc, provider)
+	require.NoError(t, err)
+	assert.Empty(t, result.Token)
+	assert.NotEmpty(t, result.MFAToken)
+}
+
+func TestOIDCService_Rejections(t *testing.T) {
+	t.Run("EmailBelumDiverifikasi", func(t *testing.T) {
+		svc, provider, users, _ := newOIDCTestService(t)
+		provider.SetIdentity(oidctest.Identity{Subject: "x", Email: "x@example.com"})
+		_, err := loginOIDC(t, svc, provider)
+		assert.ErrorIs(t, err, models.ErrOIDCEmailNotVerified)
+		assert.Empty(t, users.Users)
+	})
+	t.Run("DomainTidakDiizinkan", func(t *testing.T) {
+		svc, provider, users, audit := newOIDCTestService(t)
+		svc.AllowedDomains = []string{"perusahaan.co.id"}
+		_, err := loginOIDC(t, svc, provider)
+		assert.ErrorIs(t, err, models.ErrOIDCDomainNotAllowed)
+		assert.Empty(t, users.Users)
+		require.Len(t, audit.Created, 1)
+		assert.Equal(t, models.AuditActionLoginFailure, audit.Created[0].Action)
+	})
+	t.Run("TanpaAutoProvision", func(t *testing.T) {
+		svc, provider, users, _ := newOIDCTestService(t)
+		svc.AutoProvision = false
+		_, err := loginOIDC(t, svc, provider)
+		assert.ErrorIs(t, err, models.ErrOIDCAccountNotFound)
+		assert.Empty(t, users.Users)
+	})
+	t.Run("AkunNonaktif", func(t *testing.T) {
+		svc, provider, users, _ := newOIDCTestService(t)
+		// Akun belum aktif bisa didaftarkan penyerang dengan email korban: identitas tidak dihubungkan
+		pending := &models.User{Email: "user@example.com"}
+		require.NoError(t, users.Create(context.Background(), pending))
+		_, err := loginOIDC(t, svc, provider)
+		assert.ErrorIs(t, err, models.ErrAccountNotActive)
+		assert.Nil(t, pending.OIDCIssuer)
+		assert.Nil(t, pending.OIDCSubject)
+	})
+}
+
+func TestOIDCService_Complete_RejectsForgedOrReplayedCallbacks(t *testing.T) {
+	svc, provider, _, _ := newOIDCTestService(t)
+	ctx := context.Background()
+
+	req, err := svc.Begin(ctx)
+	require.NoError(t, err)
+	callback := provider.Authorize(t, req.URL)
+	state, code := callback.Query().Get("state"), callback.Query().Get("code")
+
+	// State dari callback harus cocok dengan cookie alur milik browser yang sama
+	other, err := svc.Begin(ctx)
+	require.NoError(t, err)
+	_, err = svc.Complete(ctx, other.FlowToken, state, code)
+	assert.ErrorIs(t, err, models.ErrOIDCStateInvalid)
+	_, err = svc.Complete(ctx, "bukan-token", state, code)
+	assert.ErrorIs(t, err, models.ErrOIDCStateInvalid)
+
+	// PKCE: code tidak bisa ditukar dengan verifier dari alur lain. Penyedia tiruan
+	// membuang code setelah percobaan pertama, jadi alur asli pun gagal setelahnya.
+	otherCallback := provider.Authorize(t, other.URL)
+	_, err = svc.Complete(ctx, other.FlowToken, otherCallback.Query().Get("state"), code)
+	assert.ErrorIs(t, err, models.ErrOIDCLoginFailed)
+	_, err = svc.Complete(ctx, req.FlowToken, state, code)
+	assert.ErrorIs(t, err, models.ErrOIDCLoginFailed, "Code sekali pakai")
+
+	// Alur kedua yang sah tetap berhasil
+	_, err = svc.Complete(ctx, other.FlowToken, otherCallback.Query().Get("state"), otherCallback.Query().Get("code"))
+	assert.NoError(t, err)
+}
+
+func TestOIDCService_Begin_ProviderUnavailable(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", "test-secret")
+	svc := services.NewOIDCService(services.NewAuthService(&MockUserRepo{}), "http://127.0.0.1:1", "backend", "", oidcRedirectURL)
+	_, err := svc.Begin(context.Background())
+	assert.Error(t, err)
+}
@@ -1,22 +1,35 @@
 package services
 
 import (
+	"context"
+	"log/slog"
+
+	"fullstack-crud-project-01/backend-go/events"
 	"fullstack-crud-project-01/backend-go/models"
 )
 
 // ProductRepository mendefinisikan operasi yang diperlukan untuk produk.
 // Ini adalah "port" dalam arsitektur Hexagonal.
+// Setiap method menerima context agar query dibatalkan saat klien terputus atau deadline habis.
 type ProductRepository interface {
-	Create(product *models.Product) error
-	ReadAll() ([]models.Product, error)
-	ReadByID(id uint) (*models.Product, error)
-	Update(product *models.Product) error
-	Delete(id uint) error
+	Create(ctx context.Context, product *models.Product) error
+	ReadAll(ctx context.Context) ([]models.Product, error)
+	ReadByID(ctx context.Context, id uint) (*models.Product, error)
+	Update(ctx context.Context, product *models.Product) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// EventPublisher mendefinisikan tujuan publikasi event domain (mis. events.Bus).
+type EventPublisher interface {
+	Publish(eventType string, aggregateID uint, payload interface{}) error
 }
 
 // ProductService menyediakan logika bisnis untuk produk.
 type ProductService struct {
-	Repo ProductRepository
+	Repo   ProductRepository
+	Tx     TxManager      // Opsional: jika ada, perubahan produk dan audit-nya ditulis atomik
+	Audit  *AuditService  // Opsional: dipakai untuk audit jika Tx tidak diatur
+	Events EventPublisher // Opsional: nil berarti perubahan tidak dipublikasikan
 }
 
 // NewProductService adalah konstruktor untuk ProductService.
@@ -25,36 +38,138 @@ func NewProductService(repo ProductRepository) *ProductService {
 }
 
 // CreateProduct memvalidasi dan membuat produk baru.
-func (s *ProductService) CreateProduct(product *models.Product) error {
-	// Contoh validasi sederhana
-	if product.Name == "" {
-		return models.ErrProductNameRequired
-	}
-	if product.Price <= 0 {
-		return models.ErrProductPriceInvalid
+func (s *ProductService) CreateProduct(ctx context.Context, product *models.Product) (err error) {
+	ctx, span := startSpan(ctx, "ProductService.CreateProduct")
+	defer func() { endSpan(span, err) }()
+
+	if err := validateProduct(ctx, product); err != nil {
+		return err
 	}
 
-	// Panggil repository untuk menyimpan ke database
-	return s.Repo.Create(product)
+	// Simpan produk dan baris audit-nya dalam satu unit kerja. TxManager bisa mengulang fn
+	// setelah deadlock; ID dari Create yang sudah di-rollback tidak boleh dipakai ulang.
+	requestedID := product.ID
+	err = s.inTransaction(ctx, func(ctx context.Context, repos Repositories) error {
+		product.ID = requestedID
+		if err := repos.Products.Create(ctx, product); err != nil {
+			return err
+		}
+		return WriteAudit(ctx, repos.Audit, NewAuditEntry(
+			models.AuditActionProductCreate, models.AuditResourceProduct, formatResourceID(product.ID), nil, product,
+		))
+	})
+	if err != nil {
+		product.ID = requestedID
+		return err
+	}
+	s.publish(ctx, events.ProductCreated, product.ID, product)
+	return nil
 }
 
 // ReadAllProducts mengambil semua produk.
-func (s *ProductService) ReadAllProducts() ([]models.Product, error) {
-	return s.Repo.ReadAll()
+func (s *ProductService) ReadAllProducts(ctx context.Context) (products []models.Product, err error) {
+	ctx, span := startSpan(ctx, "ProductService.ReadAllProducts")
+	defer func() { endSpan(span, err) }()
+
+	return s.Repo.ReadAll(ctx)
 }
 
 // ReadProductByID mengambil produk berdasarkan ID.
-func (s *ProductService) ReadProductByID(id uint) (*models.Product, error) {
-	return s.Repo.ReadByID(id)
+func (s *ProductService) ReadProductByID(ctx context.Context, id uint) (product *models.Product, err error) {
+	ctx, span := startSpan(ctx, "ProductService.ReadProductByID", productIDAttr(id))
+	defer func() { endSpan(span, err) }()
+
+	return s.Repo.ReadByID(ctx, id)
 }
 
 // UpdateProduct memvalidasi dan memperbarui produk.
-func (s *ProductService) UpdateProduct(product *models.Product) error {
+// Mengembalikan gorm.ErrRecordNotFound jika produk tidak ada.
+func (s *ProductService) UpdateProduct(ctx context.Context, product *models.Product) (err error) {
+	ctx, span := startSpan(ctx, "ProductService.UpdateProduct", productIDAttr(product.ID))
+	defer func() { endSpan(span, err) }()
+
 	// Anda bisa menambahkan validasi di sini jika perlu
-	return s.Repo.Update(product)
+	err = s.inTransaction(ctx, func(ctx context.Context, repos Repositories) error {
+		before, err := repos.Products.ReadByID(ctx, product.ID)
+		if err != nil {
+			return err
+		}
+		product.CreatedAt = before.CreatedAt // Jangan timpa waktu pembuatan dengan nilai kosong
+
+		if err := repos.Products.Update(ctx, product); err != nil {
+			return err
+		}
+		return WriteAudit(ctx, repos.Audit, NewAuditEntry(
+			models.AuditActionProductUpdate, models.AuditResourceProduct, formatResourceID(product.ID), before, product,
+		))
+	})
+	if err != nil {
+		return err
+	}
+	s.publish(ctx, events.ProductUpdated, product.ID, product)
+	return nil
 }
 
 // DeleteProduct menghapus produk berdasarkan ID.
-func (s *ProductService) DeleteProduct(id uint) error {
-	return s.Repo.Delete(id)
+// Mengembalikan gorm.ErrRecordNotFound jika produk tidak ada.
+func (s *ProductService) DeleteProduct(ctx context.Context, id uint) (err error) {
+	ctx, span := startSpan(ctx, "ProductService.DeleteProduct", productIDAttr(id))
+	defer func() { endSpan(span, err) }()
+
+	err = s.inTransaction(ctx, func(ctx context.Context, repos Repositories) error {
+		before, err := repos.Products.ReadByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := repos.Products.Delete(ctx, id); err != nil {
+			return err
+		}
+		return WriteAudit(ctx, repos.Audit, NewAuditEntry(
+			models.AuditActionProductDelete, models.AuditResourceProduct, formatResourceID(id), before, nil,
+		))
+	})
+	if err != nil {
+		return err
+	}
+	s.publish(ctx, events.ProductDeleted, id, map[string]uint{"id": id})
+	return nil
+}
+
+// validateProduct memeriksa field wajib produk baru (span tersendiri agar terlihat di trace)
+func validateProduct(ctx context.Context, product *models.Product) (err error) {
+	_, span := startSpan(ctx, "ProductService.validate")
+	defer func() { endSpan(span, err) }()
+
+	// Contoh validasi sederhana
+	if product.Name == "" {
+		return models.ErrProductNameRequired
+	}
+	if product.Price <= 0 {
+		return models.ErrProductPriceInvalid
+	}
+	return nil
+}
+
+// inTransaction menjalankan fn melalui TxManager jika tersedia. Tanpa TxManager,
+// fn dijalankan langsung dengan repository biasa (tanpa jaminan atomik).
+func (s *ProductService) inTransaction(ctx context.Context, fn TxFunc) error {
+	if s.Tx != nil {
+		return s.Tx.WithinTransaction(ctx, fn)
+	}
+	repos := Repositories{Products: s.Repo}
+	if s.Audit != nil {
+		repos.Audit = s.Audit.Repo
+	}
+	return fn(ctx, repos)
+}
+
+// publish mengirim event setelah penulisan ke repository berhasil.
+// Kegagalan publikasi hanya di-log karena data sudah tersimpan.
+func (s *ProductService) publish(ctx context.Context, eventType string, id uint, payload interface{}) {
+	if s.Events == nil {
+		return
+	}
+	if err := s.Events.Publish(eventType, id, payload); err != nil {
+		slog.ErrorContext(ctx, "Gagal mempublikasikan event", "event", eventType, "product_id", id, "error", err)
+	}
 }
\ No newline at end of file
@@ -1,6 +1,7 @@
 package services_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -11,20 +12,20 @@ import (
 // --- MOCK REPOSITORY ---
 // MockProductRepo adalah implementasi palsu dari services.ProductRepository
 type MockProductRepo struct {
-	CreateFunc func(product *models.Product) error
+	CreateFunc func(ctx context.Context, product *models.Product) error
 }
 
 // Implementasi method Create dari interface ProductRepository
-func (m *MockProductRepo) Create(product *models.Product) error {
-	return m.CreateFunc(product)
+func (m *MockProductRepo) Create(ctx context.Context, product *models.Product) error {
+	return m.CreateFunc(ctx, product)
 }
 
 // Implementasi method ReadAll, ReadByID, Update, Delete di sini nanti...
 // (Untuk saat ini, kita hanya fokus pada Create)
-func (m *MockProductRepo) ReadAll() ([]models.Product, error) { return nil, nil }
-func (m *MockProductRepo) ReadByID(id uint) (*models.Product, error) { return nil, nil }
-func (m *MockProductRepo) Update(product *models.Product) error { return nil }
-func (m *MockProductRepo) Delete(id uint) error { return nil }
+func (m *MockProductRepo) ReadAll(ctx context.Context) ([]models.Product, error) { return nil, nil }
+func (m *MockProductRepo) ReadByID(ctx context.Context, id uint) (*models.Product, error) { return nil, nil }
+func (m *MockProductRepo) Update(ctx context.Context, product *models.Product) error { return nil }
+func (m *MockProductRepo) Delete(ctx context.Context, id uint) error { return nil }
 
 func TestCreateProduct(t *testing.T) {
 	// Definisikan test cases
@@ -44,7 +45,7 @@ func TestCreateProduct(t *testing.T) {
 			},
 			mockRepo: &MockProductRepo{
 				// Mocking: CreateFunc akan mengembalikan nil (sukses DB)
-				CreateFunc: func(product *models.Product) error {
+				CreateFunc: func(ctx context.Context, product *models.Product) error {
 					return nil
 				},
 			},
@@ -60,7 +61,7 @@ func TestCreateProduct(t *testing.T) {
 			},
 			mockRepo: &MockProductRepo{
 				// Meskipun CreateFunc di-mock untuk sukses, logika service harus memblokir ini
-				CreateFunc: func(product *models.Product) error {
+				CreateFunc: func(ctx context.Context, product *models.Product) error {
 					return nil
 				},
 			},
@@ -76,7 +77,7 @@ func TestCreateProduct(t *testing.T) {
 			},
 			mockRepo: &MockProductRepo{
 				// Mocking: CreateFunc mengembalikan error dari DB
-				CreateFunc: func(product *models.Product) error {
+				CreateFunc: func(ctx context.Context, product *models.Product) error {
 					return errors.New("database connection failed")
 				},
 			},
@@ -92,7 +93,7 @@ func TestCreateProduct(t *testing.T) {
 			}
 
 			// Eksekusi fungsi yang diuji
-			err := productService.CreateProduct(tt.inputProduct)
+			err := productService.CreateProduct(context.Background(), tt.inputProduct)
 
 			// Pengecekan Hasil
 			if tt.expectedErr != nil {
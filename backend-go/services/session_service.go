@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/utils"
+)
+
+// sessionTouchInterval membatasi penulisan last_seen_at ke database
+const sessionTouchInterval = time.Minute
+
+// processStart adalah saat proses ini dimulai. Versi ini hanya menerbitkan access token
+// dengan jti, jadi token tanpa jti yang sah pasti terbit sebelum proses dimulai oleh versi
+// lama dan berakhir paling lambat JWT_TTL setelah rilis. Dibulatkan ke detik seperti claim iat.
+var processStart = time.Now().Truncate(time.Second)
+
+// SessionRepository mendefinisikan operasi penyimpanan sesi login ("port").
+// Method Find* mengembalikan gorm.ErrRecordNotFound jika sesi tidak ada.
+type SessionRepository interface {
+	Create(ctx context.Context, session *models.Session) error
+	FindByTokenID(ctx context.Context, tokenID string) (*models.Session, error)
+	// FindForUser hanya menemukan sesi milik userID
+	FindForUser(ctx context.Context, userID, id uint) (*models.Session, error)
+	// ListActive mengambil sesi yang belum dicabut dan belum kedaluwarsa, terbaru lebih dulu
+	ListActive(ctx context.Context, userID uint, now time.Time) ([]models.Session, error)
+	Revoke(ctx context.Context, id uint, at time.Time) error
+	TouchLastSeen(ctx context.Context, id uint, at time.Time) error
+	// DeleteInactive menghapus sesi pengguna yang sudah dicabut atau kedaluwarsa
+	DeleteInactive(ctx context.Context, userID uint, now time.Time) error
+	// RevokeAllForUser mencabut semua sesi pengguna kecuali sesi dengan TokenID exceptTokenID
+	RevokeAllForUser(ctx context.Context, userID uint, exceptTokenID string, at time.Time) error
+}
+
+// SessionService mencatat setiap login sebagai sesi yang bisa dilihat dan dicabut pengguna.
+type SessionService struct {
+	Sessions           SessionRepository
+	Audit              *AuditService    // Opsional: nil berarti pencabutan sesi tidak diaudit
+	LegacyTokensBefore time.Time        // Token tanpa jti hanya diterima jika terbit sebelum waktu ini
+	Now                func() time.Time // Dapat diganti saat pengujian
+}
+
+// NewSessionService adalah konstruktor untuk SessionService.
+func NewSessionService(sessions SessionRepository) *SessionService {
+	return &SessionService{Sessions: sessions, LegacyTokensBefore: processStart, Now: time.Now}
+}
+
+// Start mencatat sesi baru untuk login pengguna. IP dan User-Agent diambil dari AuditActor
+// di ctx; masa berlaku sesi sama dengan access token.
+func (s *SessionService) Start(ctx context.Context, user *models.User) (*models.Session, error) {
+	return s.StartWithTTL(ctx, user, utils.TokenTTL())
+}
+
+// StartWithTTL seperti Start dengan masa berlaku sesi kustom, untuk token yang diterbitkan
+// dengan utils.GenerateSessionTokenWithTTL (mis. dari CLI `token mint`).
+func (s *SessionService) StartWithTTL(ctx context.Context, user *models.User, ttl time.Duration) (*models.Session, error) {
+	tokenID, err := randomSessionID()
+	if err != nil {
+		return nil, err
+	}
+	now := s.Now()
+	actor, _ := AuditActorFrom(ctx)
+
+	// Sesi yang sudah tidak aktif dibersihkan di sini agar tabel tidak tumbuh tanpa batas
+	if err := s.Sessions.DeleteInactive(ctx, user.ID, now); err != nil {
+		slog.WarnContext(ctx, "Gagal menghapus sesi yang tidak aktif", "user_id", user.ID, "error", err)
+	}
+
+	session := &models.Session{
+		UserID:     user.ID,
+		TokenID:    tokenID,
+		Device:     describeDevice(actor.UserAgent),
+		IP:         actor.IP,
+		UserAgent:  truncate(actor.UserAgent, 255),
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(ttl),
+	}
+	if err := s.Sessions.Create(ctx, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// List mengambil sesi aktif milik pengguna. Sesi dengan TokenID currentTokenID (jti token
+// yang dipakai request) ditandai Current.
+func (s *SessionService) List(ctx context.Context, userID uint, currentTokenID string) ([]models.Session, error) {
+	sessions, err := s.Sessions.ListActive(ctx, userID, s.Now())
+	if err != nil {
+		return nil, err
+	}
+	for i := range sessions {
+		sessions[i].Current = currentTokenID != "" && sessions[i].TokenID == currentTokenID
+	}
+	return sessions, nil
+}
+
+// Revoke mencabut sesi aktif milik pengguna; token sesi tersebut langsung ditolak
+// AuthMiddleware. Sesi milik pengguna lain atau yang sudah berakhir dianggap tidak ada.
+func (s *SessionService) Revoke(ctx context.Context, userID, id uint) error {
+	session, err := s.Sessions.FindForUser(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	now := s.Now()
+	if !session.Active(now) {
+		return gorm.ErrRecordNotFound
+	}
+	if err := s.Sessions.Revoke(ctx, session.ID, now); err != nil {
+		return err
+	}
+
+	entry := NewAuditEntry(models.AuditActionSessionRevoke, models.AuditResourceSession, formatResourceID(session.ID), session, nil)
+	if err := s.Audit.Record(ctx, entry); err != nil {
+		logAuditFailure(ctx, entry, err)
+	}
+	return nil
+}
+
+// RevokeAll mencabut semua sesi pengguna kecuali sesi dengan TokenID keepTokenID (kosong
+// berarti semua sesi), mis. setelah password diganti
+func (s *SessionService) RevokeAll(ctx context.Context, userID uint, keepTokenID string) error {
+	return s.Sessions.RevokeAllForUser(ctx, userID, keepTokenID, s.Now())
+}
+
+// Validate memastikan sesi token (claim jti) masih aktif dan memperbarui last_seen_at.
+// Token tanpa jti hanya diterima jika terbit sebelum LegacyTokensBefore (sebelum sesi
+// dicatat); token seperti itu tidak terikat sesi dan berakhir saat kedaluwarsa.
+func (s *SessionService) Validate(ctx context.Context, claims *utils.CustomClaims) error {
+	if claims.ID == "" {
+		if claims.IssuedAt == nil || !claims.IssuedAt.Time.Before(s.LegacyTokensBefore) {
+			return models.ErrSessionRevoked
+		}
+		return nil
+	}
+	session, err := s.Sessions.FindByTokenID(ctx, claims.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.ErrSessionRevoked
+	}
+	if err != nil {
+		return err
+	}
+	now := s.Now()
+	if session.UserID != claims.UserID || !session.Active(now) {
+		return models.ErrSessionRevoked
+	}
+
+	// Seperti last_used_at API key, cukup akurat per menit
+	if now.Sub(session.LastSeenAt) >= sessionTouchInterval {
+		if err := s.Sessions.TouchLastSeen(ctx, session.ID, now); err != nil {
+			slog.WarnContext(ctx, "Gagal memperbarui last_seen_at sesi", "session_id", session.ID, "error", err)
+		}
+	}
+	return nil
+}
+
+// Penanda di User-Agent, diperiksa berurutan (mis. Edge dan Opera juga memuat "Chrome/",
+// iOS memuat "Mac OS X", Android memuat "Linux")
+var (
+	userAgentBrowsers = []struct{ marker, name string }{
+		{"Edg/", "Edge"}, {"OPR/", "Opera"}, {"Firefox/", "Firefox"}, {"FxiOS/", "Firefox"},
+		{"CriOS/", "Chrome"}, {"Chrome/", "Chrome"}, {"Safari/", "Safari"},
+	}
+	userAgentSystems = []struct{ marker, name string }{
+		{"iPhone", "iOS"}, {"iPad", "iPadOS"}, {"Android", "Android"}, {"Windows", "Windows"},
+		{"Mac OS X", "macOS"}, {"CrOS", "ChromeOS"}, {"Linux", "Linux"},
+	}
+)
+
+// describeDevice meringkas User-Agent menjadi nama perangkat yang mudah dibaca,
+// mis. "Firefox di Linux". Klien non-browser memakai nama produknya (mis. "curl").
+func describeDevice(userAgent string) string {
+	if strings.TrimSpace(userAgent) == "" {
+		return "Tidak diketahui"
+	}
+	var browser, system string
+	for _, candidate := range userAgentBrowsers {
+		if strings.Contains(userAgent, candidate.marker) {
+			browser = candidate.name
+			break
+		}
+	}
+	for _, candidate := range userAgentSystems {
+		if strings.Contains(userAgent, candidate.marker) {
+			system = candidate.name
+			break
+		}
+	}
+
+	switch {
+	case browser != "" && system != "":
+		return browser + " di " + system
+	case browser != "":
+		return browser
+	case system != "":
+		return system
+	}
+	product, _, _ := strings.Cut(strings.Fields(userAgent)[0], "/")
+	return truncate(product, 100)
+}
+
+// randomSessionID membuat ID sesi acak (128 bit, base64url) untuk claim jti
+func randomSessionID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// truncate memotong s menjadi maksimal n byte tanpa memecah karakter UTF-8
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
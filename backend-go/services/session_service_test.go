@@ -0,0 +1,214 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/services"
+	"fullstack-crud-project-01/backend-go/utils"
+)
+
+// MockSessionRepo adalah implementasi in-memory dari services.SessionRepository
+type MockSessionRepo struct {
+	Sessions []*models.Session
+	Touches  int
+}
+
+func (m *MockSessionRepo) Create(ctx context.Context, session *models.Session) error {
+	session.ID = uint(len(m.Sessions) + 1)
+	m.Sessions = append(m.Sessions, session)
+	return nil
+}
+
+func (m *MockSessionRepo) FindByTokenID(ctx context.Context, tokenID string) (*models.Session, error) {
+	for _, session := range m.Sessions {
+		if session.TokenID == tokenID {
+			return session, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockSessionRepo) FindForUser(ctx context.Context, userID, id uint) (*models.Session, error) {
+	for _, session := range m.Sessions {
+		if session.ID == id && session.UserID == userID {
+			return session, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockSessionRepo) ListActive(ctx context.Context, userID uint, now time.Time) ([]models.Session, error) {
+	var sessions []models.Session
+	for i := len(m.Sessions) - 1; i >= 0; i-- {
+		if m.Sessions[i].UserID == userID && m.Sessions[i].Active(now) {
+			sessions = append(sessions, *m.Sessions[i])
+		}
+	}
+	return sessions, nil
+}
+
+func (m *MockSessionRepo) Revoke(ctx context.Context, id uint, at time.Time) error {
+	for _, session := range m.Sessions {
+		if session.ID == id {
+			session.RevokedAt = &at
+		}
+	}
+	return nil
+}
+
+func (m *MockSessionRepo) TouchLastSeen(ctx context.Context, id uint, at time.Time) error {
+	for _, session := range m.Sessions {
+		if session.ID == id {
+			session.LastSeenAt = at
+			m.Touches++
+		}
+	}
+	return nil
+}
+
+func (m *MockSessionRepo) DeleteInactive(ctx context.Context, userID uint, now time.Time) error {
+	kept := m.Sessions[:0]
+	for _, session := range m.Sessions {
+		if session.UserID != userID || session.Active(now) {
+			kept = append(kept, session)
+		}
+	}
+	m.Sessions = kept
+	return nil
+}
+
+func (m *MockSessionRepo) RevokeAllForUser(ctx context.Context, userID uint, exceptTokenID string, at time.Time) error {
+	for _, session := range m.Sessions {
+		if session.UserID == userID && session.TokenID != exceptTokenID && session.RevokedAt == nil {
+			session.RevokedAt = &at
+		}
+	}
+	return nil
+}
+
+// sessionClaims membuat claims seperti hasil ValidateToken untuk sesi tertentu
+func sessionClaims(userID uint, tokenID string) *utils.CustomClaims {
+	return &utils.CustomClaims{UserID: userID, RegisteredClaims: jwt.RegisteredClaims{ID: tokenID}}
+}
+
+func TestSessionService_StartAndList(t *testing.T) {
+	repo := &MockSessionRepo{}
+	svc := services.NewSessionService(repo)
+	now := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	svc.Now = func() time.Time { return now }
+	user := &models.User{ID: 3}
+
+	laptop := services.WithAuditActor(context.Background(), services.AuditActor{
+		IP:        "203.0.113.7",
+		UserAgent: "Mozilla/5.0 (X11; Linux x86_64; rv:128.0) Gecko/20100101 Firefox/128.0",
+	})
+	first, err := svc.Start(laptop, user)
+	require.NoError(t, err)
+	assert.Equal(t, "Firefox di Linux", first.Device)
+	assert.Equal(t, "203.0.113.7", first.IP)
+	assert.NotEmpty(t, first.TokenID)
+	assert.Equal(t, now.Add(utils.TokenTTL()), first.ExpiresAt)
+
+	cli := services.WithAuditActor(context.Background(), services.AuditActor{UserAgent: "curl/8.5.0"})
+	second, err := svc.Start(cli, user)
+	require.NoError(t, err)
+	assert.Equal(t, "curl", second.Device)
+	assert.NotEqual(t, first.TokenID, second.TokenID)
+
+	third, err := svc.Start(context.Background(), user)
+	require.NoError(t, err)
+	assert.Equal(t, "Tidak diketahui", third.Device)
+
+	sessions, err := svc.List(context.Background(), user.ID, second.TokenID)
+	require.NoError(t, err)
+	require.Len(t, sessions, 3)
+	for _, session := range sessions {
+		assert.Equal(t, session.ID == second.ID, session.Current)
+	}
+
+	others, err := svc.List(context.Background(), 99, "")
+	require.NoError(t, err)
+	assert.Empty(t, others)
+}
+
+func TestSessionService_StartPrunesInactiveSessions(t *testing.T) {
+	repo := &MockSessionRepo{}
+	svc := services.NewSessionService(repo)
+	now := time.Now()
+	svc.Now = func() time.Time { return now }
+	user := &models.User{ID: 3}
+
+	old, err := svc.Start(context.Background(), user)
+	require.NoError(t, err)
+	require.NoError(t, svc.Revoke(context.Background(), user.ID, old.ID))
+
+	_, err = svc.Start(context.Background(), user)
+	require.NoError(t, err)
+	assert.Len(t, repo.Sessions, 1, "Sesi yang sudah dicabut dihapus")
+}
+
+func TestSessionService_Revoke(t *testing.T) {
+	repo := &MockSessionRepo{}
+	audit := &MockAuditRepo{}
+	svc := services.NewSessionService(repo)
+	svc.Audit = services.NewAuditService(audit)
+	user := &models.User{ID: 3}
+
+	session, err := svc.Start(context.Background(), user)
+	require.NoError(t, err)
+	claims := sessionClaims(user.ID, session.TokenID)
+	require.NoError(t, svc.Validate(context.Background(), claims))
+
+	// Sesi milik pengguna lain dianggap tidak ada
+	assert.ErrorIs(t, svc.Revoke(context.Background(), 99, session.ID), gorm.ErrRecordNotFound)
+
+	require.NoError(t, svc.Revoke(context.Background(), user.ID, session.ID))
+	assert.ErrorIs(t, svc.Validate(context.Background(), claims), models.ErrSessionRevoked)
+	assert.ErrorIs(t, svc.Revoke(context.Background(), user.ID, session.ID), gorm.ErrRecordNotFound, "Sudah dicabut")
+
+	require.Len(t, audit.Created, 1)
+	assert.Equal(t, models.AuditActionSessionRevoke, audit.Created[0].Action)
+	assert.Equal(t, models.AuditResourceSession, audit.Created[0].Resource)
+	assert.NotContains(t, string(audit.Created[0].Before), session.TokenID, "jti tidak ikut diaudit")
+}
+
+func TestSessionService_Validate(t *testing.T) {
+	repo := &MockSessionRepo{}
+	svc := services.NewSessionService(repo)
+	now := time.Now()
+	svc.Now = func() time.Time { return now }
+	session, err := svc.Start(context.Background(), &models.User{ID: 3})
+	require.NoError(t, err)
+
+	// Token tanpa jti hanya diterima jika terbit sebelum proses dimulai (oleh versi lama)
+	legacy := sessionClaims(7, "")
+	assert.ErrorIs(t, svc.Validate(context.Background(), legacy), models.ErrSessionRevoked, "Token tanpa jti dan iat")
+	legacy.IssuedAt = jwt.NewNumericDate(svc.LegacyTokensBefore.Add(-time.Minute))
+	assert.NoError(t, svc.Validate(context.Background(), legacy), "Token lama tidak terikat sesi")
+	legacy.IssuedAt = jwt.NewNumericDate(svc.LegacyTokensBefore)
+	assert.ErrorIs(t, svc.Validate(context.Background(), legacy), models.ErrSessionRevoked, "Token tanpa jti yang terbit setelah proses dimulai")
+	assert.ErrorIs(t, svc.Validate(context.Background(), sessionClaims(3, "tidak-ada")), models.ErrSessionRevoked)
+	assert.ErrorIs(t, svc.Validate(context.Background(), sessionClaims(7, session.TokenID)), models.ErrSessionRevoked, "jti milik pengguna lain")
+
+	// last_seen_at diperbarui paling sering sekali per menit
+	claims := sessionClaims(3, session.TokenID)
+	now = now.Add(30 * time.Second)
+	require.NoError(t, svc.Validate(context.Background(), claims))
+	assert.Zero(t, repo.Touches)
+	now = now.Add(time.Minute)
+	require.NoError(t, svc.Validate(context.Background(), claims))
+	assert.Equal(t, 1, repo.Touches)
+	assert.Equal(t, now, session.LastSeenAt)
+
+	// Sesi berakhir bersama access token
+	now = session.ExpiresAt
+	assert.ErrorIs(t, svc.Validate(context.Background(), claims), models.ErrSessionRevoked)
+}
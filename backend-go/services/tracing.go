@@ -0,0 +1,33 @@
+package services
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName adalah nama instrumentasi span service
+const tracerName = "fullstack-crud-project-01/backend-go/services"
+
+// startSpan memulai span sebagai anak dari span di ctx (mis. span route dari otelgin).
+// Tracer diambil dari provider global saat dipanggil; no-op jika tracing tidak diaktifkan.
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan mencatat err (jika ada) lalu menutup span
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// productIDAttr adalah atribut span untuk ID produk
+func productIDAttr(id uint) attribute.KeyValue {
+	return attribute.Int64("product.id", int64(id))
+}
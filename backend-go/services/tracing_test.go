@@ -0,0 +1,46 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/services"
+)
+
+func TestProductService_Spans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+
+	svc := services.NewProductService(&MockProductRepo{
+		CreateFunc: func(ctx context.Context, product *models.Product) error { return nil },
+	})
+
+	// Validasi gagal: span validate dan span method ditandai error
+	err := svc.CreateProduct(context.Background(), &models.Product{Name: ""})
+	require.ErrorIs(t, err, models.ErrProductNameRequired)
+
+	spans := exporter.GetSpans().Snapshots()
+	require.Len(t, spans, 2)
+	validate, create := spans[0], spans[1]
+	assert.Equal(t, "ProductService.validate", validate.Name())
+	assert.Equal(t, "ProductService.CreateProduct", create.Name())
+	assert.Equal(t, create.SpanContext().SpanID(), validate.Parent().SpanID())
+	assert.Equal(t, codes.Error, create.Status().Code)
+
+	// Berhasil: status span tidak error
+	exporter.Reset()
+	require.NoError(t, svc.CreateProduct(context.Background(), &models.Product{Name: "Kopi", Price: 10}))
+	spans = exporter.GetSpans().Snapshots()
+	require.Len(t, spans, 2)
+	assert.NotEqual(t, codes.Error, spans[1].Status().Code)
+}
@@ -0,0 +1,24 @@
+package services
+
+import "context"
+
+// Repositories adalah kumpulan repository yang terikat pada transaksi yang sama.
+type Repositories struct {
+	Products      ProductRepository
+	Audit         AuditRepository
+	Users         UserRepository
+	RecoveryCodes RecoveryCodeRepository
+}
+
+// TxFunc adalah unit kerja yang dijalankan di dalam transaksi.
+// ctx yang diterima membawa transaksi aktif sehingga pemanggilan WithinTransaction
+// bersarang di dalamnya menjadi savepoint, bukan transaksi baru.
+type TxFunc func(ctx context.Context, repos Repositories) error
+
+// TxManager menjalankan unit kerja secara atomik ("port" unit-of-work).
+// Jika fn mengembalikan error, semua perubahan di dalamnya di-rollback.
+// Implementasi boleh menjalankan ulang fn saat terjadi deadlock, jadi fn tidak
+// boleh memiliki efek samping di luar database.
+type TxManager interface {
+	WithinTransaction(ctx context.Context, fn TxFunc) error
+}
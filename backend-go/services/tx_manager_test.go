@@ -0,0 +1,148 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/services"
+)
+
+// MockTxManager menjalankan fn dengan repository palsu dan mencatat apakah transaksi di-commit
+type MockTxManager struct {
+	Repos      services.Repositories
+	Committed  int
+	RolledBack int
+}
+
+func (m *MockTxManager) WithinTransaction(ctx context.Context, fn services.TxFunc) error {
+	if err := fn(ctx, m.Repos); err != nil {
+		m.RolledBack++
+		return err
+	}
+	m.Committed++
+	return nil
+}
+
+// MockPublisher mencatat event yang dipublikasikan
+type MockPublisher struct {
+	Published []string
+}
+
+func (p *MockPublisher) Publish(eventType string, aggregateID uint, payload interface{}) error {
+	p.Published = append(p.Published, eventType)
+	return nil
+}
+
+func TestProductService_CreateWithinTransaction(t *testing.T) {
+	tests := []struct {
+		name               string
+		auditFails         bool
+		expectErr          bool
+		expectCommitted    int
+		expectRolledBack   int
+		expectPublished    int
+		expectAuditEntries int
+	}{
+		{name: "Success_ProductAndAuditCommitted", expectCommitted: 1, expectPublished: 1, expectAuditEntries: 1},
+		{name: "Failure_AuditErrorRollsBack", auditFails: true, expectErr: true, expectRolledBack: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auditRepo := &MockAuditRepo{Fail: tt.auditFails}
+			tx := &MockTxManager{Repos: services.Repositories{
+				Products: &MockProductRepo{CreateFunc: func(ctx context.Context, product *models.Product) error {
+					product.ID = 1
+					return nil
+				}},
+				Audit: auditRepo,
+			}}
+			publisher := &MockPublisher{}
+			productService := services.ProductService{Tx: tx, Events: publisher}
+
+			err := productService.CreateProduct(context.Background(), &models.Product{Name: "Laptop", Price: 100})
+
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("Expected error: %v, got: %v", tt.expectErr, err)
+			}
+			if tx.Committed != tt.expectCommitted || tx.RolledBack != tt.expectRolledBack {
+				t.Errorf("Expected committed=%d rolledBack=%d, got committed=%d rolledBack=%d",
+					tt.expectCommitted, tt.expectRolledBack, tx.Committed, tx.RolledBack)
+			}
+			if len(publisher.Published) != tt.expectPublished {
+				t.Errorf("Expected %d published events, got %d", tt.expectPublished, len(publisher.Published))
+			}
+			if len(auditRepo.Created) != tt.expectAuditEntries {
+				t.Errorf("Expected %d audit entries, got %d", tt.expectAuditEntries, len(auditRepo.Created))
+			}
+		})
+	}
+}
+
+func TestProductService_UpdateNotFound(t *testing.T) {
+	notFound := errors.New("record not found")
+	tx := &MockTxManager{Repos: services.Repositories{
+		Products: &notFoundRepo{MockProductRepo{}, notFound},
+		Audit:    &MockAuditRepo{},
+	}}
+	productService := services.ProductService{Tx: tx}
+
+	err := productService.UpdateProduct(context.Background(), &models.Product{ID: 99, Name: "X", Price: 1})
+	if !errors.Is(err, notFound) {
+		t.Errorf("Expected not found error, got: %v", err)
+	}
+}
+
+// notFoundRepo mengembalikan error pada ReadByID
+type notFoundRepo struct {
+	MockProductRepo
+	err error
+}
+
+func (r *notFoundRepo) ReadByID(ctx context.Context, id uint) (*models.Product, error) {
+	return nil, r.err
+}
+
+// retryingTxManager menjalankan fn dua kali seperti GormTxManager setelah deadlock:
+// percobaan pertama dianggap di-rollback, tetapi perubahan pada model tetap tertinggal
+type retryingTxManager struct {
+	Repos services.Repositories
+}
+
+func (m *retryingTxManager) WithinTransaction(ctx context.Context, fn services.TxFunc) error {
+	if err := fn(ctx, m.Repos); err != nil {
+		return err
+	}
+	return fn(ctx, m.Repos)
+}
+
+func TestProductService_CreateRetryDoesNotReuseRolledBackID(t *testing.T) {
+	// Sequence database tidak ikut di-rollback, jadi percobaan ulang mendapat ID baru
+	var seenIDs []uint
+	nextID := uint(0)
+	auditRepo := &MockAuditRepo{}
+	tx := &retryingTxManager{Repos: services.Repositories{
+		Products: &MockProductRepo{CreateFunc: func(ctx context.Context, product *models.Product) error {
+			seenIDs = append(seenIDs, product.ID)
+			nextID++
+			product.ID = nextID
+			return nil
+		}},
+		Audit: auditRepo,
+	}}
+	productService := services.ProductService{Tx: tx}
+
+	product := &models.Product{Name: "Laptop", Price: 100}
+	if err := productService.CreateProduct(context.Background(), product); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(seenIDs) != 2 || seenIDs[0] != 0 || seenIDs[1] != 0 {
+		t.Errorf("Expected every attempt to start without an ID, got: %v", seenIDs)
+	}
+	if product.ID != 2 || auditRepo.Created[1].ResourceID != "2" {
+		t.Errorf("Expected product and audit to use the ID from the last attempt, got product=%d audit=%s",
+			product.ID, auditRepo.Created[1].ResourceID)
+	}
+}
@@ -0,0 +1,401 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"fullstack-crud-project-01/backend-go/events"
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/retry"
+)
+
+// Header yang dikirim bersama setiap pengiriman webhook
+const (
+	WebhookHeaderEvent     = "X-Webhook-Event"
+	WebhookHeaderDelivery  = "X-Webhook-Delivery"
+	WebhookHeaderTimestamp = "X-Webhook-Timestamp"
+	WebhookHeaderSignature = "X-Webhook-Signature"
+)
+
+// Pengaturan default antrean pengiriman
+const (
+	defaultWebhookMaxAttempts = 8
+	defaultWebhookBaseBackoff = 30 * time.Second
+	defaultWebhookMaxBackoff  = 6 * time.Hour
+	webhookBatchSize          = 50
+	webhookResponseBodyLimit  = 1024
+)
+
+// WebhookEventTypes adalah tipe event yang boleh dilanggan
+var WebhookEventTypes = []string{events.ProductCreated, events.ProductUpdated, events.ProductDeleted}
+
+// WebhookRepository mendefinisikan operasi penyimpanan subscription dan antrean pengiriman ("port").
+type WebhookRepository interface {
+	CreateSubscription(sub *models.WebhookSubscription) error
+	ListSubscriptions() ([]models.WebhookSubscription, error)
+	FindSubscription(id uint) (*models.WebhookSubscription, error)
+	DeleteSubscription(id uint) error
+	ActiveSubscriptions() ([]models.WebhookSubscription, error)
+
+	CreateDelivery(delivery *models.WebhookDelivery) error
+	FindDelivery(id uint) (*models.WebhookDelivery, error)
+	ListDeliveries(subscriptionID uint, limit int) ([]models.WebhookDelivery, error)
+	DueDeliveries(now time.Time, limit int) ([]models.WebhookDelivery, error)
+	UpdateDelivery(delivery *models.WebhookDelivery) error
+	CreateDeliveryLog(entry *models.WebhookDeliveryLog) error
+}
+
+// WebhookService mengelola subscription webhook serta pengiriman event yang ditandatangani HMAC-SHA256.
+type WebhookService struct {
+	Repo        WebhookRepository
+	Client      *http.Client
+	Leader      Leader // Opsional: nil berarti antrean selalu diproses oleh instance ini
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	Now         func() time.Time // Dapat diganti saat pengujian
+
+	// AllowPrivateTargets mengizinkan pengiriman ke loopback, link-local dan jaringan privat.
+	// Default false agar URL webhook tidak bisa dipakai untuk menjangkau layanan internal (SSRF).
+	AllowPrivateTargets bool
+}
+
+// NewWebhookService adalah konstruktor untuk WebhookService dengan pengaturan retry default.
+// Client bawaan memeriksa alamat IP tujuan setiap koneksi (termasuk setelah redirect dan
+// resolusi DNS) dan menolak alamat internal kecuali AllowPrivateTargets diaktifkan.
+func NewWebhookService(repo WebhookRepository) *WebhookService {
+	s := &WebhookService{
+		Repo:        repo,
+		MaxAttempts: defaultWebhookMaxAttempts,
+		BaseBackoff: defaultWebhookBaseBackoff,
+		MaxBackoff:  defaultWebhookMaxBackoff,
+		Now:         time.Now,
+	}
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			return s.checkTarget(address)
+		},
+	}
+	s.Client = &http.Client{
+		Timeout: 10 * time.Second,
+		// Tanpa proxy: pemeriksaan alamat hanya berlaku untuk koneksi langsung ke tujuan
+		Transport: &http.Transport{Proxy: nil, DialContext: dialer.DialContext},
+	}
+	return s
+}
+
+// CreateSubscription memvalidasi dan menyimpan subscription baru.
+// Jika secret kosong, secret acak dibuat dan dikembalikan lewat sub.Secret.
+func (s *WebhookService) CreateSubscription(sub *models.WebhookSubscription) error {
+	parsed, err := url.Parse(sub.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return models.ErrWebhookURLInvalid
+	}
+	// Host berupa IP internal atau localhost langsung ditolak; nama domain lain diperiksa
+	// kembali setiap kali dikirim karena hasil DNS-nya bisa berubah
+	if !s.AllowPrivateTargets {
+		host := parsed.Hostname()
+		if ip := net.ParseIP(host); (ip != nil && isInternalIP(ip)) || strings.EqualFold(host, "localhost") {
+			return fmt.Errorf("%w: %v", models.ErrWebhookURLInvalid, models.ErrWebhookTargetBlocked)
+		}
+	}
+	if len(sub.Events) == 0 {
+		return models.ErrWebhookEventTypesRequired
+	}
+	for _, eventType := range sub.Events {
+		if !isWebhookEventType(eventType) {
+			return fmt.Errorf("%w: %s", models.ErrWebhookEventTypeInvalid, eventType)
+		}
+	}
+	if sub.Secret == "" {
+		secret, err := generateWebhookSecret()
+		if err != nil {
+			return err
+		}
+		sub.Secret = secret
+	}
+	sub.Active = true
+	return s.Repo.CreateSubscription(sub)
+}
+
+// ListSubscriptions mengambil semua subscription.
+func (s *WebhookService) ListSubscriptions() ([]models.WebhookSubscription, error) {
+	return s.Repo.ListSubscriptions()
+}
+
+// DeleteSubscription menghapus subscription berdasarkan ID.
+func (s *WebhookService) DeleteSubscription(id uint) error {
+	if _, err := s.Repo.FindSubscription(id); err != nil {
+		return err
+	}
+	return s.Repo.DeleteSubscription(id)
+}
+
+// ListDeliveries mengambil log pengiriman terbaru untuk sebuah subscription.
+func (s *WebhookService) ListDeliveries(subscriptionID uint) ([]models.WebhookDelivery, error) {
+	if _, err := s.Repo.FindSubscription(subscriptionID); err != nil {
+		return nil, err
+	}
+	return s.Repo.ListDeliveries(subscriptionID, 100)
+}
+
+// Enqueue memasukkan event ke antrean pengiriman untuk setiap subscription aktif yang berlangganan.
+func (s *WebhookService) Enqueue(event events.Event) error {
+	subs, err := s.Repo.ActiveSubscriptions()
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"type":        event.Type,
+		"occurred_at": event.OccurredAt,
+		"data":        event.Data,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, sub := range subs {
+		if !sub.Wants(event.Type) {
+			continue
+		}
+		delivery := &models.WebhookDelivery{
+			SubscriptionID: sub.ID,
+			EventType:      event.Type,
+			Payload:        string(payload),
+			Status:         models.WebhookDeliveryPending,
+			NextAttemptAt:  s.Now(),
+		}
+		if err := s.Repo.CreateDelivery(delivery); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Redeliver menjadwalkan ulang sebuah pengiriman (berhasil maupun gagal) untuk segera dikirim.
+// Attempts tidak di-reset agar nomor percobaan di log pengiriman tetap berurutan; pengiriman
+// yang sudah mencapai MaxAttempts hanya dicoba sekali lagi tanpa retry otomatis.
+func (s *WebhookService) Redeliver(deliveryID uint) (*models.WebhookDelivery, error) {
+	delivery, err := s.Repo.FindDelivery(deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.Status == models.WebhookDeliveryPending {
+		return nil, models.ErrWebhookDeliveryPending
+	}
+
+	delivery.Status = models.WebhookDeliveryPending
+	delivery.NextAttemptAt = s.Now()
+	if err := s.Repo.UpdateDelivery(delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// ProcessDue mengirim semua pengiriman yang sudah jatuh tempo. Mengembalikan jumlah yang diproses.
+func (s *WebhookService) ProcessDue(ctx context.Context) (int, error) {
+	due, err := s.Repo.DueDeliveries(s.Now(), webhookBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	for i := range due {
+		if ctx.Err() != nil {
+			return i, ctx.Err()
+		}
+		if err := s.deliver(ctx, &due[i]); err != nil {
+			slog.ErrorContext(ctx, "Gagal memperbarui status webhook delivery", "delivery_id", due[i].ID, "error", err)
+		}
+	}
+	return len(due), nil
+}
+
+// Run memproses antrean setiap pollInterval hingga ctx dibatalkan.
+// Event masuk ke antrean lewat outbox (WebhookPublisher). Jika Leader diisi, antrean hanya
+// diproses selama instance ini memegang lock leader agar replika lain tidak mengirim
+// pengiriman yang sama dua kali.
+func (s *WebhookService) Run(ctx context.Context, pollInterval time.Duration) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	if s.Leader != nil {
+		defer func() {
+			// Lock tetap dilepas walaupun ctx sudah dibatalkan
+			if err := s.Leader.Release(context.Background()); err != nil {
+				slog.Error("Gagal melepas lock leader worker webhook", "error", err)
+			}
+		}()
+	}
+
+	leading := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			active, err := s.acquire(ctx)
+			if active != leading && err == nil {
+				leading = active
+				slog.InfoContext(ctx, "Status leader worker webhook berubah", "leader", leading)
+			}
+			if active {
+				_, err = s.ProcessDue(ctx)
+			}
+			if err != nil && ctx.Err() == nil {
+				slog.ErrorContext(ctx, "Gagal memproses antrean webhook", "error", err)
+			}
+		}
+	}
+}
+
+// acquire memeriksa apakah instance ini boleh memproses antrean
+func (s *WebhookService) acquire(ctx context.Context) (bool, error) {
+	if s.Leader == nil {
+		return true, nil
+	}
+	return s.Leader.Acquire(ctx)
+}
+
+// deliver melakukan satu percobaan pengiriman lalu memperbarui status dan jadwal retry.
+func (s *WebhookService) deliver(ctx context.Context, delivery *models.WebhookDelivery) error {
+	sub, err := s.Repo.FindSubscription(delivery.SubscriptionID)
+	if err != nil {
+		// Subscription sudah dihapus: hentikan pengiriman
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.LastError = "subscription tidak ditemukan"
+		return s.Repo.UpdateDelivery(delivery)
+	}
+
+	start := s.Now()
+	status, sendErr := s.send(ctx, sub, delivery)
+	delivery.Attempts++
+	delivery.ResponseStatus = status
+
+	entry := &models.WebhookDeliveryLog{
+		DeliveryID:     delivery.ID,
+		Attempt:        delivery.Attempts,
+		ResponseStatus: status,
+		DurationMs:     s.Now().Sub(start).Milliseconds(),
+	}
+
+	if sendErr == nil {
+		now := s.Now()
+		delivery.Status = models.WebhookDeliverySucceeded
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+	} else {
+		entry.Error = sendErr.Error()
+		delivery.LastError = sendErr.Error()
+		// Tujuan yang diblokir tidak akan berhasil walaupun dicoba ulang
+		if delivery.Attempts >= s.MaxAttempts || errors.Is(sendErr, models.ErrWebhookTargetBlocked) {
+			delivery.Status = models.WebhookDeliveryFailed
+		} else {
+			delivery.NextAttemptAt = s.Now().Add(retry.Backoff(delivery.Attempts, s.BaseBackoff, s.MaxBackoff))
+		}
+	}
+
+	if err := s.Repo.CreateDeliveryLog(entry); err != nil {
+		return err
+	}
+	return s.Repo.UpdateDelivery(delivery)
+}
+
+// send mengirim payload ke URL subscription. Status 2xx dianggap berhasil.
+func (s *WebhookService) send(ctx context.Context, sub *models.WebhookSubscription, delivery *models.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	timestamp := s.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "fullstack-crud-webhooks/1.0")
+	req.Header.Set(WebhookHeaderEvent, delivery.EventType)
+	req.Header.Set(WebhookHeaderDelivery, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(WebhookHeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookHeaderSignature, SignWebhookPayload(sub.Secret, timestamp, body))
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseBodyLimit))
+		return resp.StatusCode, fmt.Errorf("receiver membalas status %d: %s", resp.StatusCode, snippet)
+	}
+	return resp.StatusCode, nil
+}
+
+// checkTarget menolak koneksi ke alamat internal. address adalah "ip:port" yang sudah
+// di-resolve, sehingga nama domain yang mengarah ke IP internal juga tertolak.
+func (s *WebhookService) checkTarget(address string) error {
+	if s.AllowPrivateTargets {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || isInternalIP(ip) {
+		return fmt.Errorf("%w: %s", models.ErrWebhookTargetBlocked, host)
+	}
+	return nil
+}
+
+// sharedAddressSpace adalah 100.64.0.0/10 (CGNAT), yang tidak tercakup net.IP.IsPrivate
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// isInternalIP melaporkan apakah ip adalah loopback, link-local, privat atau tidak dapat dirutekan
+func isInternalIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() || sharedAddressSpace.Contains(ip)
+}
+
+// SignWebhookPayload menghasilkan nilai header X-Webhook-Signature:
+// "sha256=" + hex(HMAC-SHA256(secret, "<timestamp>.<body>")).
+// Receiver memverifikasi dengan menghitung ulang nilai yang sama.
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func isWebhookEventType(eventType string) bool {
+	for _, known := range WebhookEventTypes {
+		if eventType == known {
+			return true
+		}
+	}
+	return false
+}
+
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
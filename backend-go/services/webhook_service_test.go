@@ -0,0 +1,251 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"fullstack-crud-project-01/backend-go/events"
+	"fullstack-crud-project-01/backend-go/models"
+	"fullstack-crud-project-01/backend-go/services"
+)
+
+// MockWebhookRepo adalah implementasi in-memory dari services.WebhookRepository
+type MockWebhookRepo struct {
+	mu         sync.Mutex
+	subs       []models.WebhookSubscription
+	deliveries []models.WebhookDelivery
+	logs       []models.WebhookDeliveryLog
+}
+
+func (m *MockWebhookRepo) CreateSubscription(sub *models.WebhookSubscription) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sub.ID = uint(len(m.subs) + 1)
+	m.subs = append(m.subs, *sub)
+	return nil
+}
+
+func (m *MockWebhookRepo) ListSubscriptions() ([]models.WebhookSubscription, error) {
+	return m.subs, nil
+}
+
+func (m *MockWebhookRepo) FindSubscription(id uint) (*models.WebhookSubscription, error) {
+	for _, sub := range m.subs {
+		if sub.ID == id {
+			return &sub, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockWebhookRepo) DeleteSubscription(id uint) error { return nil }
+
+func (m *MockWebhookRepo) ActiveSubscriptions() ([]models.WebhookSubscription, error) {
+	return m.subs, nil
+}
+
+func (m *MockWebhookRepo) CreateDelivery(delivery *models.WebhookDelivery) error {
+	delivery.ID = uint(len(m.deliveries) + 1)
+	m.deliveries = append(m.deliveries, *delivery)
+	return nil
+}
+
+func (m *MockWebhookRepo) FindDelivery(id uint) (*models.WebhookDelivery, error) {
+	for _, d := range m.deliveries {
+		if d.ID == id {
+			return &d, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockWebhookRepo) ListDeliveries(subscriptionID uint, limit int) ([]models.WebhookDelivery, error) {
+	return m.deliveries, nil
+}
+
+func (m *MockWebhookRepo) DueDeliveries(now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	var due []models.WebhookDelivery
+	for _, d := range m.deliveries {
+		if d.Status == models.WebhookDeliveryPending && !d.NextAttemptAt.After(now) {
+			due = append(due, d)
+		}
+	}
+	return due, nil
+}
+
+func (m *MockWebhookRepo) UpdateDelivery(delivery *models.WebhookDelivery) error {
+	m.deliveries[delivery.ID-1] = *delivery
+	return nil
+}
+
+func (m *MockWebhookRepo) CreateDeliveryLog(entry *models.WebhookDeliveryLog) error {
+	m.logs = append(m.logs, *entry)
+	return nil
+}
+
+func TestWebhookService_CreateSubscriptionValidation(t *testing.T) {
+	webhookService := services.NewWebhookService(&MockWebhookRepo{})
+
+	err := webhookService.CreateSubscription(&models.WebhookSubscription{URL: "ftp://erp", Events: []string{events.ProductCreated}})
+	assert.ErrorIs(t, err, models.ErrWebhookURLInvalid)
+
+	err = webhookService.CreateSubscription(&models.WebhookSubscription{URL: "https://erp.example.com/hook", Events: []string{"order.paid"}})
+	assert.ErrorIs(t, err, models.ErrWebhookEventTypeInvalid)
+
+	sub := &models.WebhookSubscription{URL: "https://erp.example.com/hook", Events: []string{events.ProductCreated}}
+	assert.NoError(t, webhookService.CreateSubscription(sub))
+	assert.NotEmpty(t, sub.Secret, "Secret harus dibuat otomatis")
+}
+
+func TestWebhookService_BlocksInternalTargets(t *testing.T) {
+	var requests atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	}))
+	defer receiver.Close()
+
+	repo := &MockWebhookRepo{}
+	webhookService := services.NewWebhookService(repo)
+
+	for _, target := range []string{"http://127.0.0.1:8080/hook", "http://localhost/hook", "http://169.254.169.254/latest/meta-data", "https://10.0.0.5/hook", "http://[::1]/hook"} {
+		err := webhookService.CreateSubscription(&models.WebhookSubscription{URL: target, Events: []string{events.ProductCreated}})
+		assert.ErrorIs(t, err, models.ErrWebhookURLInvalid, target)
+	}
+
+	// Alamat yang lolos saat didaftarkan (mis. DNS yang kemudian mengarah ke IP internal)
+	// tetap diperiksa saat koneksi dibuat
+	repo.subs = append(repo.subs, models.WebhookSubscription{ID: 1, URL: receiver.URL, Secret: "s", Events: []string{events.ProductCreated}, Active: true})
+	require.NoError(t, webhookService.Enqueue(events.Event{Type: events.ProductCreated, Data: json.RawMessage(`{}`)}))
+
+	processed, err := webhookService.ProcessDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, processed)
+	assert.Zero(t, requests.Load(), "Receiver internal tidak boleh dihubungi")
+	assert.Equal(t, models.WebhookDeliveryFailed, repo.deliveries[0].Status, "Tujuan yang diblokir tidak dicoba ulang")
+	assert.Contains(t, repo.deliveries[0].LastError, models.ErrWebhookTargetBlocked.Error())
+}
+
+func TestWebhookService_SignedDeliveryWithRetry(t *testing.T) {
+	const secret = "rahasia-erp"
+
+	// Receiver gagal pada percobaan pertama, berhasil pada percobaan kedua
+	var (
+		mu       sync.Mutex
+		requests int
+	)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(services.WebhookHeaderTimestamp), 10, 64)
+		assert.Equal(t, services.SignWebhookPayload(secret, timestamp, body), r.Header.Get(services.WebhookHeaderSignature))
+		assert.Equal(t, events.ProductUpdated, r.Header.Get(services.WebhookHeaderEvent))
+
+		var payload map[string]interface{}
+		assert.NoError(t, json.Unmarshal(body, &payload))
+		assert.Equal(t, events.ProductUpdated, payload["type"])
+
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	now := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	repo := &MockWebhookRepo{}
+	webhookService := services.NewWebhookService(repo)
+	webhookService.AllowPrivateTargets = true // Receiver httptest berjalan di 127.0.0.1
+	webhookService.Now = func() time.Time { return now }
+
+	require.NoError(t, webhookService.CreateSubscription(&models.WebhookSubscription{
+		URL:    receiver.URL,
+		Secret: secret,
+		Events: []string{events.ProductUpdated},
+	}))
+
+	// Event yang tidak dilanggan tidak masuk antrean
+	require.NoError(t, webhookService.Enqueue(events.Event{Type: events.ProductDeleted, Data: json.RawMessage(`{}`)}))
+	require.NoError(t, webhookService.Enqueue(events.Event{Type: events.ProductUpdated, AggregateID: 1, Data: json.RawMessage(`{"id":1}`)}))
+	require.Len(t, repo.deliveries, 1)
+
+	// 1. Percobaan pertama gagal -> dijadwalkan ulang dengan backoff
+	processed, err := webhookService.ProcessDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, processed)
+	assert.Equal(t, models.WebhookDeliveryPending, repo.deliveries[0].Status)
+	assert.Equal(t, now.Add(30*time.Second), repo.deliveries[0].NextAttemptAt)
+	assert.Equal(t, http.StatusServiceUnavailable, repo.deliveries[0].ResponseStatus)
+
+	// 2. Belum jatuh tempo -> tidak diproses
+	processed, _ = webhookService.ProcessDue(context.Background())
+	assert.Equal(t, 0, processed)
+
+	// 3. Setelah backoff berlalu -> berhasil
+	now = now.Add(31 * time.Second)
+	processed, _ = webhookService.ProcessDue(context.Background())
+	assert.Equal(t, 1, processed)
+	assert.Equal(t, models.WebhookDeliverySucceeded, repo.deliveries[0].Status)
+	assert.Equal(t, 2, repo.deliveries[0].Attempts)
+	assert.Len(t, repo.logs, 2)
+
+	// 4. Redeliver manual menjadwalkan ulang pengiriman yang sudah selesai
+	delivery, err := webhookService.Redeliver(1)
+	require.NoError(t, err)
+	assert.Equal(t, models.WebhookDeliveryPending, delivery.Status)
+	processed, _ = webhookService.ProcessDue(context.Background())
+	assert.Equal(t, 1, processed)
+	assert.Equal(t, 3, requests)
+
+	// Nomor percobaan terus bertambah setelah redeliver
+	assert.Equal(t, 3, repo.deliveries[0].Attempts)
+	require.Len(t, repo.logs, 3)
+	assert.Equal(t, 3, repo.logs[2].Attempt)
+}
+
+func TestWebhookService_GivesUpAfterMaxAttempts(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+
+	now := time.Now()
+	repo := &MockWebhookRepo{}
+	webhookService := services.NewWebhookService(repo)
+	webhookService.AllowPrivateTargets = true
+	webhookService.MaxAttempts = 3
+	webhookService.Now = func() time.Time { return now }
+
+	require.NoError(t, webhookService.CreateSubscription(&models.WebhookSubscription{URL: receiver.URL, Events: []string{events.ProductCreated}}))
+	require.NoError(t, webhookService.Enqueue(events.Event{Type: events.ProductCreated, Data: json.RawMessage(`{}`)}))
+
+	for i := 0; i < 3; i++ {
+		webhookService.ProcessDue(context.Background())
+		now = now.Add(time.Hour)
+	}
+	assert.Equal(t, models.WebhookDeliveryFailed, repo.deliveries[0].Status)
+	assert.Equal(t, 3, repo.deliveries[0].Attempts)
+
+	// Redeliver manual mencoba sekali lagi tanpa mengulang jatah retry otomatis
+	_, err := webhookService.Redeliver(repo.deliveries[0].ID)
+	require.NoError(t, err)
+	processed, _ := webhookService.ProcessDue(context.Background())
+	assert.Equal(t, 1, processed)
+	assert.Equal(t, models.WebhookDeliveryFailed, repo.deliveries[0].Status)
+	assert.Equal(t, 4, repo.deliveries[0].Attempts)
+}
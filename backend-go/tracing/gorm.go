@@ -0,0 +1,94 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// parentKey menyimpan context sebelum span query dibuat, untuk dikembalikan setelah query
+const parentKey = "tracing:parent"
+
+// GormPlugin membuat satu span per query GORM sebagai anak dari span di context query
+// (db.WithContext). Query tanpa span induk (mis. polling relay outbox setiap detik)
+// tidak di-trace agar tidak menghasilkan trace tunggal yang hanya berisi satu query.
+// SQL dicatat dengan placeholder, tanpa nilai parameter.
+// Pasang dengan db.Use(tracing.GormPlugin{}).
+type GormPlugin struct{}
+
+// Name memenuhi gorm.Plugin
+func (GormPlugin) Name() string {
+	return "tracing"
+}
+
+// Initialize memasang callback sebelum dan sesudah setiap jenis operasi GORM
+func (GormPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	errs := []error{
+		cb.Create().Before("gorm:create").Register("tracing:before_create", startSpan("create")),
+		cb.Create().After("gorm:create").Register("tracing:after_create", endSpan),
+		cb.Query().Before("gorm:query").Register("tracing:before_query", startSpan("query")),
+		cb.Query().After("gorm:query").Register("tracing:after_query", endSpan),
+		cb.Update().Before("gorm:update").Register("tracing:before_update", startSpan("update")),
+		cb.Update().After("gorm:update").Register("tracing:after_update", endSpan),
+		cb.Delete().Before("gorm:delete").Register("tracing:before_delete", startSpan("delete")),
+		cb.Delete().After("gorm:delete").Register("tracing:after_delete", endSpan),
+		cb.Row().Before("gorm:row").Register("tracing:before_row", startSpan("row")),
+		cb.Row().After("gorm:row").Register("tracing:after_row", endSpan),
+		cb.Raw().Before("gorm:raw").Register("tracing:before_raw", startSpan("raw")),
+		cb.Raw().After("gorm:raw").Register("tracing:after_raw", endSpan),
+	}
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// startSpan memulai span "gorm.<operasi>" dan memasukkannya ke context statement
+func startSpan(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		parent := db.Statement.Context
+		if parent == nil || !trace.SpanContextFromContext(parent).IsValid() {
+			return
+		}
+		ctx, _ := otel.Tracer(InstrumentationName).Start(parent, "gorm."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(semconv.DBOperationName(operation)),
+		)
+		db.InstanceSet(parentKey, parent)
+		db.Statement.Context = ctx
+	}
+}
+
+// endSpan melengkapi atribut query, mencatat error (kecuali record not found) lalu menutup span
+func endSpan(db *gorm.DB) {
+	parent, ok := db.InstanceGet(parentKey)
+	if !ok {
+		return
+	}
+	span := trace.SpanFromContext(db.Statement.Context)
+	db.Statement.Context = parent.(context.Context)
+
+	if span.IsRecording() {
+		span.SetAttributes(
+			semconv.DBSystemNameKey.String(db.Dialector.Name()),
+			semconv.DBQueryText(db.Statement.SQL.String()),
+			semconv.DBResponseReturnedRows(int(db.RowsAffected)),
+		)
+		if db.Statement.Table != "" {
+			span.SetAttributes(semconv.DBCollectionName(db.Statement.Table))
+		}
+	}
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+	span.End()
+}
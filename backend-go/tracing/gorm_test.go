@@ -0,0 +1,63 @@
+package tracing_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/gorm"
+
+	"fullstack-crud-project-01/backend-go/tracing"
+)
+
+type widget struct {
+	ID   uint
+	Name string
+}
+
+// attr mengambil nilai atribut span sebagai string
+func attr(span sdktrace.ReadOnlySpan, key string) string {
+	for _, kv := range span.Attributes() {
+		if string(kv.Key) == key {
+			return kv.Value.Emit()
+		}
+	}
+	return ""
+}
+
+func TestGormPlugin_SpansAreChildrenOfContextSpan(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	installProvider(t, provider)
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(tracing.GormPlugin{}))
+	require.NoError(t, db.AutoMigrate(&widget{}))
+	exporter.Reset()
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "request")
+	require.NoError(t, db.WithContext(ctx).Create(&widget{Name: "rahasia"}).Error)
+	assert.Error(t, db.WithContext(ctx).Table("tidak_ada").Create(map[string]interface{}{"name": "x"}).Error)
+	parent.End()
+	require.NoError(t, db.Create(&widget{Name: "tanpa induk"}).Error, "Query tanpa span induk tidak di-trace")
+
+	spans := exporter.GetSpans().Snapshots()
+	require.Len(t, spans, 3)
+
+	create := spans[0]
+	assert.Equal(t, "gorm.create", create.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), create.Parent().SpanID())
+	assert.Equal(t, "sqlite", attr(create, "db.system.name"))
+	assert.Equal(t, "widgets", attr(create, "db.collection.name"))
+	assert.Contains(t, attr(create, "db.query.text"), "INSERT INTO `widgets`")
+	assert.NotContains(t, attr(create, "db.query.text"), "rahasia", "Nilai parameter tidak boleh masuk ke span")
+
+	failed := spans[1]
+	assert.Equal(t, codes.Error, failed.Status().Code)
+}
@@ -0,0 +1,124 @@
+// Package tracing menyiapkan OpenTelemetry tracing: exporter sesuai konfigurasi,
+// propagasi W3C traceparent dan plugin GORM untuk span per query.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+
+	"fullstack-crud-project-01/backend-go/config"
+)
+
+// InstrumentationName adalah nama tracer untuk span yang dibuat aplikasi ini
+const InstrumentationName = "fullstack-crud-project-01/backend-go"
+
+// defaultOTLPPath adalah path OTLP/HTTP untuk trace jika endpoint tidak menyertakan path
+const defaultOTLPPath = "/v1/traces"
+
+// ShutdownFunc mengirim span yang tersisa dan menutup exporter
+type ShutdownFunc func(ctx context.Context) error
+
+// Setup memasang propagator W3C (traceparent dan baggage) dan, jika exporter bukan "none",
+// TracerProvider global. Tanpa exporter, tracer global tetap no-op sehingga
+// instrumentasi tidak menambah biaya.
+func Setup(ctx context.Context, cfg config.TracingConfig, version string) (ShutdownFunc, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	exporter, closeExporter, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if exporter == nil {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(cfg.ServiceName),
+		semconv.ServiceVersion(version),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		// Ikuti keputusan sampling pemanggil (traceparent) agar trace lintas layanan utuh
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closeErr := closeExporter(); err == nil {
+			err = closeErr
+		}
+		return err
+	}, nil
+}
+
+// newExporter membuat exporter sesuai cfg.Exporter beserta fungsi untuk menutup sumber dayanya
+func newExporter(ctx context.Context, cfg config.TracingConfig) (sdktrace.SpanExporter, func() error, error) {
+	noClose := func() error { return nil }
+
+	switch cfg.Exporter {
+	case config.TracingStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
+		return exporter, noClose, err
+
+	case config.TracingFile:
+		file, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("gagal membuka file trace: %w", err)
+		}
+		// Satu span per baris (JSON Lines)
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			file.Close()
+			return nil, nil, err
+		}
+		return exporter, file.Close, nil
+
+	case config.TracingOTLP:
+		var opts []otlptracehttp.Option
+		if cfg.OTLPEndpoint != "" {
+			endpoint, err := otlpEndpointURL(cfg.OTLPEndpoint)
+			if err != nil {
+				return nil, nil, err
+			}
+			opts = append(opts, otlptracehttp.WithEndpointURL(endpoint))
+		}
+		exporter, err := otlptracehttp.New(ctx, opts...)
+		return exporter, noClose, err
+
+	default:
+		return nil, noClose, nil
+	}
+}
+
+// otlpEndpointURL menambahkan path /v1/traces jika URL collector hanya berisi host
+func otlpEndpointURL(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("TRACING_OTLP_ENDPOINT tidak valid: %w", err)
+	}
+	if strings.Trim(u.Path, "/") == "" {
+		u.Path = defaultOTLPPath
+	}
+	return u.String(), nil
+}
@@ -0,0 +1,61 @@
+package tracing_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"fullstack-crud-project-01/backend-go/config"
+	"fullstack-crud-project-01/backend-go/tracing"
+)
+
+// installProvider memasang TracerProvider global selama tes lalu menggantinya dengan no-op
+func installProvider(t *testing.T, provider trace.TracerProvider) {
+	t.Helper()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+}
+
+func TestSetup_FileExporterAndPropagation(t *testing.T) {
+	installProvider(t, noop.NewTracerProvider())
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+
+	shutdown, err := tracing.Setup(context.Background(), config.TracingConfig{
+		Exporter:    config.TracingFile,
+		File:        path,
+		SampleRatio: 1,
+		ServiceName: "backend-test",
+	}, "v1.2.3")
+	require.NoError(t, err)
+
+	// traceparent dari pemanggil dilanjutkan oleh span baru
+	carrier := propagation.MapCarrier{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), carrier)
+	_, span := otel.Tracer("test").Start(ctx, "product.create")
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+	span.End()
+
+	require.NoError(t, shutdown(context.Background()))
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"Name":"product.create"`)
+	assert.Contains(t, string(content), "backend-test")
+}
+
+func TestSetup_NoneKeepsNoopProvider(t *testing.T) {
+	provider := noop.NewTracerProvider()
+	installProvider(t, provider)
+
+	shutdown, err := tracing.Setup(context.Background(), config.TracingConfig{Exporter: config.TracingNone}, "dev")
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+	assert.Equal(t, provider, otel.GetTracerProvider(), "Exporter none tidak boleh mengganti TracerProvider")
+}
@@ -0,0 +1,290 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwtValidMethods adalah algoritma yang diterima saat validasi token
+var jwtValidMethods = []string{
+	jwt.SigningMethodHS256.Alg(), jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg(),
+}
+
+// JWK adalah kunci publik dalam format JSON Web Key (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// jwtKey adalah kunci asimetris di keyset JWT. kid adalah thumbprint JWK (RFC 7638),
+// sehingga semua replika menghasilkan kid yang sama dari file kunci yang sama.
+type jwtKey struct {
+	jwk     JWK
+	method  jwt.SigningMethod
+	private crypto.Signer // nil untuk kunci yang hanya dipakai verifikasi
+	public  crypto.PublicKey
+}
+
+// ConfigureJWTKeys mengaktifkan penandatanganan asimetris. signingKeyFile adalah kunci
+// privat PEM (RSA minimal 2048 bit → RS256, Ed25519 → EdDSA) untuk token baru;
+// verificationKeyFiles adalah kunci publik (atau privat) PEM lain yang tetap diterima dan
+// dipublikasikan di JWKS, untuk rotasi tanpa memutus sesi. Tanpa signingKeyFile token
+// ditandatangani HS256 dengan secret dari ConfigureJWT.
+func ConfigureJWTKeys(signingKeyFile string, verificationKeyFiles []string) error {
+	var signing *jwtKey
+	keys := map[string]*jwtKey{}
+	if signingKeyFile != "" {
+		key, err := loadJWTKey(signingKeyFile)
+		if err != nil {
+			return err
+		}
+		if key.private == nil {
+			return fmt.Errorf("JWT_SIGNING_KEY_FILE %s: berisi kunci publik, bukan kunci privat", signingKeyFile)
+		}
+		signing = key
+		keys[key.jwk.Kid] = key
+	}
+	for _, path := range verificationKeyFiles {
+		key, err := loadJWTKey(path)
+		if err != nil {
+			return err
+		}
+		if _, exists := keys[key.jwk.Kid]; !exists {
+			key.private = nil // Kunci verifikasi tidak pernah dipakai untuk menandatangani
+			keys[key.jwk.Kid] = key
+		}
+	}
+
+	var internal []byte
+	if signing != nil {
+		der, err := x509.MarshalPKCS8PrivateKey(signing.private)
+		if err != nil {
+			return fmt.Errorf("JWT_SIGNING_KEY_FILE %s: %w", signingKeyFile, err)
+		}
+		internal = deriveInternalKey(der)
+	}
+
+	jwtSettings.Lock()
+	defer jwtSettings.Unlock()
+	jwtSettings.signing = signing
+	jwtSettings.keys = keys
+	jwtSettings.internal = internal
+	return nil
+}
+
+// JWKS mengembalikan kunci publik yang dipakai untuk memverifikasi token, kunci aktif
+// lebih dulu. Kosong jika token ditandatangani HS256 (secret tidak boleh dipublikasikan).
+func JWKS() []JWK {
+	jwtSettings.RLock()
+	defer jwtSettings.RUnlock()
+
+	jwks := make([]JWK, 0, len(jwtSettings.keys))
+	for _, key := range jwtSettings.keys {
+		if key != jwtSettings.signing {
+			jwks = append(jwks, key.jwk)
+		}
+	}
+	sort.Slice(jwks, func(i, j int) bool { return jwks[i].Kid < jwks[j].Kid })
+	if jwtSettings.signing != nil {
+		jwks = append([]JWK{jwtSettings.signing.jwk}, jwks...)
+	}
+	return jwks
+}
+
+// signToken menandatangani claims dengan kunci asimetris aktif (beserta header kid), atau
+// HS256 dengan secret jika belum ada kunci asimetris
+func signToken(claims jwt.Claims) (string, error) {
+	jwtSettings.RLock()
+	key := jwtSettings.signing
+	jwtSettings.RUnlock()
+
+	var token *jwt.Token
+	var signingKey interface{}
+	if key != nil {
+		token = jwt.NewWithClaims(key.method, claims)
+		token.Header["kid"] = key.jwk.Kid
+		signingKey = key.private
+	} else {
+		secret, err := jwtSecret()
+		if err != nil {
+			return "", err
+		}
+		token = jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		signingKey = secret
+	}
+
+	tokenString, err := token.SignedString(signingKey)
+	if err != nil {
+		return "", fmt.Errorf("gagal menandatangani token: %w", err)
+	}
+	return tokenString, nil
+}
+
+// signInternalToken menandatangani token yang hanya dibaca server ini sendiri (tantangan
+// MFA, alur OIDC) dengan HS256 dan kunci internal. Kunci ini tidak pernah dipublikasikan,
+// sehingga verifier JWKS tidak bisa menerima token tersebut.
+func signInternalToken(claims jwt.Claims) (string, error) {
+	key, err := internalKey()
+	if err != nil {
+		return "", err
+	}
+	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(key)
+	if err != nil {
+		return "", fmt.Errorf("gagal menandatangani token: %w", err)
+	}
+	return tokenString, nil
+}
+
+// parseInternalToken memverifikasi token dari signInternalToken beserta audience-nya
+func parseInternalToken(tokenString string, claims jwt.Claims, audience string) error {
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(*jwt.Token) (interface{}, error) {
+		return internalKey()
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithAudience(audience))
+	if err != nil {
+		return fmt.Errorf("token tidak valid: %w", err)
+	}
+	return nil
+}
+
+// internalKey mengembalikan kunci HMAC token internal: diturunkan dari kunci privat aktif,
+// atau dari secret HS256 jika belum ada kunci asimetris. Semua replika dengan konfigurasi
+// yang sama mendapat kunci yang sama.
+func internalKey() ([]byte, error) {
+	jwtSettings.RLock()
+	key := jwtSettings.internal
+	jwtSettings.RUnlock()
+	if key != nil {
+		return key, nil
+	}
+	secret, err := jwtSecret()
+	if err != nil {
+		return nil, err
+	}
+	return deriveInternalKey(secret), nil
+}
+
+// deriveInternalKey menurunkan kunci terpisah dari material kunci (HMAC dengan label), agar
+// token internal tidak bisa diverifikasi dengan secret access token dan sebaliknya
+func deriveInternalKey(material []byte) []byte {
+	mac := hmac.New(sha256.New, material)
+	mac.Write([]byte("go-backend-auth internal token"))
+	return mac.Sum(nil)
+}
+
+// verificationKey memilih kunci verifikasi dari header kid. Token tanpa kid diverifikasi
+// dengan secret HS256, sehingga token lama tetap berlaku selama secret masih diatur.
+func verificationKey(token *jwt.Token) (interface{}, error) {
+	if kid, ok := token.Header["kid"].(string); ok {
+		jwtSettings.RLock()
+		key := jwtSettings.keys[kid]
+		jwtSettings.RUnlock()
+		if key == nil {
+			return nil, fmt.Errorf("kid %q tidak dikenal", kid)
+		}
+		// alg wajib sesuai tipe kunci (mencegah algorithm confusion)
+		if token.Method.Alg() != key.method.Alg() {
+			return nil, fmt.Errorf("metode penandatanganan tidak valid: %v", token.Header["alg"])
+		}
+		return key.public, nil
+	}
+
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		return nil, fmt.Errorf("metode penandatanganan tidak valid: %v", token.Header["alg"])
+	}
+	return jwtSecret()
+}
+
+// loadJWTKey membaca kunci PEM: PKCS#8 ("PRIVATE KEY"), PKCS#1 ("RSA PRIVATE KEY") atau
+// PKIX ("PUBLIC KEY")
+func loadJWTKey(path string) (*jwtKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca kunci JWT: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("kunci JWT %s bukan file PEM", path)
+	}
+
+	var private crypto.Signer
+	var public crypto.PublicKey
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, parseErr := x509.ParsePKCS8PrivateKey(block.Bytes)
+		signer, ok := parsed.(crypto.Signer)
+		if parseErr == nil && !ok {
+			parseErr = fmt.Errorf("tipe kunci %T tidak didukung", parsed)
+		}
+		private, err = signer, parseErr
+	case "RSA PRIVATE KEY":
+		private, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		public, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		err = fmt.Errorf("blok PEM %q tidak didukung", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("kunci JWT %s tidak valid: %w", path, err)
+	}
+	if private != nil {
+		public = private.Public()
+	}
+
+	key, err := newJWTKey(public)
+	if err != nil {
+		return nil, fmt.Errorf("kunci JWT %s: %w", path, err)
+	}
+	key.private = private
+	return key, nil
+}
+
+// newJWTKey menentukan algoritma dari tipe kunci dan menyusun JWK beserta kid-nya
+func newJWTKey(public crypto.PublicKey) (*jwtKey, error) {
+	key := &jwtKey{public: public}
+	// Anggota wajib JWK untuk thumbprint RFC 7638, urut leksikografis. %q cukup sebagai
+	// encoding JSON karena nilai base64url hanya berisi karakter ASCII biasa.
+	var members string
+	switch pub := public.(type) {
+	case *rsa.PublicKey:
+		if pub.N.BitLen() < 2048 {
+			return nil, fmt.Errorf("kunci RSA minimal 2048 bit, didapat %d", pub.N.BitLen())
+		}
+		key.method = jwt.SigningMethodRS256
+		key.jwk = JWK{
+			Kty: "RSA",
+			N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}
+		members = fmt.Sprintf(`{"e":%q,"kty":"RSA","n":%q}`, key.jwk.E, key.jwk.N)
+	case ed25519.PublicKey:
+		key.method = jwt.SigningMethodEdDSA
+		key.jwk = JWK{Kty: "OKP", Crv: "Ed25519", X: base64.RawURLEncoding.EncodeToString(pub)}
+		members = fmt.Sprintf(`{"crv":"Ed25519","kty":"OKP","x":%q}`, key.jwk.X)
+	default:
+		return nil, fmt.Errorf("tipe kunci %T tidak didukung (gunakan RSA atau Ed25519)", public)
+	}
+
+	sum := sha256.Sum256([]byte(members))
+	key.jwk.Kid = base64.RawURLEncoding.EncodeToString(sum[:])
+	key.jwk.Use = "sig"
+	key.jwk.Alg = key.method.Alg()
+	return key, nil
+}
@@ -0,0 +1,240 @@
+package utils_test
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"fullstack-crud-project-01/backend-go/utils"
+)
+
+// writeKeyPair menyimpan kunci privat (PKCS#8) dan publik (PKIX) sebagai file PEM
+func writeKeyPair(t *testing.T, name string, private crypto.Signer) (privatePath, publicPath string) {
+	t.Helper()
+	dir := t.TempDir()
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+	publicDER, err := x509.MarshalPKIXPublicKey(private.Public())
+	require.NoError(t, err)
+
+	privatePath = filepath.Join(dir, name+".pem")
+	publicPath = filepath.Join(dir, name+".pub.pem")
+	require.NoError(t, os.WriteFile(privatePath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}), 0o600))
+	require.NoError(t, os.WriteFile(publicPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0o600))
+	return privatePath, publicPath
+}
+
+func newEd25519Key(t *testing.T) ed25519.PrivateKey {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return private
+}
+
+// resetJWTKeys kembali ke HS256 setelah tes (keyset bersifat global)
+func resetJWTKeys(t *testing.T) {
+	t.Cleanup(func() { require.NoError(t, utils.ConfigureJWTKeys("", nil)) })
+}
+
+func TestJWTKeys_SignAndVerify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		key  crypto.Signer
+		alg  string
+	}{
+		{"RSA", rsaKey, "RS256"},
+		{"Ed25519", newEd25519Key(t), "EdDSA"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetJWTKeys(t)
+			t.Setenv("JWT_SECRET_KEY", "")
+			privatePath, _ := writeKeyPair(t, "jwt", tt.key)
+			require.NoError(t, utils.ConfigureJWTKeys(privatePath, nil))
+
+			jwks := utils.JWKS()
+			require.Len(t, jwks, 1)
+			assert.Equal(t, tt.alg, jwks[0].Alg)
+			assert.Equal(t, "sig", jwks[0].Use)
+
+			token, err := utils.GenerateSessionToken(5, "rs@example.com", "admin", "sesi-test")
+			require.NoError(t, err, "Tidak butuh JWT_SECRET_KEY")
+			claims, err := utils.ValidateToken(token)
+			require.NoError(t, err)
+			assert.Equal(t, uint(5), claims.UserID)
+
+			// Layanan lain cukup memakai kunci publik dari JWKS
+			parsed, err := jwt.ParseWithClaims(token, &utils.CustomClaims{}, func(token *jwt.Token) (interface{}, error) {
+				assert.Equal(t, jwks[0].Kid, token.Header["kid"])
+				return tt.key.Public(), nil
+			}, jwt.WithValidMethods([]string{tt.alg}))
+			require.NoError(t, err)
+			assert.Equal(t, "rs@example.com", parsed.Claims.(*utils.CustomClaims).Email)
+
+			mfaToken, err := utils.GenerateMFAToken(5, "rs@example.com")
+			require.NoError(t, err)
+			_, err = utils.ValidateMFAToken(mfaToken)
+			assert.NoError(t, err)
+			_, err = utils.ValidateToken(mfaToken)
+			assert.Error(t, err, "Audience tetap dibedakan")
+		})
+	}
+}
+
+func TestJWTKeys_Ed25519Thumbprint(t *testing.T) {
+	resetJWTKeys(t)
+	// Contoh kunci dari RFC 8037 lampiran A.3
+	x, err := base64.RawURLEncoding.DecodeString("11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo")
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(ed25519.PublicKey(x))
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "rfc8037.pub.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+
+	require.NoError(t, utils.ConfigureJWTKeys("", []string{path}))
+	jwks := utils.JWKS()
+	require.Len(t, jwks, 1)
+	assert.Equal(t, "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k", jwks[0].Kid)
+	assert.Equal(t, "OKP", jwks[0].Kty)
+	assert.Equal(t, "Ed25519", jwks[0].Crv)
+}
+
+func TestJWTKeys_Rotation(t *testing.T) {
+	resetJWTKeys(t)
+	t.Setenv("JWT_SECRET_KEY", "")
+	oldPrivate, oldPublic := writeKeyPair(t, "lama", newEd25519Key(t))
+	newPrivate, newPublic := writeKeyPair(t, "baru", newEd25519Key(t))
+
+	// 1. Kunci baru dipublikasikan dulu, penandatanganan masih dengan kunci lama
+	require.NoError(t, utils.ConfigureJWTKeys(oldPrivate, []string{newPublic}))
+	jwks := utils.JWKS()
+	require.Len(t, jwks, 2)
+	oldKid, newKid := jwks[0].Kid, jwks[1].Kid
+	oldToken, err := utils.GenerateSessionToken(1, "lama@example.com", "user", "sesi-test")
+	require.NoError(t, err)
+
+	// 2. Beralih ke kunci baru; token lama tetap berlaku
+	require.NoError(t, utils.ConfigureJWTKeys(newPrivate, []string{oldPublic}))
+	assert.Equal(t, []string{newKid, oldKid}, []string{utils.JWKS()[0].Kid, utils.JWKS()[1].Kid})
+	newToken, err := utils.GenerateSessionToken(2, "baru@example.com", "user", "sesi-test")
+	require.NoError(t, err)
+	_, err = utils.ValidateToken(oldToken)
+	assert.NoError(t, err)
+	_, err = utils.ValidateToken(newToken)
+	assert.NoError(t, err)
+
+	// 3. Kunci lama dihapus setelah JWT_TTL berlalu
+	require.NoError(t, utils.ConfigureJWTKeys(newPrivate, nil))
+	_, err = utils.ValidateToken(oldToken)
+	assert.Error(t, err, "kid yang sudah dihapus")
+	_, err = utils.ValidateToken(newToken)
+	assert.NoError(t, err)
+}
+
+func TestJWTKeys_MigrationFromHS256(t *testing.T) {
+	resetJWTKeys(t)
+	t.Setenv("JWT_SECRET_KEY", "secret-lama")
+	hsToken, err := utils.GenerateSessionToken(1, "hs@example.com", "user", "sesi-test")
+	require.NoError(t, err)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	privatePath, publicPath := writeKeyPair(t, "rsa", rsaKey)
+	require.NoError(t, utils.ConfigureJWTKeys(privatePath, nil))
+
+	// Token HS256 (tanpa kid) tetap diterima selama secret masih diatur
+	_, err = utils.ValidateToken(hsToken)
+	assert.NoError(t, err)
+
+	// Algorithm confusion: HS256 dengan kid RSA dan kunci publik sebagai secret ditolak
+	publicPEM, err := os.ReadFile(publicPath)
+	require.NoError(t, err)
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, &utils.CustomClaims{UserID: 1, Role: "admin"})
+	forged.Header["kid"] = utils.JWKS()[0].Kid
+	forgedString, err := forged.SignedString(publicPEM)
+	require.NoError(t, err)
+	_, err = utils.ValidateToken(forgedString)
+	assert.Error(t, err)
+
+	t.Setenv("JWT_SECRET_KEY", "")
+	_, err = utils.ValidateToken(hsToken)
+	assert.Error(t, err, "Tanpa secret, token HS256 tidak diterima lagi")
+}
+
+func TestConfigureJWTKeys_InvalidKeys(t *testing.T) {
+	resetJWTKeys(t)
+	dir := t.TempDir()
+	_, publicPath := writeKeyPair(t, "ed", newEd25519Key(t))
+
+	weakKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	weakPath := filepath.Join(dir, "weak.pem")
+	require.NoError(t, os.WriteFile(weakPath, pem.EncodeToMemory(&pem.Block{
+		Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(weakKey),
+	}), 0o600))
+	notPEM := filepath.Join(dir, "kunci.txt")
+	require.NoError(t, os.WriteFile(notPEM, []byte("bukan kunci"), 0o600))
+
+	assert.ErrorContains(t, utils.ConfigureJWTKeys(publicPath, nil), "bukan kunci privat")
+	assert.ErrorContains(t, utils.ConfigureJWTKeys(weakPath, nil), "minimal 2048 bit")
+	assert.ErrorContains(t, utils.ConfigureJWTKeys("", []string{notPEM}), "bukan file PEM")
+	assert.Error(t, utils.ConfigureJWTKeys(filepath.Join(dir, "tidak-ada.pem"), nil))
+}
+
+func TestJWTKeys_InternalTokensUseUnpublishedKey(t *testing.T) {
+	resetJWTKeys(t)
+	t.Setenv("JWT_SECRET_KEY", "secret-hs256")
+
+	// Token MFA yang ditandatangani dengan secret access token (seperti sebelumnya) ditolak
+	legacy := jwt.NewWithClaims(jwt.SigningMethodHS256, &utils.CustomClaims{UserID: 1, RegisteredClaims: jwt.RegisteredClaims{
+		Audience: jwt.ClaimStrings{"mfa"},
+	}})
+	legacyString, err := legacy.SignedString([]byte("secret-hs256"))
+	require.NoError(t, err)
+	_, err = utils.ValidateMFAToken(legacyString)
+	assert.Error(t, err, "Token internal tidak boleh memakai secret access token")
+
+	privatePath, _ := writeKeyPair(t, "ed", newEd25519Key(t))
+	require.NoError(t, utils.ConfigureJWTKeys(privatePath, nil))
+	public := utils.JWKS()[0]
+
+	for name, generate := range map[string]func() (string, error){
+		"MFA":      func() (string, error) { return utils.GenerateMFAToken(1, "mfa@example.com") },
+		"OIDCFlow": func() (string, error) { return utils.GenerateOIDCFlowToken("state", "nonce", "verifier") },
+	} {
+		t.Run(name, func(t *testing.T) {
+			tokenString, err := generate()
+			require.NoError(t, err)
+
+			// Tidak ada kid dan bukan algoritma kunci JWKS: verifier eksternal tidak bisa menerimanya
+			token, _, err := jwt.NewParser().ParseUnverified(tokenString, jwt.MapClaims{})
+			require.NoError(t, err)
+			assert.Equal(t, "HS256", token.Method.Alg())
+			assert.NotContains(t, token.Header, "kid")
+			assert.NotEqual(t, public.Alg, token.Method.Alg())
+
+			_, err = utils.ValidateToken(tokenString)
+			assert.Error(t, err)
+		})
+	}
+
+	// Access token membawa audience yang wajib diperiksa verifier JWKS
+	accessToken, err := utils.GenerateSessionToken(1, "at@example.com", "user", "sesi-test")
+	require.NoError(t, err)
+	claims, err := utils.ValidateToken(accessToken)
+	require.NoError(t, err)
+	assert.Equal(t, jwt.ClaimStrings{"go-backend-api"}, claims.Audience)
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"os"
+	"sync"
 	"time"
     "fmt" // Import fmt untuk formatting error
 
@@ -13,61 +14,175 @@ import (
 type CustomClaims struct {
 	UserID uint   `json:"user_id"`
 	Email  string `json:"email"`
+	Role   string `json:"role,omitempty"`
 	jwt.RegisteredClaims
 }
 
-// GenerateToken membuat JWT baru untuk pengguna yang berhasil login.
-func GenerateToken(userID uint, email string) (string, error) {
-	jwtSecret := os.Getenv("JWT_SECRET_KEY")
+// accessAudience adalah audience access token. Verifier lain (mis. layanan yang memakai
+// JWKS) wajib memeriksanya agar hanya access token yang diterima.
+const accessAudience = "go-backend-api"
 
-	// Pastikan Secret Key sudah diatur
-	if jwtSecret == "" {
-		return "", fmt.Errorf("JWT_SECRET_KEY tidak diatur dalam environment variables")
+// mfaAudience menandai token tantangan MFA agar tidak bisa dipakai sebagai access token
+const mfaAudience = "mfa"
+
+// MFATokenTTL adalah masa berlaku token tantangan MFA antara langkah password dan kode TOTP
+const MFATokenTTL = 5 * time.Minute
+
+// oidcFlowAudience menandai token alur login OIDC agar tidak bisa dipakai sebagai access token
+const oidcFlowAudience = "oidc_flow"
+
+// OIDCFlowTTL adalah batas waktu pengguna menyelesaikan login di penyedia identitas
+const OIDCFlowTTL = 10 * time.Minute
+
+// OIDCFlowClaims menyimpan data alur login OIDC (authorization code + PKCE) yang harus
+// dicocokkan saat callback. Token ini disimpan di cookie HttpOnly milik browser pengguna.
+type OIDCFlowClaims struct {
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"` // PKCE code_verifier
+	jwt.RegisteredClaims
+}
+
+// jwtSettings menyimpan secret, keyset asimetris dan masa berlaku token dari konfigurasi aplikasi
+var jwtSettings struct {
+	sync.RWMutex
+	secret  []byte
+	ttl     time.Duration
+	signing  *jwtKey            // Kunci aktif dari ConfigureJWTKeys; nil berarti HS256
+	keys     map[string]*jwtKey // Semua kunci verifikasi per kid, termasuk kunci aktif
+	internal []byte             // Kunci HMAC token internal dari kunci privat aktif; nil berarti diturunkan dari secret
+}
+
+// ConfigureJWT mengatur secret dan masa berlaku default token. Dipanggil sekali saat
+// startup dari config.Config; tanpa ini, JWT_SECRET_KEY dibaca dari environment.
+func ConfigureJWT(secret string, ttl time.Duration) {
+	jwtSettings.Lock()
+	defer jwtSettings.Unlock()
+	jwtSettings.secret = []byte(secret)
+	jwtSettings.ttl = ttl
+}
+
+// jwtSecret mengembalikan secret yang dikonfigurasi, atau JWT_SECRET_KEY sebagai fallback
+func jwtSecret() ([]byte, error) {
+	jwtSettings.RLock()
+	secret := jwtSettings.secret
+	jwtSettings.RUnlock()
+	if len(secret) > 0 {
+		return secret, nil
+	}
+	if env := os.Getenv("JWT_SECRET_KEY"); env != "" {
+		return []byte(env), nil
 	}
+	return nil, fmt.Errorf("JWT_SECRET_KEY tidak diatur")
+}
 
-	// 1. Definisikan waktu kedaluwarsa (misalnya 1 jam dari sekarang)
-	expirationTime := time.Now().Add(time.Hour * 1).Unix()
+// TokenTTL mengembalikan masa berlaku default access token (1 jam jika belum dikonfigurasi)
+func TokenTTL() time.Duration {
+	jwtSettings.RLock()
+	defer jwtSettings.RUnlock()
+	if jwtSettings.ttl > 0 {
+		return jwtSettings.ttl
+	}
+	return time.Hour * 1
+}
+
+// GenerateSessionToken membuat access token untuk sesi login. sessionID disimpan sebagai
+// claim jti agar token bisa dicabut bersama sesinya.
+func GenerateSessionToken(userID uint, email, role, sessionID string) (string, error) {
+	return GenerateSessionTokenWithTTL(userID, email, role, sessionID, TokenTTL())
+}
+
+// GenerateSessionTokenWithTTL seperti GenerateSessionToken dengan masa berlaku kustom
+// (mis. token debug dari CLI); ttl harus sama dengan masa berlaku sesinya. sessionID kosong
+// (AuthService tanpa SessionService) berarti token tanpa claim jti.
+func GenerateSessionTokenWithTTL(userID uint, email, role, sessionID string, ttl time.Duration) (string, error) {
+	// 1. Definisikan waktu kedaluwarsa (default dari JWT_TTL, 1 jam)
+	expirationTime := time.Now().Add(ttl).Unix()
 	
 	// 2. Definisikan claims
 	claims := &CustomClaims{
 		UserID: userID,
 		Email:  email,
+		Role:   role,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        sessionID,
+			Audience:  jwt.ClaimStrings{accessAudience},
 			ExpiresAt: jwt.NewNumericDate(time.Unix(expirationTime, 0)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			Issuer:    "go-backend-auth", // Opsional: Tanda pengenal penerbit token
 		},
 	}
 
-	// 3. Buat token baru dengan algoritma HS256
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	// 3. Tandatangani token (HS256 dengan secret key, atau RS256/EdDSA dengan kunci aktif)
+	return signToken(claims)
+}
 
-	// 4. Tandatangani token dengan secret key
-	tokenString, err := token.SignedString([]byte(jwtSecret))
-	if err != nil {
-		return "", fmt.Errorf("gagal menandatangani token: %w", err)
+// GenerateMFAToken membuat token tantangan MFA setelah password terverifikasi. Token ini
+// hanya diterima oleh ValidateMFAToken (langkah kedua login), bukan oleh AuthMiddleware,
+// dan ditandatangani dengan kunci internal yang tidak dipublikasikan di JWKS.
+func GenerateMFAToken(userID uint, email string) (string, error) {
+	now := time.Now()
+	claims := &CustomClaims{
+		UserID: userID,
+		Email:  email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{mfaAudience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(MFATokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    "go-backend-auth",
+		},
+	}
+	return signInternalToken(claims)
+}
+
+// ValidateMFAToken memverifikasi token tantangan MFA dari GenerateMFAToken
+func ValidateMFAToken(tokenString string) (*CustomClaims, error) {
+	claims := &CustomClaims{}
+	if err := parseInternalToken(tokenString, claims, mfaAudience); err != nil {
+		return nil, err
 	}
+	return claims, nil
+}
 
-	return tokenString, nil
+// GenerateOIDCFlowToken menandatangani data alur login OIDC sebelum redirect ke penyedia
+// identitas, dengan kunci internal karena token ini membawa PKCE verifier
+func GenerateOIDCFlowToken(state, nonce, verifier string) (string, error) {
+	now := time.Now()
+	claims := &OIDCFlowClaims{
+		State:    state,
+		Nonce:    nonce,
+		Verifier: verifier,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{oidcFlowAudience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(OIDCFlowTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    "go-backend-auth",
+		},
+	}
+	return signInternalToken(claims)
+}
+
+// ValidateOIDCFlowToken memverifikasi token alur login OIDC dari GenerateOIDCFlowToken
+func ValidateOIDCFlowToken(tokenString string) (*OIDCFlowClaims, error) {
+	claims := &OIDCFlowClaims{}
+	if err := parseInternalToken(tokenString, claims, oidcFlowAudience); err != nil {
+		return nil, err
+	}
+	return claims, nil
 }
 
 // ValidateToken memverifikasi token JWT.
-// Fungsi ini digunakan di Middleware pada Langkah 3.
+// Fungsi ini digunakan di Middleware pada Langkah 3. Hanya token dengan audience access
+// token yang diterima; token tantangan MFA dan alur OIDC selalu ditolak.
 func ValidateToken(tokenString string) (*CustomClaims, error) {
-    jwtSecret := os.Getenv("JWT_SECRET_KEY")
-
-    if jwtSecret == "" {
-        return nil, fmt.Errorf("JWT_SECRET_KEY tidak diatur")
-    }
-
-	token, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, func(token *jwt.Token) (interface{}, error) {
-		// Pastikan algoritma yang digunakan sama dengan saat pembuatan
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("metode penandatanganan tidak valid: %v", token.Header["alg"])
-		}
-		// Mengembalikan secret key
-		return []byte(jwtSecret), nil
-	})
+	return parseToken(tokenString, jwt.WithAudience(accessAudience))
+}
+
+// parseToken memverifikasi signature dan masa berlaku token beserta opsi parser tambahan
+func parseToken(tokenString string, opts ...jwt.ParserOption) (*CustomClaims, error) {
+	// Kunci dipilih dari header kid (asimetris) atau secret HS256 untuk token tanpa kid
+	opts = append(opts, jwt.WithValidMethods(jwtValidMethods))
+	token, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, verificationKey, opts...)
 
 	if err != nil {
 		// Ini akan menangani error umum seperti token kedaluwarsa atau signature tidak valid
@@ -3,8 +3,10 @@ package utils_test
 import (
 	"os"
 	"testing"
+	"time"
 
 	"fullstack-crud-project-01/backend-go/utils"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -17,7 +19,7 @@ func TestGenerateAndValidateToken(t *testing.T) {
 	email := "test@example.com"
 
 	// 1. Test Token Generation
-	tokenString, err := utils.GenerateToken(userID, email)
+	tokenString, err := utils.GenerateSessionToken(userID, email, "user", "sesi-test")
 	assert.NoError(t, err, "Token generation should not produce an error")
 	assert.NotEmpty(t, tokenString, "Generated token string should not be empty")
 
@@ -35,6 +37,61 @@ func TestGenerateAndValidateToken(t *testing.T) {
 
 	// 4. Test with empty secret
 	os.Unsetenv("JWT_SECRET_KEY")
-	_, err = utils.GenerateToken(userID, email)
+	_, err = utils.GenerateSessionToken(userID, email, "user", "sesi-test")
 	assert.Error(t, err, "Generation should fail if JWT_SECRET_KEY is not set")
-}
\ No newline at end of file
+}
+func TestMFAToken_IsNotAccessToken(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", "my-super-secret-key-for-testing")
+
+	mfaToken, err := utils.GenerateMFAToken(7, "mfa@example.com")
+	assert.NoError(t, err)
+	claims, err := utils.ValidateMFAToken(mfaToken)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(7), claims.UserID)
+
+	_, err = utils.ValidateToken(mfaToken)
+	assert.Error(t, err, "Token MFA tidak boleh diterima sebagai access token")
+
+	accessToken, err := utils.GenerateSessionToken(7, "mfa@example.com", "user", "sesi-test")
+	assert.NoError(t, err)
+	_, err = utils.ValidateMFAToken(accessToken)
+	assert.Error(t, err, "Access token tidak boleh dipakai sebagai token MFA")
+}
+
+func TestOIDCFlowToken(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", "my-super-secret-key-for-testing")
+
+	flowToken, err := utils.GenerateOIDCFlowToken("state-1", "nonce-1", "verifier-1")
+	assert.NoError(t, err)
+	claims, err := utils.ValidateOIDCFlowToken(flowToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "state-1", claims.State)
+	assert.Equal(t, "nonce-1", claims.Nonce)
+	assert.Equal(t, "verifier-1", claims.Verifier)
+
+	_, err = utils.ValidateToken(flowToken)
+	assert.Error(t, err, "Token alur OIDC tidak boleh diterima sebagai access token")
+
+	mfaToken, err := utils.GenerateMFAToken(7, "mfa@example.com")
+	assert.NoError(t, err)
+	_, err = utils.ValidateOIDCFlowToken(mfaToken)
+	assert.Error(t, err, "Token MFA tidak boleh dipakai sebagai token alur OIDC")
+}
+
+func TestValidateToken_RequiresAccessAudience(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", "my-super-secret-key-for-testing")
+
+	// Token bertanda tangan sah tetapi tanpa audience access token ditolak
+	for name, audience := range map[string]jwt.ClaimStrings{"NoAudience": nil, "OtherAudience": {"layanan-lain"}} {
+		t.Run(name, func(t *testing.T) {
+			token := jwt.NewWithClaims(jwt.SigningMethodHS256, &utils.CustomClaims{UserID: 1, RegisteredClaims: jwt.RegisteredClaims{
+				Audience:  audience,
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			}})
+			tokenString, err := token.SignedString([]byte("my-super-secret-key-for-testing"))
+			assert.NoError(t, err)
+			_, err = utils.ValidateToken(tokenString)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -94,4 +94,24 @@ export const deleteProduct = async (id: number, token: string): Promise<void> =>
         console.error(`Error deleting product ID ${id}:`, error);
         throw error;
     }
-};
\ No newline at end of file
+};
+
+// --- SERVICE SSE (PERUBAHAN PRODUK) ---
+const PRODUCT_EVENT_TYPES = ['product.created', 'product.updated', 'product.deleted', 'reset'];
+
+/**
+ * Berlangganan perubahan produk melalui Server-Sent Events.
+ * EventSource tidak bisa mengirim header Authorization, jadi token dikirim via ?token=.
+ * Browser otomatis tersambung ulang dan melanjutkan stream dengan header Last-Event-ID.
+ * @returns Fungsi untuk menutup stream, atau no-op jika EventSource tidak tersedia.
+ */
+export const subscribeProductEvents = (token: string, onChange: (eventType: string) => void): (() => void) => {
+    if (typeof EventSource === 'undefined') {
+        return () => {};
+    }
+    const source = new EventSource(`${API_BASE_URL}/products/events?token=${encodeURIComponent(token)}`);
+    PRODUCT_EVENT_TYPES.forEach((eventType) => {
+        source.addEventListener(eventType, () => onChange(eventType));
+    });
+    return () => source.close();
+};
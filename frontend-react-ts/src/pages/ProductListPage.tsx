@@ -3,7 +3,7 @@
 import React, { useState, useEffect, useCallback } from 'react';
 import { useAuth } from '../../context/AuthContext';
 import type { Product } from '../types/Product';
-import { getAllProducts, deleteProduct } from '../api/productApi';
+import { getAllProducts, deleteProduct, subscribeProductEvents } from '../api/productApi';
 import ProductForm from '../components/organisms/ProductForm';
 import ProductItem from '../components/molecules/ProductItem';
 
@@ -45,6 +45,16 @@ const ProductListPage: React.FC = () => {
         fetchProducts();
     }, [fetchProducts]); // ✨ IMPROVEMENT: Gunakan fetchProducts sebagai dependensi
 
+    // Muat ulang daftar saat produk diubah pengguna lain (atau saat server meminta reset)
+    useEffect(() => {
+        if (!token) {
+            return;
+        }
+        return subscribeProductEvents(token, () => {
+            fetchProducts();
+        });
+    }, [token, fetchProducts]);
+
     const handleDelete = useCallback(async (id: number) => {
         if (!token) {
             setError("Sesi tidak valid. Silakan login kembali.");